@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"upload-backend/internal/server"
+)
+
+type exportedEvent struct {
+	ID         int64     `json:"id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	TenantID   string    `json:"tenant"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	FileID     string    `json:"file_id,omitempty"`
+	PeerIP     string    `json:"peer_ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	Result     string    `json:"result"`
+	Detail     string    `json:"detail,omitempty"`
+}
+
+// audit-export writes audit events as JSON lines, oldest first, for SIEM
+// ingestion. Times are RFC 3339.
+func main() {
+	tenant := flag.String("tenant", "", "only events of this tenant")
+	actor := flag.String("actor", "", "only events by this actor")
+	action := flag.String("action", "", "only events with this action")
+	fileID := flag.String("file", "", "only events for this file ID")
+	result := flag.String("result", "", "only events with this result")
+	since := flag.String("since", "", "only events at or after this time (RFC 3339)")
+	until := flag.String("until", "", "only events before this time (RFC 3339)")
+	out := flag.String("out", "", "output file (defaults to stdout)")
+	flag.Parse()
+
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		log.Fatalf("POSTGRES_DSN is required")
+	}
+
+	filter := server.AuditFilter{
+		TenantID:  *tenant,
+		Actor:     *actor,
+		Action:    *action,
+		FileID:    *fileID,
+		Result:    *result,
+		Ascending: true,
+		Limit:     1000,
+	}
+	var err error
+	if *since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, *since); err != nil {
+			log.Fatalf("invalid --since: %v", err)
+		}
+	}
+	if *until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, *until); err != nil {
+			log.Fatalf("invalid --until: %v", err)
+		}
+	}
+
+	db, err := server.NewUploadDB(dsn)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to PostgreSQL: %v", err)
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			log.Fatalf("❌ Failed to create %s: %v", *out, err)
+		}
+		defer w.Close()
+	}
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	exported := 0
+	for {
+		events, err := db.ListAuditEvents(filter)
+		if err != nil {
+			log.Fatalf("❌ Query failed after %d events: %v", exported, err)
+		}
+		for _, e := range events {
+			if err := enc.Encode(exportedEvent(*e)); err != nil {
+				log.Fatalf("❌ Write failed: %v", err)
+			}
+		}
+		exported += len(events)
+		if len(events) < filter.Limit {
+			break
+		}
+		filter.Cursor = events[len(events)-1].ID
+	}
+	if err := bw.Flush(); err != nil {
+		log.Fatalf("❌ Write failed: %v", err)
+	}
+	log.Printf("exported %d audit events", exported)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"upload-backend/internal/cdc"
+	pb "upload-backend/pb"
+)
+
+// findBatch is how many hashes are checked per FindMissingChunks call
+const findBatch = 10000
+
+// uploadCDC splits the file into content-defined chunks, uploads only the
+// chunks the server does not have from this user and commits the manifest
+func uploadCDC(ctx context.Context, client pb.FileUploadServiceClient, file *os.File, targetID, folder string) error {
+	// First pass: hash every chunk
+	var manifest []*pb.ManifestEntry
+	if err := eachChunk(file, func(c cdc.Chunk) error {
+		sum := sha256.Sum256(c.Data)
+		manifest = append(manifest, &pb.ManifestEntry{Hash: hex.EncodeToString(sum[:]), Size: int64(len(c.Data))})
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	missing := make(map[string]bool)
+	for start := 0; start < len(manifest); start += findBatch {
+		end := min(start+findBatch, len(manifest))
+		req := &pb.FindMissingChunksRequest{}
+		for _, e := range manifest[start:end] {
+			req.Hashes = append(req.Hashes, e.Hash)
+		}
+		resp, err := client.FindMissingChunks(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, h := range resp.Missing {
+			missing[h] = true
+		}
+	}
+	fmt.Printf("%d chunks, %d to upload\n", len(manifest), len(missing))
+
+	initResp, err := client.InitUpload(ctx, &pb.InitRequest{
+		FileName:     filepath.Base(file.Name()),
+		TotalChunks:  int64(len(manifest)),
+		TargetFileId: targetID,
+		FolderPath:   folder,
+	})
+	if err != nil {
+		return err
+	}
+	fileID := initResp.FileId
+	fmt.Println("Uploading file with ID:", fileID)
+
+	// Second pass: send the missing chunks
+	if len(missing) > 0 {
+		stream, err := client.PutChunks(ctx)
+		if err != nil {
+			return err
+		}
+		i := 0
+		if err := eachChunk(file, func(c cdc.Chunk) error {
+			hash := manifest[i].Hash
+			i++
+			if !missing[hash] {
+				return nil
+			}
+			delete(missing, hash)
+			return stream.Send(&pb.ContentChunk{Hash: hash, Content: c.Data})
+		}); err != nil {
+			return err
+		}
+		putResp, err := stream.CloseAndRecv()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Sent %d chunks, %d new to the server\n", putResp.Received, putResp.Stored)
+	}
+
+	statusResp, err := client.CommitManifest(ctx, &pb.CommitManifestRequest{FileId: fileID, Chunks: manifest})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Upload completed: %v, %s\n", statusResp.Success, statusResp.Message)
+	return nil
+}
+
+// eachChunk runs fn on every content-defined chunk of the file from the start
+func eachChunk(file *os.File, fn func(cdc.Chunk) error) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	chunker, err := cdc.NewChunker(file, cdc.DefaultOptions)
+	if err != nil {
+		return err
+	}
+	for {
+		c, err := chunker.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+}
@@ -1,43 +1,81 @@
 package main
 
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"upload-backend/internal/e2e"
+	pb "upload-backend/pb"
+)
+
 func main() {
-	// fileID := flag.String("id", "064b8a71-af67-4daa-b07e-e8da79ffe28d", "File ID to download")
-	// flag.Parse()
-
-	// if *fileID == "" {
-	// 	fmt.Println("Please provide --id=<file_id>")
-	// 	return
-	// }
-
-	// conn, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// defer conn.Close()
-
-	// client := pb.NewFileUploadServiceClient(conn)
-	// stream, err := client.DownloadFile(context.Background(), &pb.DownloadRequest{FileId: *fileID})
-	// if err != nil {
-	// 	panic(err)
-	// }
-
-	// outFile, err := os.Create(fmt.Sprintf("downloaded_%s.zip", *fileID))
-	// if err != nil {
-	// 	panic(err)
-	// }
-	// defer outFile.Close()
-
-	// for {
-	// 	chunk, err := stream.Recv()
-	// 	if err == io.EOF {
-	// 		break
-	// 	}
-	// 	if err != nil {
-	// 		panic(err)
-	// 	}
-	// 	outFile.Write(chunk.Content)
-	// }
-
-	// fmt.Println("✅ File downloaded successfully!")
-	// time.Sleep(1 * time.Second)
+	fileID := flag.String("id", "", "File ID to download")
+	serverAddr := flag.String("server", "localhost:50051", "gRPC server address")
+	out := flag.String("out", "", "output path (defaults to the stored file name)")
+	decryptKey := flag.String("decrypt-key", "", "path to the 32-byte key used with --encrypt-key on upload")
+	version := flag.Int64("version", 0, "version to download (defaults to the current one)")
+	flag.Parse()
+
+	if *fileID == "" {
+		fmt.Println("Please provide --id=<file_id>")
+		return
+	}
+
+	conn, err := grpc.NewClient(*serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+	client := pb.NewFileUploadServiceClient(conn)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer your-jwt-token")
+	resp, err := client.DownloadFile(ctx, &pb.DownloadRequest{FileId: *fileID, Version: *version})
+	if err != nil {
+		panic(err)
+	}
+
+	content := resp.Content
+	if resp.EncryptionScheme != "" {
+		if resp.EncryptionScheme != e2e.Scheme {
+			panic(fmt.Errorf("unsupported encryption scheme %q", resp.EncryptionScheme))
+		}
+		if *decryptKey == "" {
+			panic(fmt.Errorf("file is client-side encrypted (key id %s), pass --decrypt-key", resp.KeyId))
+		}
+		userKey, err := e2e.LoadKey(*decryptKey)
+		if err != nil {
+			panic(err)
+		}
+		contentKey, err := e2e.UnwrapContentKey(userKey, resp.WrappedKey)
+		if err != nil {
+			panic(err)
+		}
+		// Chunks are bound to the upload that first stored the content
+		contentID := *fileID
+		if resp.VersionId != "" {
+			contentID = resp.VersionId
+		}
+		var plain bytes.Buffer
+		if err := e2e.OpenStream(contentKey, contentID, bytes.NewReader(resp.Content), &plain); err != nil {
+			panic(err)
+		}
+		content = plain.Bytes()
+	}
+
+	path := *out
+	if path == "" {
+		path = filepath.Base(resp.FileName)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("✅ Downloaded %s (%d bytes)\n", path, len(content))
 }
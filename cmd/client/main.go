@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"io"
@@ -9,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/google/uuid"
+	"upload-backend/internal/e2e"
 	pb "upload-backend/pb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -18,6 +21,12 @@ import (
 func main() {
 	filePath := flag.String("file", "", "path to file")
 	serverAddr := flag.String("server", "localhost:50051", "gRPC server address")
+	encryptKey := flag.String("encrypt-key", "", "path to a 32-byte key; encrypts chunks client-side before upload")
+	resumeID := flag.String("resume", "", "file_id of an interrupted upload to resume")
+	useCDC := flag.Bool("cdc", false, "upload with content-defined chunking, sending only chunks the server lacks")
+	targetID := flag.String("version-of", "", "file_id of an existing file to upload a new version of")
+	folder := flag.String("folder", "", "folder path to place the new file in, e.g. /projects/q3")
+	extract := flag.Bool("extract", false, "unpack a zip, tar or tar.gz upload into a folder once it completes")
 	flag.Parse()
 
 	if *filePath == "" {
@@ -48,16 +57,84 @@ func main() {
 	// Add JWT token to context
 	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer your-jwt-token")
 
-	// Initialize upload with server-generated ID
-	initResp, err := client.InitUpload(ctx, &pb.InitRequest{
-		FileName:    filepath.Base(fileInfo.Name()),
-		TotalChunks: totalChunks,
-		UserId:      "user-from-jwt", // This will be overridden by JWT
-	})
-	if err != nil {
-		panic(err)
+	if *useCDC {
+		if *encryptKey != "" || *resumeID != "" {
+			panic(fmt.Errorf("--cdc cannot be combined with --encrypt-key or --resume"))
+		}
+		if err := uploadCDC(ctx, client, file, *targetID, *folder); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	// The user key never leaves the client; the server stores only the wrapped content key
+	var userKey, contentKey []byte
+	if *encryptKey != "" {
+		userKey, err = e2e.LoadKey(*encryptKey)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	var fileID string
+	if *resumeID != "" {
+		// Resume an existing upload, recovering its content key from the record
+		meta, err := client.GetUploadMetadata(ctx, &pb.GetMetadataRequest{FileId: *resumeID})
+		if err != nil {
+			panic(err)
+		}
+		fileID = meta.FileId
+		if meta.EncryptionScheme != "" {
+			if userKey == nil {
+				panic(fmt.Errorf("upload %s is encrypted, pass --encrypt-key", fileID))
+			}
+			if contentKey, err = e2e.UnwrapContentKey(userKey, meta.WrappedKey); err != nil {
+				panic(err)
+			}
+		}
+	} else {
+		// Initialize upload with server-generated ID
+		initReq := &pb.InitRequest{
+			FileName:     filepath.Base(fileInfo.Name()),
+			TotalChunks:  totalChunks,
+			UserId:       "user-from-jwt", // This will be overridden by JWT
+			TargetFileId: *targetID,
+			FolderPath:   *folder,
+			Extract:      *extract,
+		}
+		if userKey != nil {
+			var wrapped []byte
+			if contentKey, wrapped, err = e2e.NewContentKey(userKey); err != nil {
+				panic(err)
+			}
+			initReq.EncryptionScheme = e2e.Scheme
+			initReq.WrappedKey = wrapped
+			initReq.KeyId = e2e.KeyID(userKey)
+		} else {
+			// Let the server skip the transfer if it already has this content
+			hash := sha256.New()
+			if _, err := io.Copy(hash, file); err != nil {
+				panic(err)
+			}
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				panic(err)
+			}
+			initReq.Sha256 = hex.EncodeToString(hash.Sum(nil))
+			initReq.Size = fileInfo.Size()
+		}
+		initResp, err := client.InitUpload(ctx, initReq)
+		if err != nil {
+			panic(err)
+		}
+		fileID = initResp.FileId
+		if initResp.AlreadyUploaded {
+			fmt.Println("Server already has this content; upload completed with ID:", fileID)
+			return
+		}
+		if initResp.Version > 1 {
+			fmt.Printf("Uploading version %d of %s\n", initResp.Version, *targetID)
+		}
 	}
-	fileID := initResp.FileId
 	fmt.Println("Uploading file with ID:", fileID)
 
 	// Check already uploaded chunks
@@ -80,11 +157,12 @@ func main() {
 	buf := make([]byte, chunkSize)
 
 	for {
-		n, err := file.Read(buf)
+		// Full reads keep chunk boundaries stable across resumed runs
+		n, err := io.ReadFull(file, buf)
 		if err == io.EOF {
 			break
 		}
-		if err != nil {
+		if err != nil && err != io.ErrUnexpectedEOF {
 			panic(err)
 		}
 
@@ -94,13 +172,21 @@ func main() {
 			continue
 		}
 
+		content := buf[:n]
+		if contentKey != nil {
+			content, err = e2e.SealChunk(contentKey, fileID, chunkIndex, chunkIndex == totalChunks-1, content)
+			if err != nil {
+				panic(err)
+			}
+		}
+
 		err = stream.Send(&pb.FileChunk{
 			FileId:      fileID,
 			FileName:    fileInfo.Name(),
 			UserId:      uuid.New().String(), // example
 			ChunkIndex:  chunkIndex,
 			TotalChunks: totalChunks,
-			Content:     buf[:n],
+			Content:     content,
 		})
 		if err != nil {
 			panic(err)
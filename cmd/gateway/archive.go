@@ -0,0 +1,84 @@
+package main
+
+import (
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"upload-backend/pb"
+)
+
+// handleArchive streams DownloadArchive to the client as raw archive bytes.
+// GET takes repeated file_id or folder_id/path query parameters; POST takes
+// a JSON DownloadArchiveRequest. Callers authenticate with a bearer JWT; a
+// share link token works for archives of files it grants.
+func handleArchive(client pb.FileUploadServiceClient) func(http.ResponseWriter, *http.Request, map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		req := &pb.DownloadArchiveRequest{}
+		if r.Method == http.MethodPost {
+			body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+			if err != nil {
+				http.Error(w, "Failed to read request", http.StatusBadRequest)
+				return
+			}
+			if err := protojson.Unmarshal(body, req); err != nil {
+				http.Error(w, "Invalid archive request", http.StatusBadRequest)
+				return
+			}
+		} else {
+			q := r.URL.Query()
+			req.FileIds = q["file_id"]
+			req.FolderId = q.Get("folder_id")
+			req.Path = q.Get("path")
+			req.Format = q.Get("format")
+			req.Name = q.Get("name")
+			req.Zip64, _ = strconv.ParseBool(q.Get("zip64"))
+		}
+
+		ctx := r.Context()
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+		}
+		if token := r.Header.Get("X-Share-Token"); token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-share-token", token)
+		}
+
+		stream, err := client.DownloadArchive(ctx, req)
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		// The server checks everything before its first message, so errors
+		// up to here can still become an HTTP status
+		first, err := stream.Recv()
+		if err != nil {
+			log.Printf("gateway archive failed: error=%v", err)
+			writeUploadError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", first.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": first.FileName}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				// Abort the connection so a truncated archive is not
+				// mistaken for a complete one
+				log.Printf("gateway archive interrupted: file_name=%s, error=%v", first.FileName, err)
+				panic(http.ErrAbortHandler)
+			}
+			if _, err := w.Write(chunk.Content); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"upload-backend/pb"
+)
+
+// How often an idle event stream sends a keepalive so proxies keep it open
+const eventKeepalive = 15 * time.Second
+
+// eventJSON encodes events like the gateway's JSON responses
+var eventJSON = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// handleEvents relays WatchUpload to browsers: as Server-Sent Events, or
+// over a WebSocket when the request asks for an upgrade. Callers
+// authenticate with a bearer JWT or share token like other routes; because
+// EventSource and WebSocket cannot set headers, the "access_token" and
+// "share_token" query parameters are accepted as well.
+func handleEvents(client pb.FileUploadServiceClient) func(http.ResponseWriter, *http.Request, map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		q := r.URL.Query()
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+		} else if token := q.Get("access_token"); token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+		if token := firstNonEmpty(r.Header.Get("X-Share-Token"), q.Get("share_token")); token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-share-token", token)
+		}
+
+		fileID := pathParams["file_id"]
+		stream, err := client.WatchUpload(ctx, &pb.WatchUploadRequest{FileId: fileID})
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+		// The server authorizes the watcher before its first event, so
+		// failures up to here can still become an HTTP status
+		first, err := stream.Recv()
+		if err != nil {
+			log.Printf("gateway events failed: file_id=%s, error=%v", fileID, err)
+			writeUploadError(w, err)
+			return
+		}
+
+		events := make(chan *pb.UploadEvent)
+		var streamErr error
+		go func() {
+			defer close(events)
+			for ev := first; ; {
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+				if ev, streamErr = stream.Recv(); streamErr != nil {
+					return
+				}
+			}
+		}()
+		// result is the outcome of the stream, read once events is closed
+		result := func() error {
+			if streamErr == io.EOF || status.Code(streamErr) == codes.Canceled {
+				return nil
+			}
+			return streamErr
+		}
+
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			// Any origin may connect: credentials are explicit tokens, never cookies
+			websocket.Server{Handler: func(ws *websocket.Conn) {
+				relayWebSocket(ws, cancel, events, result)
+			}}.ServeHTTP(w, r)
+			return
+		}
+		relaySSE(w, fileID, events, result)
+	}
+}
+
+// relaySSE writes events as a text/event-stream. The stream ends after a
+// completed or failed event; clients should close their EventSource then
+// rather than let it reconnect.
+func relaySSE(w http.ResponseWriter, fileID string, events <-chan *pb.UploadEvent, result func() error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(eventKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				if err := result(); err != nil {
+					log.Printf("gateway events interrupted: file_id=%s, error=%v", fileID, err)
+					data, _ := eventJSON.Marshal(status.Convert(err).Proto())
+					fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+					flusher.Flush()
+				}
+				return
+			}
+			data, err := eventJSON.Marshal(ev)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// relayWebSocket sends each event as a JSON text message. A stream that
+// fails sends a final {"code", "message"} status message before closing.
+func relayWebSocket(ws *websocket.Conn, cancel context.CancelFunc, events <-chan *pb.UploadEvent, result func() error) {
+	defer ws.Close()
+	// Messages from the client are ignored; reading notices when it leaves
+	go func() {
+		var msg string
+		for websocket.Message.Receive(ws, &msg) == nil {
+		}
+		cancel()
+	}()
+
+	for ev := range events {
+		data, err := eventJSON.Marshal(ev)
+		if err != nil {
+			return
+		}
+		if err := websocket.Message.Send(ws, string(data)); err != nil {
+			return
+		}
+	}
+	if err := result(); err != nil {
+		data, _ := eventJSON.Marshal(status.Convert(err).Proto())
+		websocket.Message.Send(ws, string(data))
+	}
+}
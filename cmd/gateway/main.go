@@ -5,7 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"net/http"
-	"strconv"
+	"os"
+	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"upload-backend/pb"
@@ -21,15 +22,35 @@ func main() {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(headerMatcher))
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
 	err := pb.RegisterFileUploadServiceHandlerFromEndpoint(ctx, mux, *grpcServerEndpoint, opts)
 	if err != nil {
 		panic(fmt.Errorf("failed to start gateway: %v", err))
 	}
 
-	// Add custom REST upload endpoint
-	mux.HandlePath("POST", "/v1/upload", handleUpload)
+	// Direct client for custom routes that are not plain gRPC-Gateway mappings
+	conn, err := grpc.NewClient(*grpcServerEndpoint, opts...)
+	if err != nil {
+		panic(fmt.Errorf("failed to dial gRPC server: %v", err))
+	}
+	defer conn.Close()
+	client := pb.NewFileUploadServiceClient(conn)
+
+	// Add custom REST upload endpoints (JWT or presigned upload token)
+	mux.HandlePath("POST", "/v1/upload", handleUpload(client))
+	mux.HandlePath("POST", "/v1/uploads/{file_id}", handleUpload(client))
+
+	// Signed download links carry their own HMAC instead of a bearer token
+	signedURLSecret := []byte(firstNonEmpty(os.Getenv("SIGNED_URL_SECRET"), os.Getenv("JWT_SECRET"), "your-secret-key"))
+	mux.HandlePath("GET", "/v1/signed/{file_id}", handleSignedDownload(client, signedURLSecret))
+
+	// Archives stream as raw bytes rather than as JSON messages
+	mux.HandlePath("GET", "/v1/archives", handleArchive(client))
+	mux.HandlePath("POST", "/v1/archives", handleArchive(client))
+
+	// Upload events for browsers, as Server-Sent Events or a WebSocket
+	mux.HandlePath("GET", "/v1/uploads/{file_id}/events", handleEvents(client))
 
 	// Add CORS middleware
 	corsHandler := func(next http.Handler) http.Handler {
@@ -37,7 +58,7 @@ func main() {
 			// Set CORS headers
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Upload-Token, X-Share-Token")
 
 			// Handle preflight requests
 			if r.Method == "OPTIONS" {
@@ -56,46 +77,12 @@ func main() {
 	}
 }
 
-func handleUpload(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
-	// Parse multipart form
-	err := r.ParseMultipartForm(32 << 20) // 32 MB max
-	if err != nil {
-		http.Error(w, "Failed to parse multipart form", http.StatusBadRequest)
-		return
-	}
-
-	// Get file from form
-	file, _, err := r.FormFile("file")
-	if err != nil {
-		http.Error(w, "No file provided", http.StatusBadRequest)
-		return
+// headerMatcher forwards the share and upload token headers to gRPC metadata
+// in addition to the gateway defaults.
+func headerMatcher(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case "x-share-token", "x-upload-token":
+		return strings.ToLower(key), true
 	}
-	defer file.Close()
-
-	// Get other form fields
-	fileId := r.FormValue("fileId")
-	fileName := r.FormValue("fileName")
-	userId := r.FormValue("userId")
-	totalChunksStr := r.FormValue("totalChunks")
-
-	if fileId == "" || fileName == "" || userId == "" || totalChunksStr == "" {
-		http.Error(w, "Missing required fields", http.StatusBadRequest)
-		return
-	}
-
-	_, err = strconv.ParseInt(totalChunksStr, 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid totalChunks", http.StatusBadRequest)
-		return
-	}
-
-	// For now, just return success
-	// In a real implementation, you would:
-	// 1. Connect to gRPC server
-	// 2. Stream the file in chunks
-	// 3. Handle the upload process
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, `{"success": true, "message": "File uploaded successfully", "fileId": "%s"}`, fileId)
-}
\ No newline at end of file
+	return runtime.DefaultHeaderMatcher(key)
+}
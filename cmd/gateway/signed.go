@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"upload-backend/internal/signing"
+	"upload-backend/pb"
+)
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// clientIP returns the remote address of the request without its port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// handleSignedDownload validates a signed URL and streams the file back
+func handleSignedDownload(client pb.FileUploadServiceClient, secret []byte) func(http.ResponseWriter, *http.Request, map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		params, sig, err := signing.ParseURL(pathParams["file_id"], r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ip := clientIP(r)
+		if err := signing.VerifyURL(secret, params, sig, time.Now(), ip); err != nil {
+			code := http.StatusForbidden
+			if errors.Is(err, signing.ErrExpired) {
+				code = http.StatusGone
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+
+		// The server checks the IP binding again, trusting the address the
+		// gateway appends like its generated routes do
+		forwarded := ip
+		if prior := r.Header.Get("X-Forwarded-For"); prior != "" {
+			forwarded = prior + ", " + ip
+		}
+		ctx := metadata.AppendToOutgoingContext(r.Context(), "x-forwarded-for", forwarded)
+		resp, err := client.DownloadSigned(ctx, &pb.SignedDownloadRequest{
+			FileId:       params.FileID,
+			UrlId:        params.URLID,
+			ExpiresAt:    params.ExpiresAt,
+			MaxDownloads: params.MaxDownloads,
+			AllowedIp:    params.AllowedIP,
+			Signature:    sig,
+		})
+		if err != nil {
+			log.Printf("signed download failed: file_id=%s, error=%v", params.FileID, err)
+			switch status.Code(err) {
+			case codes.NotFound:
+				http.Error(w, "file not found", http.StatusNotFound)
+			case codes.PermissionDenied:
+				http.Error(w, "signed url is no longer valid", http.StatusForbidden)
+			default:
+				http.Error(w, "download failed", http.StatusBadGateway)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": resp.FileName}))
+		w.Header().Set("Content-Length", strconv.Itoa(len(resp.Content)))
+		w.Header().Set("Cache-Control", "private, no-store")
+		if _, err := w.Write(resp.Content); err != nil {
+			log.Printf("signed download write error: file_id=%s, error=%v", params.FileID, err)
+		}
+	}
+}
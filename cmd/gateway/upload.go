@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+const uploadChunkSize = 4 * 1024 * 1024 // matches the Go client
+
+// handleUpload streams a multipart "file" part to UploadFile. Callers
+// authenticate with a bearer JWT or with a presigned upload token sent as the
+// X-Upload-Token header or the "uploadToken" form field.
+func handleUpload(client pb.FileUploadServiceClient) func(http.ResponseWriter, *http.Request, map[string]string) {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		// Parse multipart form
+		err := r.ParseMultipartForm(32 << 20) // 32 MB in memory, rest spills to disk
+		if err != nil {
+			http.Error(w, "Failed to parse multipart form", http.StatusBadRequest)
+			return
+		}
+
+		// Get file from form
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "No file provided", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		fileID := pathParams["file_id"]
+		if fileID == "" {
+			fileID = r.FormValue("fileId")
+		}
+		if fileID == "" {
+			http.Error(w, "Missing required fields", http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+		}
+		token := r.Header.Get("X-Upload-Token")
+		if token == "" {
+			token = r.FormValue("uploadToken")
+		}
+		if token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-upload-token", token)
+		}
+
+		totalChunks := (header.Size + uploadChunkSize - 1) / uploadChunkSize
+		if totalChunks == 0 {
+			totalChunks = 1
+		}
+
+		stream, err := client.UploadFile(ctx)
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+
+		buf := make([]byte, uploadChunkSize)
+		for idx := int64(0); idx < totalChunks; idx++ {
+			n, err := io.ReadFull(file, buf)
+			if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+				http.Error(w, "Failed to read upload", http.StatusBadRequest)
+				return
+			}
+			err = stream.Send(&pb.FileChunk{
+				FileId:      fileID,
+				FileName:    header.Filename,
+				ChunkIndex:  idx,
+				TotalChunks: totalChunks,
+				Content:     buf[:n],
+			})
+			if err != nil {
+				// The server aborted the stream; CloseAndRecv returns its status
+				break
+			}
+		}
+
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			log.Printf("gateway upload failed: file_id=%s, error=%v", fileID, err)
+			writeUploadError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": resp.Success,
+			"message": resp.Message,
+			"fileId":  fileID,
+		})
+	}
+}
+
+// writeUploadError maps a gRPC status to an HTTP error response
+func writeUploadError(w http.ResponseWriter, err error) {
+	code := http.StatusBadGateway
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		code = http.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = http.StatusForbidden
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		code = http.StatusBadRequest
+	case codes.ResourceExhausted:
+		code = http.StatusRequestEntityTooLarge
+	}
+	http.Error(w, status.Convert(err).Message(), code)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"upload-backend/internal/server"
+)
+
+// rotate-keys rewraps every per-file data key with the active master key in
+// ENCRYPTION_KEYFILE. Run it after adding a new key and marking it active;
+// old keys can be removed from the keyfile once it reports nothing left.
+func main() {
+	dsn := os.Getenv("POSTGRES_DSN")
+	keyfile := os.Getenv("ENCRYPTION_KEYFILE")
+	if dsn == "" || keyfile == "" {
+		log.Fatalf("POSTGRES_DSN and ENCRYPTION_KEYFILE are required")
+	}
+
+	keyring, err := server.LoadKeyring(keyfile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load keyfile: %v", err)
+	}
+
+	db, err := server.NewUploadDB(dsn)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to PostgreSQL: %v", err)
+	}
+
+	rotated, err := server.RotateDataKeys(db, keyring)
+	if err != nil {
+		log.Fatalf("❌ Rotation stopped after %d keys: %v", rotated, err)
+	}
+	fmt.Printf("✅ Rewrapped %d data keys with master key %s\n", rotated, keyring.ActiveKeyID())
+}
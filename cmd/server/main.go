@@ -7,12 +7,13 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
-	"upload-backend/internal/server"
-	pb "upload-backend/pb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"upload-backend/internal/server"
+	pb "upload-backend/pb"
 )
 
 type cfg struct {
@@ -23,6 +24,33 @@ type cfg struct {
 	TLSCert     string
 	TLSKey      string
 	StorageDir  string
+	// Optional JSON file with allowed/denied MIME types and extensions
+	ContentPolicyFile string
+	// Malware scanner: "clamd", "stub" or empty to disable
+	Scanner   string
+	ClamdAddr string
+	// What happens to files the scanner refuses as too large: "reject" or "skip"
+	ScanOversize string
+	// Master keyfile for encryption at rest; empty stores files in plaintext
+	EncryptionKeyfile string
+	// How often unreferenced blobs are collected, and how long they are kept first
+	BlobGCInterval time.Duration
+	BlobGCGrace    time.Duration
+	// Completed versions kept per file; 0 keeps every version
+	VersionRetention int
+	// How long deleted files stay in the trash, and how often expired ones are purged
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+	// How often files past their retention policy's maximum age are deleted
+	RetentionInterval time.Duration
+	// Largest file ImportFromURL fetches, and private ranges it may reach anyway
+	ImportMaxSize     int64
+	ImportAllowedNets string
+	// Private CIDR ranges webhooks may be delivered to, and how often queued deliveries are retried
+	WebhookAllowedNets  string
+	WebhookPollInterval time.Duration
+	// CIDR ranges of proxies, such as the gateway, whose x-forwarded-for is trusted
+	TrustedProxies string
 }
 
 func mustEnv(k string, optional bool) string {
@@ -40,22 +68,62 @@ func defaultIfEmpty(s, d string) string {
 	return s
 }
 
+func durationEnv(k string, d time.Duration) time.Duration {
+	v := os.Getenv(k)
+	if v == "" {
+		return d
+	}
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", k, err)
+	}
+	return parsed
+}
+
+func intEnv(k string, d int) int {
+	v := os.Getenv(k)
+	if v == "" {
+		return d
+	}
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed < 0 {
+		log.Fatalf("invalid %s: %q", k, v)
+	}
+	return parsed
+}
+
 func loadCfg() cfg {
 	return cfg{
-		GRPCPort:    defaultIfEmpty(os.Getenv("GRPC_PORT"), "50051"),
-		PostgresDSN: mustEnv("POSTGRES_DSN", false),
-		RedisAddr:   defaultIfEmpty(os.Getenv("REDIS_ADDR"), "localhost:6379"),
-		JWTSecret:   mustEnv("JWT_SECRET", os.Getenv("ALLOW_INSECURE") == "true"),
-		TLSCert:     os.Getenv("TLS_CERT"),
-		TLSKey:      os.Getenv("TLS_KEY"),
-		StorageDir:  defaultIfEmpty(os.Getenv("STORAGE_DIR"), "./storage"),
+		GRPCPort:            defaultIfEmpty(os.Getenv("GRPC_PORT"), "50051"),
+		PostgresDSN:         mustEnv("POSTGRES_DSN", false),
+		RedisAddr:           defaultIfEmpty(os.Getenv("REDIS_ADDR"), "localhost:6379"),
+		JWTSecret:           mustEnv("JWT_SECRET", os.Getenv("ALLOW_INSECURE") == "true"),
+		TLSCert:             os.Getenv("TLS_CERT"),
+		TLSKey:              os.Getenv("TLS_KEY"),
+		StorageDir:          defaultIfEmpty(os.Getenv("STORAGE_DIR"), "./storage"),
+		ContentPolicyFile:   os.Getenv("CONTENT_POLICY_FILE"),
+		Scanner:             os.Getenv("SCANNER"),
+		ClamdAddr:           defaultIfEmpty(os.Getenv("CLAMD_ADDR"), "tcp://localhost:3310"),
+		ScanOversize:        defaultIfEmpty(os.Getenv("SCAN_OVERSIZE"), "reject"),
+		EncryptionKeyfile:   os.Getenv("ENCRYPTION_KEYFILE"),
+		BlobGCInterval:      durationEnv("BLOB_GC_INTERVAL", time.Hour),
+		BlobGCGrace:         durationEnv("BLOB_GC_GRACE", 24*time.Hour),
+		VersionRetention:    intEnv("VERSION_RETENTION", 10),
+		TrashRetention:      durationEnv("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval:  durationEnv("TRASH_PURGE_INTERVAL", time.Hour),
+		RetentionInterval:   durationEnv("RETENTION_INTERVAL", time.Hour),
+		ImportMaxSize:       int64(intEnv("IMPORT_MAX_SIZE", 10<<30)),
+		ImportAllowedNets:   os.Getenv("IMPORT_ALLOWED_NETS"),
+		WebhookAllowedNets:  os.Getenv("WEBHOOK_ALLOWED_NETS"),
+		WebhookPollInterval: durationEnv("WEBHOOK_POLL_INTERVAL", 10*time.Second),
+		TrustedProxies:      os.Getenv("TRUSTED_PROXIES"),
 	}
 }
 
 func main() {
 	// Load and validate configuration
 	config := loadCfg()
-	
+
 	grpcPort, err := strconv.Atoi(config.GRPCPort)
 	if err != nil {
 		log.Fatalf("invalid GRPC_PORT: %v", err)
@@ -76,6 +144,9 @@ func main() {
 		log.Fatalf("❌ Failed to connect to PostgreSQL: %v", err)
 	}
 	fmt.Println("✅ Connected to PostgreSQL")
+	if err := db.CheckRowSecurity(); err != nil {
+		log.Fatalf("❌ Tenant isolation check failed: %v", err)
+	}
 
 	// Connect to Redis
 	rdb := redis.NewClient(&redis.Options{
@@ -94,22 +165,83 @@ func main() {
 		log.Fatalf("❌ Failed to listen: %v", err)
 	}
 
-	// Configure TLS if certificates are provided
-	var grpcServer *grpc.Server
+	// Initialize the upload service
+	uploadService := server.NewUploadService(config.RedisAddr, config.StorageDir, db)
+
+	policy, err := server.LoadContentPolicy(config.ContentPolicyFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load content policy: %v", err)
+	}
+	uploadService.SetContentPolicy(policy)
+	uploadService.SetVersionRetention(config.VersionRetention)
+	uploadService.SetTrashRetention(config.TrashRetention)
+
+	allowedNets, err := server.ParseNets(config.ImportAllowedNets)
+	if err != nil {
+		log.Fatalf("❌ Invalid IMPORT_ALLOWED_NETS: %v", err)
+	}
+	uploadService.SetImportPolicy(server.ImportPolicy{MaxSize: config.ImportMaxSize, AllowedNets: allowedNets})
+	webhookNets, err := server.ParseNets(config.WebhookAllowedNets)
+	if err != nil {
+		log.Fatalf("❌ Invalid WEBHOOK_ALLOWED_NETS: %v", err)
+	}
+	uploadService.SetWebhookPolicy(server.WebhookPolicy{AllowedNets: webhookNets})
+	trustedProxies, err := server.ParseNets(config.TrustedProxies)
+	if err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
+	uploadService.SetTrustedProxies(trustedProxies)
+
+	if config.EncryptionKeyfile != "" {
+		keyring, err := server.LoadKeyring(config.EncryptionKeyfile)
+		if err != nil {
+			log.Fatalf("❌ Failed to load encryption keyfile: %v", err)
+		}
+		uploadService.SetKeyWrapper(keyring)
+		fmt.Printf("✅ Encryption at rest enabled (master key %s)\n", keyring.ActiveKeyID())
+	}
+
+	scanner, err := server.NewScanner(config.Scanner, config.ClamdAddr)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure scanner: %v", err)
+	}
+	if scanner != nil {
+		switch config.ScanOversize {
+		case "reject":
+		case "skip":
+			uploadService.SetSkipOversizeScans(true)
+		default:
+			log.Fatalf("❌ Invalid SCAN_OVERSIZE %q: want reject or skip", config.ScanOversize)
+		}
+		uploadService.SetScanner(scanner)
+		fmt.Printf("✅ Malware scanning enabled (%s)\n", config.Scanner)
+	}
+
+	go uploadService.RunBlobGC(context.Background(), config.BlobGCInterval, config.BlobGCGrace)
+	go uploadService.RunTrashPurge(context.Background(), config.TrashPurgeInterval)
+	go uploadService.RunRetention(context.Background(), config.RetentionInterval)
+	go uploadService.ResumeImports()
+	go uploadService.ResumeExtractions()
+	go uploadService.RunWebhooks(context.Background(), config.WebhookPollInterval)
+
+	// Configure TLS if certificates are provided; every RPC passes through the
+	// tenant interceptors, then the audit interceptors
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(uploadService.TenantUnaryInterceptor(), uploadService.AuditUnaryInterceptor()),
+		grpc.ChainStreamInterceptor(uploadService.TenantStreamInterceptor(), uploadService.AuditStreamInterceptor()),
+	}
 	if config.TLSCert != "" {
 		creds, err := credentials.NewServerTLSFromFile(config.TLSCert, config.TLSKey)
 		if err != nil {
 			log.Fatalf("❌ Failed to load TLS credentials: %v", err)
 		}
-		grpcServer = grpc.NewServer(grpc.Creds(creds))
+		opts = append(opts, grpc.Creds(creds))
 		fmt.Println("✅ TLS enabled")
 	} else {
-		grpcServer = grpc.NewServer()
 		fmt.Println("⚠️  Running without TLS")
 	}
+	grpcServer := grpc.NewServer(opts...)
 
-	// Initialize the upload service
-	uploadService := server.NewUploadService(config.RedisAddr, config.StorageDir, db)
 	pb.RegisterFileUploadServiceServer(grpcServer, uploadService)
 
 	fmt.Printf("🚀 gRPC server running on port %d\n", grpcPort)
@@ -0,0 +1,151 @@
+// Package cdc splits streams into content-defined chunks using FastCDC, so
+// an edit in one place of a file only changes the chunks around it. Chunk
+// boundaries depend only on the data and the options, never on where reads
+// happen to end, so every client produces the same chunks for the same bytes.
+package cdc
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// Options bound the chunk sizes. AvgSize must be a power of two.
+type Options struct {
+	MinSize int
+	AvgSize int
+	MaxSize int
+}
+
+// DefaultOptions suits large files such as VM images and datasets
+var DefaultOptions = Options{MinSize: 256 << 10, AvgSize: 1 << 20, MaxSize: 4 << 20}
+
+// gear holds one pseudo-random value per byte. It is generated from a fixed
+// seed and must never change, or existing chunks stop deduplicating.
+var gear = func() [256]uint64 {
+	var table [256]uint64
+	state := uint64(0x5ca1ab1e0ddba11)
+	for i := range table {
+		// splitmix64
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// Chunker reads content-defined chunks from a stream
+type Chunker struct {
+	r      io.Reader
+	opts   Options
+	maskS  uint64 // stricter mask used before AvgSize
+	maskL  uint64 // looser mask used after AvgSize
+	buf    []byte
+	start  int
+	end    int
+	eof    bool
+	offset int64
+}
+
+// NewChunker returns a chunker over r
+func NewChunker(r io.Reader, opts Options) (*Chunker, error) {
+	if opts.MinSize <= 0 || opts.AvgSize <= opts.MinSize || opts.MaxSize <= opts.AvgSize {
+		return nil, errors.New("cdc: sizes must satisfy 0 < min < avg < max")
+	}
+	if opts.AvgSize&(opts.AvgSize-1) != 0 {
+		return nil, errors.New("cdc: average size must be a power of two")
+	}
+	// Normalized chunking (level 2): two bits more before the average size,
+	// two bits fewer after it, which narrows the chunk size distribution
+	avgBits := bits.TrailingZeros(uint(opts.AvgSize))
+	return &Chunker{
+		r:     r,
+		opts:  opts,
+		maskS: topBits(avgBits + 2),
+		maskL: topBits(avgBits - 2),
+		buf:   make([]byte, 2*opts.MaxSize),
+	}, nil
+}
+
+// topBits returns a mask of the n most significant bits. The gear hash
+// shifts left, so the high bits depend on the last 64 bytes.
+func topBits(n int) uint64 {
+	return ^uint64(0) << (64 - n)
+}
+
+// Chunk is one content-defined piece of the stream. Data is only valid until
+// the next call to Next.
+type Chunk struct {
+	Offset int64
+	Data   []byte
+}
+
+// Next returns the next chunk, or io.EOF after the last one
+func (c *Chunker) Next() (Chunk, error) {
+	if err := c.fill(); err != nil {
+		return Chunk{}, err
+	}
+	if c.start == c.end {
+		return Chunk{}, io.EOF
+	}
+
+	n := c.cut(c.buf[c.start:c.end])
+	chunk := Chunk{Offset: c.offset, Data: c.buf[c.start : c.start+n]}
+	c.start += n
+	c.offset += int64(n)
+	return chunk, nil
+}
+
+// fill makes sure at least MaxSize bytes are buffered unless the stream ends first
+func (c *Chunker) fill() error {
+	if c.end-c.start >= c.opts.MaxSize || c.eof {
+		return nil
+	}
+	if c.start > 0 {
+		c.end = copy(c.buf, c.buf[c.start:c.end])
+		c.start = 0
+	}
+	for c.end < len(c.buf) && !c.eof {
+		n, err := c.r.Read(c.buf[c.end:])
+		c.end += n
+		if err == io.EOF {
+			c.eof = true
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cut returns the length of the first chunk of data
+func (c *Chunker) cut(data []byte) int {
+	n := len(data)
+	if n <= c.opts.MinSize {
+		return n
+	}
+	if n > c.opts.MaxSize {
+		n = c.opts.MaxSize
+	}
+	normal := c.opts.AvgSize
+	if normal > n {
+		normal = n
+	}
+
+	var fp uint64
+	i := c.opts.MinSize
+	for ; i < normal; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&c.maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		fp = (fp << 1) + gear[data[i]]
+		if fp&c.maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
@@ -0,0 +1,173 @@
+// Package e2e implements client-side end-to-end encryption for uploads. The
+// server only stores the scheme name and the wrapped content key; it never
+// sees the user's key or plaintext.
+package e2e
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Scheme identifies this encryption format in upload records
+const Scheme = "client-aes256gcm-v1"
+
+const (
+	keySize   = 32
+	nonceSize = 12
+	// Overhead is the number of bytes each sealed chunk adds to its plaintext
+	Overhead = 4 + nonceSize + 16
+	// maxFrameSize bounds a single sealed chunk read back on download
+	maxFrameSize = 256 << 20
+)
+
+var (
+	wrapAAD         = []byte("upload-backend e2e content key")
+	ErrWrongKey     = errors.New("e2e: key does not match this file")
+	ErrCorruptChunk = errors.New("e2e: chunk failed authentication")
+)
+
+// LoadKey reads a user key file holding 32 raw or base64-encoded bytes
+func LoadKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == keySize {
+		return data, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("e2e: key file must hold 32 raw or base64-encoded bytes")
+	}
+	return key, nil
+}
+
+// KeyID is a short fingerprint of a user key, stored so clients can tell
+// which key a file needs without revealing it
+func KeyID(userKey []byte) string {
+	sum := sha256.Sum256(append([]byte("upload-backend e2e key id"), userKey...))
+	return hex.EncodeToString(sum[:8])
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// NewContentKey creates a random per-file content key and wraps it with the user key
+func NewContentKey(userKey []byte) (contentKey, wrapped []byte, err error) {
+	contentKey = make([]byte, keySize)
+	if _, err := rand.Read(contentKey); err != nil {
+		return nil, nil, err
+	}
+	aead, err := newAEAD(userKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	return contentKey, aead.Seal(nonce, nonce, contentKey, wrapAAD), nil
+}
+
+// UnwrapContentKey recovers a content key with the user key
+func UnwrapContentKey(userKey, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < nonceSize {
+		return nil, ErrWrongKey
+	}
+	aead, err := newAEAD(userKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := aead.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], wrapAAD)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return key, nil
+}
+
+func chunkAAD(fileID string, index int64, final bool) []byte {
+	aad := append([]byte(fileID), 0)
+	aad = binary.BigEndian.AppendUint64(aad, uint64(index))
+	if final {
+		return append(aad, 1)
+	}
+	return append(aad, 0)
+}
+
+// SealChunk encrypts one upload chunk. The output is length-prefixed so the
+// merged file can be split back into chunks on download.
+func SealChunk(contentKey []byte, fileID string, index int64, final bool, plain []byte) ([]byte, error) {
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 4+nonceSize, Overhead+len(plain))
+	if _, err := rand.Read(out[4:]); err != nil {
+		return nil, err
+	}
+	out = aead.Seal(out, out[4:], plain, chunkAAD(fileID, index, final))
+	binary.BigEndian.PutUint32(out[:4], uint32(len(out)-4))
+	return out, nil
+}
+
+// OpenStream decrypts a downloaded file written by SealChunk frames
+func OpenStream(contentKey []byte, fileID string, r io.Reader, w io.Writer) error {
+	aead, err := newAEAD(contentKey)
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	var frame []byte
+	var size [4]byte
+	for index := int64(0); ; index++ {
+		// A missing frame is caught by the final flag of the previous chunk
+		if _, err := io.ReadFull(br, size[:]); err != nil {
+			return ErrCorruptChunk
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if n < nonceSize+16 || n > maxFrameSize {
+			return ErrCorruptChunk
+		}
+		if cap(frame) < int(n) {
+			frame = make([]byte, n)
+		}
+		frame = frame[:n]
+		if _, err := io.ReadFull(br, frame); err != nil {
+			return ErrCorruptChunk
+		}
+
+		// Only the last frame was sealed with the final flag
+		_, peekErr := br.Peek(1)
+		final := peekErr == io.EOF
+		if peekErr != nil && !final {
+			return peekErr
+		}
+		plain, err := aead.Open(nil, frame[:nonceSize], frame[nonceSize:], chunkAAD(fileID, index, final))
+		if err != nil {
+			return ErrCorruptChunk
+		}
+		if _, err := w.Write(plain); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+	}
+}
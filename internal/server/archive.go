@@ -0,0 +1,395 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// Archives are written straight into the response stream; nothing is
+// staged on disk, so an archive can only report errors before its first
+// bytes are sent or by ending the stream early.
+
+const (
+	maxArchiveEntries = 10000
+	archiveChunkSize  = 256 << 10
+
+	// Beyond these a ZIP needs the ZIP64 extensions, which some older
+	// extractors do not understand
+	zip64MinSize    = 1<<32 - 1
+	zip64MinEntries = 1<<16 - 1
+	// Rough bytes of headers per ZIP entry besides its name, counted twice
+	zipEntryOverhead = 128
+)
+
+// SubtreeFile is a live file below a folder with the path of its folder
+// relative to that folder
+type SubtreeFile struct {
+	FileEntry
+	Dir string // empty directly inside the folder
+}
+
+// SubtreeFiles returns up to limit live files of ownerID anywhere below
+// folderID, or below the top level when folderID is empty, ordered by path
+func (db *UploadDB) SubtreeFiles(ownerID, folderID string, limit int) ([]*SubtreeFile, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`WITH RECURSIVE sub AS (
+		   SELECT id, name::text AS dir FROM folders
+		   WHERE owner_id = $1 AND parent_id IS NOT DISTINCT FROM NULLIF($2::text, '')::uuid
+		   UNION ALL
+		   SELECT f.id, sub.dir || '/' || f.name FROM folders f JOIN sub ON f.parent_id = sub.id
+		 ) SELECT `+fileEntryColumns+`, COALESCE(sub.dir, '')
+		 FROM `+fileEntryFrom+` LEFT JOIN sub ON sub.id = r.folder_id
+		 WHERE r.version_of IS NULL AND r.deleted_at IS NULL AND r.user_id::text = $1
+		   AND (sub.id IS NOT NULL OR r.folder_id IS NOT DISTINCT FROM NULLIF($2::text, '')::uuid)
+		 ORDER BY COALESCE(sub.dir, ''), r.file_name, r.created_at DESC
+		 LIMIT $3`,
+		ownerID, folderID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var files []*SubtreeFile
+	for rows.Next() {
+		var f SubtreeFile
+		if err := rows.Scan(&f.FileID, &f.FileName, &f.FolderID, &f.Size, &f.Status, &f.Version, &f.CreatedAt, &f.Metadata, &f.Tags,
+			&f.Dir); err != nil {
+			return nil, err
+		}
+		files = append(files, &f)
+	}
+	return files, rows.Err()
+}
+
+// archiveEntry is a file to be written into an archive under name
+type archiveEntry struct {
+	name   string
+	fileID string // the file's first version, for auditing
+	rec    *UploadRecord
+}
+
+// archiveNames hands out sanitized entry names, numbering repeats the way
+// browsers number repeated downloads. Names compare case-insensitively so
+// extracting on a case-insensitive file system loses nothing.
+type archiveNames map[string]bool
+
+func (n archiveNames) add(dir, name string) string {
+	var segs []string
+	for _, seg := range strings.Split(dir, "/") {
+		if seg != "" {
+			segs = append(segs, archiveSegment(seg))
+		}
+	}
+	prefix := ""
+	if len(segs) > 0 {
+		prefix = strings.Join(segs, "/") + "/"
+	}
+	name = archiveSegment(name)
+	base, ext := name, ""
+	if i := strings.LastIndexByte(name, '.'); i > 0 {
+		base, ext = name[:i], name[i:]
+	}
+	for i := 2; n[strings.ToLower(prefix+name)]; i++ {
+		name = base + " (" + strconv.Itoa(i) + ")" + ext
+	}
+	n[strings.ToLower(prefix+name)] = true
+	return prefix + name
+}
+
+// archiveSegment makes one path segment safe to extract: no separators,
+// no parent references and no control characters
+func archiveSegment(seg string) string {
+	seg = strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(seg))
+	return sanitizeFilename(seg)
+}
+
+// archivable reports why a version cannot go into an archive, or "" if it can
+func archivable(rec *UploadRecord) string {
+	switch {
+	case rec.Status == "quarantined":
+		return "file is quarantined"
+	case rec.Status != "completed":
+		return "upload is not completed"
+	case rec.ClientEncScheme != "":
+		return "file is client-side encrypted"
+	}
+	return ""
+}
+
+// archiveFiles collects explicitly named files, each of which the caller
+// must be able to read
+func (s *UploadService) archiveFiles(ctx context.Context, fileIDs []string) ([]*archiveEntry, error) {
+	names := archiveNames{}
+	var entries []*archiveEntry
+	for _, id := range fileIDs {
+		rec, err := s.db.GetUploadByID(id)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "file not found: %s", id)
+		}
+		if _, err := s.authorize(ctx, rec, permRead); err != nil {
+			return nil, err
+		}
+		root, err := s.rootOf(rec)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "file not found: %s", id)
+		}
+		if rec, err = s.currentVersion(rec); err != nil {
+			return nil, status.Errorf(codes.Internal, "version lookup error: %v", err)
+		}
+		if why := archivable(rec); why != "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s: %s", why, id)
+		}
+		entries = append(entries, &archiveEntry{name: names.add("", root.FileName), fileID: root.FileID, rec: rec})
+	}
+	return entries, nil
+}
+
+// archiveFolder collects the files below a folder of userID, or below the
+// top level when f is nil. Files that cannot be archived are left out.
+func (s *UploadService) archiveFolder(userID string, f *Folder) ([]*archiveEntry, error) {
+	folderID := ""
+	if f != nil {
+		folderID = f.ID
+	}
+	files, err := s.db.SubtreeFiles(userID, folderID, maxArchiveEntries+1)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	if len(files) > maxArchiveEntries {
+		return nil, status.Errorf(codes.FailedPrecondition, "folder holds more than %d files", maxArchiveEntries)
+	}
+
+	names := archiveNames{}
+	var entries []*archiveEntry
+	for _, file := range files {
+		if file.Status != "completed" {
+			continue
+		}
+		root, err := s.db.GetUploadByID(file.FileID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue // deleted since it was listed
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+		}
+		rec, err := s.currentVersion(root)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "version lookup error: %v", err)
+		}
+		if why := archivable(rec); why != "" {
+			log.Printf("DownloadArchive skipped: file_id=%s, reason=%s", file.FileID, why)
+			continue
+		}
+		entries = append(entries, &archiveEntry{name: names.add(file.Dir, root.FileName), fileID: root.FileID, rec: rec})
+	}
+	return entries, nil
+}
+
+// archiveStream sends what is written to it as ArchiveChunk messages
+type archiveStream struct {
+	stream pb.FileUploadService_DownloadArchiveServer
+}
+
+func (a archiveStream) Write(p []byte) (int, error) {
+	for off := 0; off < len(p); off += archiveChunkSize {
+		// The caller reuses p, so each message gets its own copy
+		chunk := bytes.Clone(p[off:min(off+archiveChunkSize, len(p))])
+		if err := a.stream.Send(&pb.ArchiveChunk{Content: chunk}); err != nil {
+			return off, err
+		}
+	}
+	return len(p), nil
+}
+
+// precompressed reports whether content of a MIME type is already
+// compressed, so deflating it again would only cost time
+func precompressed(mimeType string) bool {
+	switch {
+	case strings.HasPrefix(mimeType, "video/"), strings.HasPrefix(mimeType, "audio/"):
+		return true
+	case strings.HasPrefix(mimeType, "image/"):
+		return mimeType != "image/svg+xml" && mimeType != "image/bmp" && mimeType != "image/tiff"
+	}
+	switch mimeType {
+	case "application/zip", "application/gzip", "application/x-gzip", "application/x-bzip2", "application/x-xz",
+		"application/zstd", "application/x-7z-compressed", "application/x-rar-compressed", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// writeZip writes entries as a ZIP archive
+func (s *UploadService) writeZip(ctx context.Context, w io.Writer, entries []*archiveEntry, modTime time.Time) error {
+	zw := zip.NewWriter(w)
+	for _, e := range entries {
+		method := zip.Deflate
+		if precompressed(e.rec.MimeType) {
+			method = zip.Store
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: method, Modified: modTime})
+		if err != nil {
+			return err
+		}
+		if err := s.copyEntry(ctx, fw, e); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// writeTarGz writes entries as a gzip-compressed tar archive
+func (s *UploadService) writeTarGz(ctx context.Context, w io.Writer, entries []*archiveEntry, modTime time.Time) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		content, err := s.openUpload(e.rec)
+		if err != nil {
+			return err
+		}
+		err = tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     e.name,
+			Size:     content.Size(),
+			Mode:     0o644,
+			ModTime:  modTime,
+		})
+		if err == nil {
+			_, err = io.Copy(tw, io.NewSectionReader(content, 0, content.Size()))
+		}
+		content.Close()
+		if err != nil {
+			return err
+		}
+		s.audit(ctx, "download", e.fileID, "ok", "archive")
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// copyEntry copies the content of an archive entry to w
+func (s *UploadService) copyEntry(ctx context.Context, w io.Writer, e *archiveEntry) error {
+	content, err := s.openUpload(e.rec)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	if _, err := io.Copy(w, io.NewSectionReader(content, 0, content.Size())); err != nil {
+		return err
+	}
+	s.audit(ctx, "download", e.fileID, "ok", "archive")
+	return nil
+}
+
+// DownloadArchive streams the named files, or the files below a folder of
+// the caller with their folder structure, as one ZIP or tar.gz archive
+func (s *UploadService) DownloadArchive(req *pb.DownloadArchiveRequest, stream pb.FileUploadService_DownloadArchiveServer) error {
+	ctx := stream.Context()
+	s = s.scoped(ctx)
+
+	format := req.Format
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "tar.gz" {
+		return status.Errorf(codes.InvalidArgument, "unsupported archive format %q", req.Format)
+	}
+	byFolder := req.FolderId != "" || req.Path != ""
+	if byFolder == (len(req.FileIds) > 0) {
+		return status.Error(codes.InvalidArgument, "give either file_ids or a folder")
+	}
+	if len(req.FileIds) > maxArchiveEntries {
+		return status.Errorf(codes.InvalidArgument, "at most %d files per archive", maxArchiveEntries)
+	}
+
+	name := "files"
+	var entries []*archiveEntry
+	if byFolder {
+		userID, err := s.validateJWT(ctx)
+		if err != nil {
+			return err
+		}
+		f, err := s.resolveFolder(userID, req.FolderId, req.Path)
+		if err != nil {
+			return err
+		}
+		if f != nil {
+			name = f.Name
+		}
+		if entries, err = s.archiveFolder(userID, f); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if entries, err = s.archiveFiles(ctx, req.FileIds); err != nil {
+			return err
+		}
+	}
+	if len(entries) == 0 {
+		return status.Error(codes.FailedPrecondition, "no completed files to archive")
+	}
+	if req.Name != "" {
+		name = archiveSegment(req.Name)
+	}
+
+	if format == "zip" && !req.Zip64 {
+		var estimate int64
+		for _, e := range entries {
+			// Deflate can grow incompressible content slightly
+			estimate += e.rec.Size + e.rec.Size>>10 + zipEntryOverhead + 2*int64(len(e.name))
+		}
+		if estimate >= zip64MinSize || len(entries) >= zip64MinEntries {
+			return status.Error(codes.FailedPrecondition, "archive needs ZIP64; set zip64 or use tar.gz")
+		}
+	}
+
+	contentType := "application/zip"
+	if format == "tar.gz" {
+		contentType = "application/gzip"
+	}
+	if err := stream.Send(&pb.ArchiveChunk{FileName: name + "." + format, ContentType: contentType}); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(archiveStream{stream: stream}, archiveChunkSize)
+	var err error
+	if format == "zip" {
+		err = s.writeZip(ctx, bw, entries, time.Now())
+	} else {
+		err = s.writeTarGz(ctx, bw, entries, time.Now())
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	if err != nil {
+		log.Printf("DownloadArchive error: files=%d, format=%s, error=%v", len(entries), format, err)
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Errorf(codes.Internal, "failed to write archive: %v", err)
+	}
+
+	log.Printf("DownloadArchive success: files=%d, format=%s", len(entries), format)
+	return nil
+}
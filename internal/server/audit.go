@@ -0,0 +1,342 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
+
+// auditActions maps RPC names to audited actions. UploadFile is audited per
+// chunk ("chunk") and once when the upload completes or the stream fails
+// ("complete").
+var auditActions = map[string]string{
+	"InitUpload":         "init",
+	"ImportFromURL":      "import",
+	"DownloadFile":       "download",
+	"DownloadSigned":     "download",
+	"DeleteFile":         "delete",
+	"ShareFile":          "share",
+	"UnshareFile":        "unshare",
+	"CreateSignedURL":    "share",
+	"CommitManifest":     "complete",
+	"RestoreVersion":     "restore",
+	"RestoreFile":        "restore",
+	"PurgeFile":          "purge",
+	"RenameFile":         "rename",
+	"MoveFile":           "move",
+	"CopyFile":           "copy",
+	"UpdateFileMetadata": "metadata",
+}
+
+// AuditEvent is a single row of the audit log
+type AuditEvent struct {
+	ID         int64
+	OccurredAt time.Time
+	TenantID   string
+	Actor      string
+	Action     string
+	FileID     string
+	PeerIP     string
+	UserAgent  string
+	Result     string
+	Detail     string
+}
+
+// AuditFilter selects audit events. Zero values match everything.
+type AuditFilter struct {
+	TenantID  string // only applies to a database that sees every tenant
+	Actor     string
+	Action    string
+	FileID    string
+	Result    string
+	Since     time.Time
+	Until     time.Time
+	Cursor    int64 // only events after this ID in the chosen order
+	Ascending bool
+	Limit     int
+}
+
+// InsertAuditEvent appends an event to the audit log
+func (db *UploadDB) InsertAuditEvent(e *AuditEvent) error {
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO audit_events(actor, action, file_id, peer_ip, user_agent, result, detail)
+		 VALUES($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, NULLIF($7, ''))`,
+		e.Actor, e.Action, e.FileID, e.PeerIP, e.UserAgent, e.Result, e.Detail,
+	)
+	return err
+}
+
+// ListAuditEvents returns events matching the filter ordered by ID
+func (db *UploadDB) ListAuditEvents(f AuditFilter) ([]*AuditEvent, error) {
+	var where []string
+	var args []interface{}
+	add := func(cond string, v interface{}) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.TenantID != "" {
+		add("tenant_id = $%d", f.TenantID)
+	}
+	if f.Actor != "" {
+		add("actor = $%d", f.Actor)
+	}
+	if f.Action != "" {
+		add("action = $%d", f.Action)
+	}
+	if f.FileID != "" {
+		add("file_id = $%d", f.FileID)
+	}
+	if f.Result != "" {
+		add("result = $%d", f.Result)
+	}
+	if !f.Since.IsZero() {
+		add("occurred_at >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("occurred_at < $%d", f.Until)
+	}
+	order := "DESC"
+	if f.Ascending {
+		order = "ASC"
+		if f.Cursor > 0 {
+			add("id > $%d", f.Cursor)
+		}
+	} else if f.Cursor > 0 {
+		add("id < $%d", f.Cursor)
+	}
+
+	query := `SELECT id, occurred_at, tenant_id, actor, action, COALESCE(file_id, ''), COALESCE(peer_ip, ''),
+	                 COALESCE(user_agent, ''), result, COALESCE(detail, '')
+	          FROM audit_events`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	args = append(args, f.Limit)
+	query += fmt.Sprintf(" ORDER BY id %s LIMIT $%d", order, len(args))
+
+	rows, err := db.pool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*AuditEvent
+	for rows.Next() {
+		var e AuditEvent
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.TenantID, &e.Actor, &e.Action, &e.FileID, &e.PeerIP, &e.UserAgent, &e.Result, &e.Detail); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// auditActor names the caller of a request: the JWT user, or the kind of
+// token used when there is none
+func (s *UploadService) auditActor(ctx context.Context) string {
+	if c, err := s.authenticate(ctx); err == nil {
+		return c.UserID
+	}
+	switch {
+	case uploadTokenFromContext(ctx) != "":
+		return "upload-token"
+	case shareTokenFromContext(ctx) != "":
+		return "share-link"
+	}
+	return "anonymous"
+}
+
+// auditPeer returns the client IP and user agent, preferring the values the
+// gateway forwards for REST callers when it is a trusted proxy
+func (s *UploadService) auditPeer(ctx context.Context) (ip, userAgent string) {
+	ip = s.clientIP(ctx)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("grpcgateway-user-agent"); len(v) > 0 {
+			userAgent = v[0]
+		} else if v := md.Get("user-agent"); len(v) > 0 {
+			userAgent = v[0]
+		}
+	}
+	return ip, userAgent
+}
+
+func auditResult(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return strings.ToLower(status.Code(err).String())
+}
+
+// audit records an event for the current request. Failures to write the
+// audit log are logged but do not fail the request.
+func (s *UploadService) audit(ctx context.Context, action, fileID, result, detail string) {
+	s.auditAs(ctx, s.auditActor(ctx), action, fileID, result, detail)
+}
+
+// auditAs records an event with an explicit actor
+func (s *UploadService) auditAs(ctx context.Context, actor, action, fileID, result, detail string) {
+	ip, ua := s.auditPeer(ctx)
+	e := &AuditEvent{
+		Actor:     actor,
+		Action:    action,
+		FileID:    fileID,
+		PeerIP:    ip,
+		UserAgent: ua,
+		Result:    result,
+		Detail:    detail,
+	}
+	// Events belong to the tenant of the request, or of a scoped background job
+	if err := s.scoped(ctx).db.InsertAuditEvent(e); err != nil {
+		log.Printf("audit write error: action=%s, file_id=%s, error=%v", action, fileID, err)
+	}
+}
+
+// auditCompleted records that an upload finished. Background jobs such as
+// imports have no caller, so they are attributed to the uploader.
+func (s *UploadService) auditCompleted(ctx context.Context, rec *UploadRecord, detail string) {
+	actor := s.auditActor(ctx)
+	if actor == "anonymous" && rec.UserID != "" {
+		actor = rec.UserID
+	}
+	s.auditAs(ctx, actor, "complete", rec.FileID, "ok", detail)
+}
+
+type fileIDGetter interface {
+	GetFileId() string
+}
+
+// AuditUnaryInterceptor records an audit event for every audited unary RPC
+func (s *UploadService) AuditUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+
+		method := path.Base(info.FullMethod)
+		action, ok := auditActions[method]
+		if !ok {
+			return resp, err
+		}
+		var fileID string
+		if r, ok := req.(fileIDGetter); ok {
+			fileID = r.GetFileId()
+		}
+		if r, ok := resp.(fileIDGetter); ok && fileID == "" && err == nil {
+			fileID = r.GetFileId()
+		}
+		result := auditResult(err)
+		if r, ok := resp.(*pb.DeleteResponse); ok && err == nil && !r.Success {
+			result = "failed"
+		}
+		actor := s.auditActor(ctx)
+		if method == "DownloadSigned" && actor == "anonymous" {
+			actor = "signed-url"
+		}
+		s.auditAs(ctx, actor, action, fileID, result, "")
+		return resp, err
+	}
+}
+
+// auditStream remembers which upload a stream is for; chunks are audited
+// by saveChunk once their outcome is known
+type auditStream struct {
+	grpc.ServerStream
+	fileID string
+}
+
+func (a *auditStream) RecvMsg(m interface{}) error {
+	err := a.ServerStream.RecvMsg(m)
+	if chunk, ok := m.(*pb.FileChunk); ok && err == nil {
+		a.fileID = chunk.FileId
+	}
+	return err
+}
+
+// AuditStreamInterceptor audits UploadFile streams that fail. Streams that
+// complete the upload are audited by finishUpload, and streams that end
+// cleanly before the last chunk leave the upload to be resumed.
+func (s *UploadService) AuditStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if path.Base(info.FullMethod) != "UploadFile" {
+			return handler(srv, ss)
+		}
+		as := &auditStream{ServerStream: ss}
+		err := handler(srv, as)
+		if err != nil {
+			s.audit(ss.Context(), "complete", as.fileID, auditResult(err), "")
+		}
+		return err
+	}
+}
+
+// ListAuditEvents returns audit events for administrators, newest first
+func (s *UploadService) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	s = s.scoped(ctx)
+	if _, err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	f := AuditFilter{
+		Actor:  req.Actor,
+		Action: req.Action,
+		FileID: req.FileId,
+		Result: req.Result,
+		Limit:  int(req.PageSize),
+	}
+	if req.Since > 0 {
+		f.Since = time.Unix(req.Since, 0)
+	}
+	if req.Until > 0 {
+		f.Until = time.Unix(req.Until, 0)
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultAuditPageSize
+	}
+	if f.Limit > maxAuditPageSize {
+		f.Limit = maxAuditPageSize
+	}
+	if req.PageToken != "" {
+		cursor, err := strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+		f.Cursor = cursor
+	}
+
+	events, err := s.db.ListAuditEvents(f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+
+	resp := &pb.ListAuditEventsResponse{}
+	for _, e := range events {
+		resp.Events = append(resp.Events, &pb.AuditEvent{
+			Id:         e.ID,
+			OccurredAt: e.OccurredAt.Unix(),
+			Actor:      e.Actor,
+			Action:     e.Action,
+			FileId:     e.FileID,
+			PeerIp:     e.PeerIP,
+			UserAgent:  e.UserAgent,
+			Result:     e.Result,
+			Detail:     e.Detail,
+		})
+	}
+	if len(events) == f.Limit {
+		resp.NextPageToken = strconv.FormatInt(events[len(events)-1].ID, 10)
+	}
+	return resp, nil
+}
@@ -2,12 +2,14 @@ package server
 
 import (
 	"context"
+	"net"
 	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -20,20 +22,37 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// caller is the identity established from a bearer JWT
+type caller struct {
+	UserID string
+	Tenant string // tenant claim, or the default tenant
+	Groups []string
+	Admin  bool // role claim is "admin"
+}
+
 func (s *UploadService) validateJWT(ctx context.Context) (string, error) {
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+	return c.UserID, nil
+}
+
+// authenticate validates the bearer JWT and returns the caller's user ID and groups
+func (s *UploadService) authenticate(ctx context.Context) (*caller, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return "", status.Errorf(codes.Unauthenticated, "missing metadata")
+		return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
 	}
 
 	authHeaders := md.Get("authorization")
 	if len(authHeaders) == 0 {
-		return "", status.Errorf(codes.Unauthenticated, "missing authorization header")
+		return nil, status.Errorf(codes.Unauthenticated, "missing authorization header")
 	}
 
 	authHeader := authHeaders[0]
 	if !strings.HasPrefix(authHeader, "Bearer ") {
-		return "", status.Errorf(codes.Unauthenticated, "invalid authorization format")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid authorization format")
 	}
 
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
@@ -45,18 +64,90 @@ func (s *UploadService) validateJWT(ctx context.Context) (string, error) {
 	})
 
 	if err != nil || !token.Valid {
-		return "", status.Errorf(codes.Unauthenticated, "invalid token")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", status.Errorf(codes.Unauthenticated, "invalid claims")
+		return nil, status.Errorf(codes.Unauthenticated, "invalid claims")
 	}
 
 	userID, ok := claims["user_id"].(string)
 	if !ok {
-		return "", status.Errorf(codes.Unauthenticated, "missing user_id in token")
+		return nil, status.Errorf(codes.Unauthenticated, "missing user_id in token")
+	}
+
+	c := &caller{UserID: userID, Tenant: defaultTenantID}
+	if tenant, ok := claims["tenant"].(string); ok && tenant != "" {
+		c.Tenant = tenant
+	}
+	if role, ok := claims["role"].(string); ok && role == "admin" {
+		c.Admin = true
+	}
+	if groups, ok := claims["groups"].([]interface{}); ok {
+		for _, g := range groups {
+			if name, ok := g.(string); ok && name != "" {
+				c.Groups = append(c.Groups, name)
+			}
+		}
+	}
+	return c, nil
+}
+
+// requireAdmin authenticates the caller and checks the admin role
+func (s *UploadService) requireAdmin(ctx context.Context) (*caller, error) {
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !c.Admin {
+		return nil, status.Errorf(codes.PermissionDenied, "admin role required")
 	}
+	return c, nil
+}
+
+// SetTrustedProxies configures the addresses, such as the gateway's, whose
+// x-forwarded-for metadata is believed
+func (s *UploadService) SetTrustedProxies(nets []*net.IPNet) {
+	s.trustedProxies = nets
+}
+
+func (s *UploadService) trustedProxy(ip net.IP) bool {
+	for _, n := range s.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
 
-	return userID, nil
-}
\ No newline at end of file
+// clientIP returns the address of the caller. Forwarded addresses are only
+// taken from trusted proxies, reading x-forwarded-for from the right so a
+// client cannot claim an address by sending the header itself.
+func (s *UploadService) clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var hops []string
+	for _, v := range md.Get("x-forwarded-for") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(addr)
+		if ip == nil || !s.trustedProxy(ip) {
+			break
+		}
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		addr = hop
+	}
+	return addr
+}
@@ -0,0 +1,355 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Completed files are stored once per distinct content under
+// storage/blobs/<aa>/<bb>/<sha256>, where the hash is over the plaintext.
+// The blobs table counts the uploads referencing each blob; a blob whose
+// count drops to zero is removed by CollectBlobs after a grace period.
+//
+// An encrypted blob keeps the data key it was first written with, and every
+// upload referencing it carries a copy of that key, so downloads read the key
+// from the upload as before. A blob written in plaintext stays plaintext even
+// after encryption at rest is enabled.
+
+// Blob is a content-addressed stored file
+type Blob struct {
+	SHA256     string
+	Size       int64
+	StoredPath string
+	KeyID      string
+	WrappedKey []byte
+}
+
+// blobPath returns where the blob with the given hex SHA-256 is stored
+func blobPath(root, sum string) string {
+	return filepath.Join(root, "blobs", sum[:2], sum[2:4], sum)
+}
+
+// CommitBlob marks an upload completed and makes it reference the blob with
+// the given hash. If no such blob exists, mergedPath is moved into the blob
+// store and becomes it, wrapped by the upload's data key. Otherwise the
+// merged file is a duplicate and is removed, and the upload adopts the
+// existing blob's data key. It returns the blob the upload now references.
+func (db *UploadDB) CommitBlob(rec *UploadRecord, mergedPath, root, sum string, size int64) (*Blob, error) {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The insert takes the row lock, so concurrent uploads of the same
+	// content wait here until the first one has moved its file into place
+	blob := &Blob{SHA256: sum, Size: size}
+	var inserted bool
+	err = tx.QueryRow(ctx,
+		`INSERT INTO blobs(sha256, size_bytes, stored_path, ref_count, enc_key_id, enc_wrapped_key)
+		 VALUES($1, $2, $3, 1, NULLIF($4, ''), $5)
+		 ON CONFLICT (tenant_id, sha256) DO UPDATE SET ref_count = blobs.ref_count + 1, unreferenced_at = NULL
+		 RETURNING stored_path, COALESCE(enc_key_id, ''), enc_wrapped_key, (xmax = 0)`,
+		sum, size, blobPath(root, sum), rec.KeyID, rec.WrappedKey,
+	).Scan(&blob.StoredPath, &blob.KeyID, &blob.WrappedKey, &inserted)
+	if err != nil {
+		return nil, err
+	}
+
+	if inserted {
+		if err := os.MkdirAll(filepath.Dir(blob.StoredPath), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(mergedPath, blob.StoredPath); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE uploads SET status='completed', stored_path=$1, sha256=$2, size_bytes=$3,
+		        enc_key_id=NULLIF($4, ''), enc_wrapped_key=$5
+		 WHERE file_id=$6`,
+		blob.StoredPath, sum, size, blob.KeyID, blob.WrappedKey, rec.FileID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	if !inserted {
+		os.Remove(mergedPath)
+	}
+	return blob, nil
+}
+
+// FindReadableBlob returns the blob with the given hash and size if the
+// user owns, or holds a read grant on, a completed upload of it. The mime
+// type detected for that upload is returned with it. It returns nil when
+// there is no such blob.
+func (db *UploadDB) FindReadableBlob(sum string, size int64, userID string, groups []string) (*Blob, string, error) {
+	blob := &Blob{SHA256: sum, Size: size}
+	var mimeType string
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT b.stored_path, COALESCE(b.enc_key_id, ''), b.enc_wrapped_key, COALESCE(u.mime_type, '')
+		 FROM blobs b JOIN uploads u ON u.sha256 = b.sha256 AND u.tenant_id = b.tenant_id
+		 WHERE b.tenant_id = `+currentTenantSQL+` AND b.sha256=$1 AND b.size_bytes=$2 AND b.ref_count > 0 AND u.status='completed'
+		   AND (u.user_id::text = $3
+		     OR EXISTS (SELECT 1 FROM file_grants g
+		                WHERE g.file_id = u.file_id AND 'read' = ANY(g.permissions)
+		                  AND (g.expires_at IS NULL OR g.expires_at > now())
+		                  AND ((g.grantee_type='user' AND g.grantee_id=$3)
+		                    OR (g.grantee_type='group' AND g.grantee_id = ANY($4)))))
+		 LIMIT 1`,
+		sum, size, userID, groups,
+	).Scan(&blob.StoredPath, &blob.KeyID, &blob.WrappedKey, &mimeType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return blob, mimeType, nil
+}
+
+// CreateBlobUpload inserts an upload that is completed from the start and
+// references an existing blob, and sets rec.Version. It reports false,
+// inserting nothing, if the blob has been released and collected in the
+// meantime.
+func (db *UploadDB) CreateBlobUpload(rec *UploadRecord, blob *Blob, totalChunks int64, mimeType string) (bool, error) {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE blobs SET ref_count = ref_count + 1, unreferenced_at = NULL WHERE tenant_id = `+currentTenantSQL+` AND sha256=$1`,
+		blob.SHA256,
+	)
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO uploads(file_id, user_id, file_name, total_chunks, status, stored_path, sha256, size_bytes,
+		                     mime_type, enc_key_id, enc_wrapped_key, client_enc_scheme, client_wrapped_key, client_key_id,
+		                     version_of, version, restored_from, folder_id, metadata, tags)
+		 VALUES($1, $2, $3, $4, 'completed', $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10,
+		        NULLIF($11, ''), $12, NULLIF($13, ''),
+		        NULLIF($14::text, '')::uuid, `+fmt.Sprintf(nextVersionSQL, "$14")+`, NULLIF($15::text, '')::uuid,
+		        NULLIF($16::text, '')::uuid, `+metadataValuesSQL("$17", "$18")+`)
+		 RETURNING version`,
+		rec.FileID, rec.UserID, rec.FileName, totalChunks, blob.StoredPath, blob.SHA256, blob.Size,
+		mimeType, blob.KeyID, blob.WrappedKey, rec.ClientEncScheme, rec.ClientWrappedKey, rec.ClientKeyID,
+		rec.VersionOf, rec.RestoredFrom, rec.FolderID, rec.Metadata, rec.Tags,
+	).Scan(&rec.Version)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// DeleteUpload removes an upload record and releases its blob reference
+func (db *UploadDB) DeleteUpload(fileID string) error {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := releaseManifest(ctx, tx, fileID); err != nil {
+		return err
+	}
+
+	var sum *string
+	err = tx.QueryRow(ctx,
+		`DELETE FROM uploads WHERE file_id=$1 RETURNING sha256`,
+		fileID,
+	).Scan(&sum)
+	if err != nil {
+		return err
+	}
+	if sum != nil {
+		if err := releaseBlob(ctx, tx, *sum); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// releaseBlob drops one reference to a blob
+func releaseBlob(ctx context.Context, tx pgx.Tx, sum string) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE blobs SET ref_count = ref_count - 1,
+		        unreferenced_at = CASE WHEN ref_count = 1 THEN now() ELSE unreferenced_at END
+		 WHERE tenant_id = `+currentTenantSQL+` AND sha256=$1 AND ref_count > 0`,
+		sum,
+	)
+	return err
+}
+
+// CollectBlobs removes blobs that have been unreferenced for longer than
+// grace, deleting the file and the row together. It returns the number of
+// blobs removed.
+func (db *UploadDB) CollectBlobs(grace time.Duration) (int, error) {
+	return db.collectUnreferenced("blobs", grace)
+}
+
+// collectUnreferenced removes the unreferenced rows of a content-addressed
+// table (blobs or content_chunks) together with their files
+func (db *UploadDB) collectUnreferenced(table string, grace time.Duration) (int, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT sha256 FROM `+table+` WHERE tenant_id = `+currentTenantSQL+` AND ref_count = 0 AND unreferenced_at < $1`,
+		time.Now().Add(-grace),
+	)
+	if err != nil {
+		return 0, err
+	}
+	var candidates []string
+	for rows.Next() {
+		var sum string
+		if err := rows.Scan(&sum); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		candidates = append(candidates, sum)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, sum := range candidates {
+		ok, err := db.collectOne(table, sum)
+		if err != nil {
+			return removed, err
+		}
+		if ok {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// collectOne removes one row and its file if it is still unreferenced. The
+// row stays locked while the file is removed so a concurrent upload of the
+// same content cannot reference it in between.
+func (db *UploadDB) collectOne(table, sum string) (bool, error) {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var storedPath string
+	err = tx.QueryRow(ctx,
+		`SELECT stored_path FROM `+table+` WHERE tenant_id = `+currentTenantSQL+` AND sha256=$1 AND ref_count = 0 FOR UPDATE`,
+		sum,
+	).Scan(&storedPath)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.Remove(storedPath); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE tenant_id = `+currentTenantSQL+` AND sha256=$1`, sum); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// blobHeadSize is how much of an existing blob is sniffed before a new
+// upload may reference it
+const blobHeadSize = 4096
+
+// initFromBlob completes rec without any chunks when the caller already has
+// read access to identical content. It reports false when the upload has to
+// be streamed as usual.
+func (s *UploadService) initFromBlob(c *caller, rec *UploadRecord, sum string, size, totalChunks int64) (bool, error) {
+	blob, mimeType, err := s.db.FindReadableBlob(sum, size, c.UserID, c.Groups)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "blob lookup error: %v", err)
+	}
+	if blob == nil {
+		return false, nil
+	}
+
+	// The stored content must pass the policy for the new name too
+	key, err := s.unwrapKey(blob.KeyID, blob.WrappedKey)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to unwrap data key: %v", err)
+	}
+	sf, err := openStored(blob.StoredPath, key, fileStreamID)
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to open blob: %v", err)
+	}
+	head := make([]byte, blobHeadSize)
+	n, err := sf.ReadAt(head, 0)
+	sf.Close()
+	if err != nil && err != io.EOF {
+		return false, status.Errorf(codes.Internal, "failed to read blob: %v", err)
+	}
+	if _, err := s.policy.rulesFor(rec.UserID).checkContent(rec.FileName, head[:n]); err != nil {
+		return false, err
+	}
+
+	return s.db.CreateBlobUpload(rec, blob, totalChunks, mimeType)
+}
+
+// RunBlobGC collects unreferenced blobs and content chunks every interval
+// until ctx is done
+func (s *UploadService) RunBlobGC(ctx context.Context, interval, grace time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// Hashes are only unique within a tenant
+		s.eachTenant(func(ts *UploadService) { ts.collectGarbage(grace) })
+	}
+}
+
+// collectGarbage removes the unreferenced blobs and content chunks of the
+// service's tenant
+func (s *UploadService) collectGarbage(grace time.Duration) {
+	removed, err := s.db.CollectBlobs(grace)
+	if err != nil {
+		log.Printf("BlobGC error: tenant_id=%s, removed=%d, error=%v", s.tenant.ID, removed, err)
+		return
+	}
+	chunks, err := s.db.CollectContentChunks(grace)
+	if err != nil {
+		log.Printf("BlobGC chunk error: tenant_id=%s, removed=%d, error=%v", s.tenant.ID, chunks, err)
+		return
+	}
+	if removed > 0 || chunks > 0 {
+		log.Printf("BlobGC success: tenant_id=%s, blobs=%d, chunks=%d", s.tenant.ID, removed, chunks)
+	}
+}
@@ -0,0 +1,562 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// Chunk-level deduplication stores content-defined chunks once under
+// storage/chunks/<aa>/<bb>/<sha256> and files as ordered manifests of chunk
+// hashes. content_chunk_users records which users have sent each chunk's
+// bytes: FindMissingChunks and CommitManifest only count chunks the caller
+// has uploaded, so a hash alone never reveals or grants access to another
+// user's content. Storage is still shared across users.
+const (
+	maxContentChunkSize = 8 << 20
+	maxFindHashes       = 10000
+	maxManifestChunks   = 1 << 20
+
+	// contentChunkStreamID identifies a content chunk in segment AAD; every
+	// chunk has its own data key
+	contentChunkStreamID = "content"
+)
+
+var errUnknownChunks = errors.New("manifest references chunks that were not uploaded")
+
+// ManifestEntry is one chunk of a manifest upload
+type ManifestEntry struct {
+	SHA256 string
+	Size   int64
+}
+
+// manifestPart locates a manifest chunk within the assembled file
+type manifestPart struct {
+	Offset     int64
+	Size       int64
+	StoredPath string
+	KeyID      string
+	WrappedKey []byte
+}
+
+// contentChunkPath returns where the content chunk with the given hex SHA-256 is stored
+func contentChunkPath(root, sum string) string {
+	return filepath.Join(root, "chunks", sum[:2], sum[2:4], sum)
+}
+
+// FindMissingChunks returns the hashes the user has not uploaded yet
+func (db *UploadDB) FindMissingChunks(userID string, hashes []string) ([]string, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT h FROM unnest($1::text[]) AS h
+		 WHERE NOT EXISTS (SELECT 1 FROM content_chunk_users u
+		                   WHERE u.tenant_id = `+currentTenantSQL+` AND u.sha256 = h AND u.user_id = $2)`,
+		hashes, userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missing []string
+	for rows.Next() {
+		var h string
+		if err := rows.Scan(&h); err != nil {
+			return nil, err
+		}
+		missing = append(missing, h)
+	}
+	return missing, rows.Err()
+}
+
+// PutContentChunk records that userID uploaded the chunk with the given
+// hash. If the server does not have it yet, write is called to store it at
+// storedPath while the row is locked. It reports whether the chunk was new.
+func (db *UploadDB) PutContentChunk(userID, sum string, size int64, storedPath, keyID string, wrapped []byte, write func() error) (bool, error) {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// New chunks count as unreferenced until a manifest uses them, so chunks
+	// of abandoned uploads are collected like released ones
+	var inserted bool
+	err = tx.QueryRow(ctx,
+		`INSERT INTO content_chunks(sha256, size_bytes, stored_path, ref_count, enc_key_id, enc_wrapped_key, unreferenced_at)
+		 VALUES($1, $2, $3, 0, NULLIF($4, ''), $5, now())
+		 ON CONFLICT (tenant_id, sha256) DO UPDATE SET sha256 = EXCLUDED.sha256
+		 RETURNING (xmax = 0)`,
+		sum, size, storedPath, keyID, wrapped,
+	).Scan(&inserted)
+	if err != nil {
+		return false, err
+	}
+	if inserted {
+		if err := write(); err != nil {
+			return false, err
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO content_chunk_users(sha256, user_id) VALUES($1, $2) ON CONFLICT DO NOTHING`,
+		sum, userID,
+	)
+	if err != nil {
+		return false, err
+	}
+	return inserted, tx.Commit(ctx)
+}
+
+// CommitManifest stores the chunk list of an upload and takes a reference on
+// every chunk. All chunks must have been uploaded by userID with matching
+// sizes. It returns the total size of the file.
+func (db *UploadDB) CommitManifest(fileID, userID string, entries []ManifestEntry) (int64, error) {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	hashes := make([]string, len(entries))
+	sizes := make([]int64, len(entries))
+	distinct := make(map[string]bool)
+	var total int64
+	for i, e := range entries {
+		hashes[i], sizes[i] = e.SHA256, e.Size
+		distinct[e.SHA256] = true
+		total += e.Size
+	}
+
+	// Lock the chunks so they cannot be collected before the references land
+	var known int
+	err = tx.QueryRow(ctx,
+		`SELECT count(*) FROM (
+		   SELECT c.sha256 FROM content_chunks c
+		   JOIN content_chunk_users u ON u.tenant_id = c.tenant_id AND u.sha256 = c.sha256 AND u.user_id = $2
+		   JOIN (SELECT DISTINCT h, s FROM unnest($1::text[], $3::bigint[]) AS m(h, s)) m
+		     ON m.h = c.sha256 AND m.s = c.size_bytes
+		   WHERE c.tenant_id = `+currentTenantSQL+`
+		   FOR UPDATE OF c
+		 ) known`,
+		hashes, userID, sizes,
+	).Scan(&known)
+	if err != nil {
+		return 0, err
+	}
+	if known != len(distinct) {
+		return 0, errUnknownChunks
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE content_chunks c SET ref_count = c.ref_count + m.n, unreferenced_at = NULL
+		 FROM (SELECT h, count(*) AS n FROM unnest($1::text[]) AS h GROUP BY h) m
+		 WHERE c.tenant_id = `+currentTenantSQL+` AND c.sha256 = m.h`,
+		hashes,
+	)
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO file_manifests(file_id, seq, chunk_sha256, size_bytes)
+		 SELECT $1, t.ord - 1, t.h, t.s FROM unnest($2::text[], $3::bigint[]) WITH ORDINALITY AS t(h, s, ord)`,
+		fileID, hashes, sizes,
+	)
+	if err != nil {
+		return 0, err
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE uploads SET manifest=true, size_bytes=$1, total_chunks=$2 WHERE file_id=$3`,
+		total, len(entries), fileID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return total, tx.Commit(ctx)
+}
+
+// CompleteManifestUpload marks a manifest upload completed
+func (db *UploadDB) CompleteManifestUpload(fileID string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE uploads SET status='completed' WHERE file_id=$1`,
+		fileID,
+	)
+	return err
+}
+
+// ManifestParts returns the chunks of a manifest upload in file order
+func (db *UploadDB) ManifestParts(fileID string) ([]manifestPart, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT m.size_bytes, c.stored_path, COALESCE(c.enc_key_id, ''), c.enc_wrapped_key
+		 FROM file_manifests m JOIN content_chunks c ON c.tenant_id = m.tenant_id AND c.sha256 = m.chunk_sha256
+		 WHERE m.file_id=$1 ORDER BY m.seq`,
+		fileID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var parts []manifestPart
+	var offset int64
+	for rows.Next() {
+		p := manifestPart{Offset: offset}
+		if err := rows.Scan(&p.Size, &p.StoredPath, &p.KeyID, &p.WrappedKey); err != nil {
+			return nil, err
+		}
+		offset += p.Size
+		parts = append(parts, p)
+	}
+	return parts, rows.Err()
+}
+
+// releaseManifest drops the chunk references held by a manifest upload
+func releaseManifest(ctx context.Context, tx pgx.Tx, fileID string) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE content_chunks c SET ref_count = c.ref_count - m.n,
+		        unreferenced_at = CASE WHEN c.ref_count = m.n THEN now() ELSE c.unreferenced_at END
+		 FROM (SELECT tenant_id, chunk_sha256, count(*) AS n FROM file_manifests WHERE file_id=$1 GROUP BY tenant_id, chunk_sha256) m
+		 WHERE c.tenant_id = m.tenant_id AND c.sha256 = m.chunk_sha256`,
+		fileID,
+	)
+	return err
+}
+
+// CollectContentChunks removes content chunks that have been unreferenced for
+// longer than grace
+func (db *UploadDB) CollectContentChunks(grace time.Duration) (int, error) {
+	return db.collectUnreferenced("content_chunks", grace)
+}
+
+// manifestFile reads the plaintext of a manifest upload across its chunks
+type manifestFile struct {
+	s     *UploadService
+	parts []manifestPart
+	size  int64
+
+	mu      sync.Mutex
+	current int // index of the open part, -1 if none
+	open    *storedFile
+}
+
+// openManifest opens a manifest upload for reading
+func (s *UploadService) openManifest(fileID string) (*manifestFile, error) {
+	parts, err := s.db.ManifestParts(fileID)
+	if err != nil {
+		return nil, err
+	}
+	var size int64
+	if len(parts) > 0 {
+		last := parts[len(parts)-1]
+		size = last.Offset + last.Size
+	}
+	return &manifestFile{s: s, parts: parts, size: size, current: -1}, nil
+}
+
+func (m *manifestFile) Size() int64 { return m.size }
+
+func (m *manifestFile) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.open != nil {
+		m.current = -1
+		return m.open.Close()
+	}
+	return nil
+}
+
+// part returns the opened chunk at index i, reusing the last one opened
+func (m *manifestFile) part(i int) (*storedFile, error) {
+	if m.current == i {
+		return m.open, nil
+	}
+	if m.open != nil {
+		m.open.Close()
+		m.open, m.current = nil, -1
+	}
+	p := m.parts[i]
+	key, err := m.s.unwrapKey(p.KeyID, p.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	sf, err := openStored(p.StoredPath, key, contentChunkStreamID)
+	if err != nil {
+		return nil, err
+	}
+	if sf.Size() != p.Size {
+		sf.Close()
+		return nil, fmt.Errorf("content chunk %s has size %d, expected %d", filepath.Base(p.StoredPath), sf.Size(), p.Size)
+	}
+	m.open, m.current = sf, i
+	return sf, nil
+}
+
+// ReadAt implements io.ReaderAt over the assembled file
+func (m *manifestFile) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	i := sort.Search(len(m.parts), func(i int) bool { return m.parts[i].Offset+m.parts[i].Size > off })
+	for n < len(p) && i < len(m.parts) {
+		sf, err := m.part(i)
+		if err != nil {
+			return n, err
+		}
+		part := m.parts[i]
+		want := p[n:]
+		if rest := part.Size - (off - part.Offset); int64(len(want)) > rest {
+			want = want[:rest]
+		}
+		c, err := sf.ReadAt(want, off-part.Offset)
+		n += c
+		off += int64(c)
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+		if c < len(want) {
+			return n, io.ErrUnexpectedEOF
+		}
+		i++
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// uploadContent is random access to the plaintext of a completed upload
+type uploadContent interface {
+	io.ReaderAt
+	Size() int64
+	Close() error
+}
+
+// openUpload opens the content of a completed upload, whether it is a single
+// stored file or a manifest of content chunks
+func (s *UploadService) openUpload(rec *UploadRecord) (uploadContent, error) {
+	if rec.Manifest {
+		return s.openManifest(rec.FileID)
+	}
+	key, err := s.dataKey(rec)
+	if err != nil {
+		return nil, err
+	}
+	return openStored(rec.StoredPath, key, fileStreamID)
+}
+
+// FindMissingChunks reports which chunk hashes the caller still has to upload
+func (s *UploadService) FindMissingChunks(ctx context.Context, req *pb.FindMissingChunksRequest) (*pb.FindMissingChunksResponse, error) {
+	s = s.scoped(ctx)
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Hashes) > maxFindHashes {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d hashes per request", maxFindHashes)
+	}
+	for _, h := range req.Hashes {
+		if !validSHA256(h) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid chunk hash %q", h)
+		}
+	}
+
+	missing, err := s.db.FindMissingChunks(userID, req.Hashes)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	return &pb.FindMissingChunksResponse{Missing: missing}, nil
+}
+
+// PutChunks receives content chunks, verifying each against its hash
+func (s *UploadService) PutChunks(stream pb.FileUploadService_PutChunksServer) error {
+	s = s.scoped(stream.Context())
+	userID, err := s.validateJWT(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	var received, stored int64
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "recv chunk error: %v", err)
+		}
+		if !validSHA256(chunk.Hash) {
+			return status.Errorf(codes.InvalidArgument, "invalid chunk hash %q", chunk.Hash)
+		}
+		if len(chunk.Content) == 0 || len(chunk.Content) > maxContentChunkSize {
+			return status.Errorf(codes.InvalidArgument, "chunk size must be between 1 and %d bytes", maxContentChunkSize)
+		}
+		sum := sha256.Sum256(chunk.Content)
+		if hex.EncodeToString(sum[:]) != chunk.Hash {
+			return status.Errorf(codes.InvalidArgument, "content does not match hash %s", chunk.Hash)
+		}
+
+		isNew, err := s.storeContentChunk(userID, chunk.Hash, chunk.Content)
+		if err != nil {
+			log.Printf("PutChunks error: user_id=%s, hash=%s, error=%v", userID, chunk.Hash, err)
+			return status.Errorf(codes.Internal, "failed to store chunk: %v", err)
+		}
+		received++
+		if isNew {
+			stored++
+		}
+	}
+
+	log.Printf("PutChunks success: user_id=%s, received=%d, stored=%d", userID, received, stored)
+	return stream.SendAndClose(&pb.PutChunksResponse{Received: received, Stored: stored})
+}
+
+// storeContentChunk writes a verified chunk into the chunk store unless it is already there
+func (s *UploadService) storeContentChunk(userID, sum string, content []byte) (bool, error) {
+	var keyID string
+	var wrapped, key []byte
+	if s.keys != nil {
+		var err error
+		if keyID, wrapped, err = newDataKey(s.keys); err != nil {
+			return false, err
+		}
+		if key, err = s.unwrapKey(keyID, wrapped); err != nil {
+			return false, err
+		}
+	}
+
+	storedPath := contentChunkPath(s.tempDir, sum)
+	return s.db.PutContentChunk(userID, sum, int64(len(content)), storedPath, keyID, wrapped, func() error {
+		if err := os.MkdirAll(filepath.Dir(storedPath), 0755); err != nil {
+			return err
+		}
+		tmp := storedPath + "." + uuid.NewString() + ".part"
+		if err := writeStored(tmp, content, key, contentChunkStreamID); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		return os.Rename(tmp, storedPath)
+	})
+}
+
+// CommitManifest completes an upload from chunks previously sent with PutChunks
+func (s *UploadService) CommitManifest(ctx context.Context, req *pb.CommitManifestRequest) (*pb.UploadStatus, error) {
+	s = s.scoped(ctx)
+	// Only whoever started the upload completes it, which for a new version
+	// may be a grantee rather than the file's owner
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if rec.UserID != userID {
+		return nil, status.Error(codes.PermissionDenied, "not the uploader of this file")
+	}
+	if rec.Status != "in_progress" || rec.Manifest {
+		return nil, status.Errorf(codes.FailedPrecondition, "upload is already %s", rec.Status)
+	}
+	if rec.ClientEncScheme != "" {
+		return nil, status.Error(codes.InvalidArgument, "client-side encrypted uploads must use UploadFile")
+	}
+	if len(req.Chunks) > maxManifestChunks {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d chunks per file", maxManifestChunks)
+	}
+
+	entries := make([]ManifestEntry, len(req.Chunks))
+	sizes := make(map[string]int64)
+	var total int64
+	for i, c := range req.Chunks {
+		if !validSHA256(c.Hash) || c.Size <= 0 || c.Size > maxContentChunkSize {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid manifest entry %d", i)
+		}
+		if size, ok := sizes[c.Hash]; ok && size != c.Size {
+			return nil, status.Errorf(codes.InvalidArgument, "manifest entry %d repeats chunk %s with a different size", i, c.Hash)
+		}
+		sizes[c.Hash] = c.Size
+		entries[i] = ManifestEntry{SHA256: c.Hash, Size: c.Size}
+		total += c.Size
+	}
+	if err := s.checkQuota(total); err != nil {
+		return nil, err
+	}
+
+	size, err := s.db.CommitManifest(rec.FileID, userID, entries)
+	if errors.Is(err, errUnknownChunks) {
+		return nil, status.Error(codes.FailedPrecondition, "manifest references chunks that were not uploaded; call FindMissingChunks")
+	}
+	if err != nil {
+		log.Printf("CommitManifest error: file_id=%s, error=%v", rec.FileID, err)
+		return nil, status.Errorf(codes.Internal, "failed to store manifest: %v", err)
+	}
+	rec.Manifest = true
+	s.mergeStarted(rec.FileID, int64(len(entries)))
+
+	content, err := s.openUpload(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to open manifest: %v", err)
+	}
+	defer content.Close()
+
+	// Same checks as a streamed upload: sniff the head, then scan the whole file
+	head := make([]byte, blobHeadSize)
+	n, err := content.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, status.Errorf(codes.Internal, "failed to read manifest: %v", err)
+	}
+	detected, err := s.policy.rulesFor(rec.UserID).checkContent(rec.FileName, head[:n])
+	if err != nil {
+		log.Printf("CommitManifest content rejected: file_id=%s, file_name=%s, detected=%s, error=%v", rec.FileID, rec.FileName, detected, err)
+		s.failUpload(rec.FileID, status.Convert(err).Message())
+		return nil, err
+	}
+	if err := s.db.SetMimeType(rec.FileID, detected); err != nil {
+		log.Printf("CommitManifest mime_type update error: file_id=%s, error=%v", rec.FileID, err)
+	}
+
+	result, err := s.scanContent(ctx, rec.FileID, rec.FileName, io.NewSectionReader(content, 0, content.Size()))
+	if err != nil {
+		log.Printf("CommitManifest scan error: file_id=%s, error=%v", rec.FileID, err)
+		reason, err := scanFailure(err)
+		s.failUpload(rec.FileID, reason)
+		return nil, err
+	}
+	if result != nil && !result.Clean {
+		// The chunks may be shared, so the file is blocked rather than moved
+		if err := s.db.QuarantineUpload(rec.FileID, ""); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to update upload status: %v", err)
+		}
+		s.uploadQuarantined(rec.FileID, result.Signature)
+		log.Printf("CommitManifest quarantined: file_id=%s, signature=%s, engine=%s", rec.FileID, result.Signature, result.Engine)
+		return &pb.UploadStatus{Success: false, Message: "file quarantined: " + result.Signature}, nil
+	}
+
+	if err := s.db.CompleteManifestUpload(rec.FileID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update upload status: %v", err)
+	}
+
+	s.versionCompleted(ctx, rec)
+	log.Printf("CommitManifest success: file_id=%s, chunks=%d, size=%d", rec.FileID, len(entries), size)
+	return &pb.UploadStatus{Success: true, Message: "upload saved"}, nil
+}
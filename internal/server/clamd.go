@@ -0,0 +1,180 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const clamdChunkSize = 64 * 1024
+
+// clamdVersionTTL is how long a VERSION reply is reused. It changes when
+// freshclam loads new signatures, so it is refreshed periodically.
+const clamdVersionTTL = 10 * time.Minute
+
+// ClamdScanner talks to a clamd daemon using the INSTREAM protocol
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+
+	mu        sync.Mutex
+	version   string
+	versionAt time.Time
+}
+
+// NewClamdScanner creates a clamd client. addr is "tcp://host:port",
+// "unix:///path/to/clamd.sock" or a bare host:port.
+func NewClamdScanner(addr string, timeout time.Duration) *ClamdScanner {
+	network, address := "tcp", addr
+	if rest, ok := strings.CutPrefix(addr, "unix://"); ok {
+		network, address = "unix", rest
+	} else if rest, ok := strings.CutPrefix(addr, "tcp://"); ok {
+		address = rest
+	}
+	return &ClamdScanner{network: network, address: address, timeout: timeout}
+}
+
+func (c *ClamdScanner) dial(ctx context.Context) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	return conn, nil
+}
+
+// command sends a null-terminated clamd command and returns the reply
+func (c *ClamdScanner) command(ctx context.Context, cmd string) (string, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("z" + cmd + "\x00")); err != nil {
+		return "", err
+	}
+	return readClamdReply(conn)
+}
+
+// Version returns the clamd engine and signature database version. The
+// reply is cached for clamdVersionTTL.
+func (c *ClamdScanner) Version(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != "" && time.Since(c.versionAt) < clamdVersionTTL {
+		return c.version, nil
+	}
+	version, err := c.command(ctx, "VERSION")
+	if err != nil {
+		return "", err
+	}
+	c.version, c.versionAt = version, time.Now()
+	return version, nil
+}
+
+// Scan implements Scanner by streaming the content to clamd
+func (c *ClamdScanner) Scan(ctx context.Context, name string, r io.Reader) (*ScanResult, error) {
+	version, err := c.Version(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("clamd version: %w", err)
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return nil, streamError(conn, err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, streamError(conn, err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	// A zero-length chunk terminates the stream
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return nil, streamError(conn, err)
+	}
+
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return nil, err
+	}
+	return parseClamdReply(reply, version)
+}
+
+// streamError explains a failed INSTREAM write. clamd replies and closes the
+// connection when it rejects a stream, most often because it exceeds
+// StreamMaxLength, so the reply is read before giving up.
+func streamError(conn net.Conn, err error) error {
+	reply, rerr := readClamdReply(conn)
+	if rerr != nil || reply == "" {
+		return err
+	}
+	if isClamdSizeLimit(reply) {
+		return ErrScanTooLarge
+	}
+	return fmt.Errorf("clamd: %s", reply)
+}
+
+// isClamdSizeLimit reports whether a reply is "INSTREAM size limit exceeded"
+func isClamdSizeLimit(reply string) bool {
+	return strings.Contains(reply, "size limit exceeded")
+}
+
+func readClamdReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(reply, "\x00\n"), nil
+}
+
+// parseClamdReply interprets replies such as "stream: OK" and
+// "stream: Eicar-Signature FOUND"
+func parseClamdReply(reply, version string) (*ScanResult, error) {
+	result := &ScanResult{Engine: "clamav", Version: version}
+	_, verdict, _ := strings.Cut(reply, ": ")
+	switch {
+	case verdict == "OK":
+		result.Clean = true
+		return result, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		result.Signature = strings.TrimSuffix(verdict, " FOUND")
+		return result, nil
+	case isClamdSizeLimit(reply):
+		return nil, ErrScanTooLarge
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PolicyRules restrict which file types may be uploaded. Empty allow lists
+// allow everything that is not denied.
+type PolicyRules struct {
+	AllowedMIMETypes  []string `json:"allowed_mime_types"`
+	DeniedMIMETypes   []string `json:"denied_mime_types"`
+	AllowedExtensions []string `json:"allowed_extensions"`
+	DeniedExtensions  []string `json:"denied_extensions"`
+	// EnforceExtensionMatch rejects files whose sniffed type contradicts a
+	// well-known extension, e.g. an executable named report.pdf
+	EnforceExtensionMatch *bool `json:"enforce_extension_match,omitempty"`
+	// AllowClientEncryption accepts client-side encrypted uploads without
+	// sniffing their content, which is opaque. Off by default: the
+	// encryption scheme is then ignored and the first chunk sniffed as usual.
+	AllowClientEncryption *bool `json:"allow_client_encryption,omitempty"`
+}
+
+// ContentPolicy holds the deployment-wide rules and optional per-user overrides
+type ContentPolicy struct {
+	Default PolicyRules            `json:"default"`
+	Users   map[string]PolicyRules `json:"users"`
+}
+
+// LoadContentPolicy reads a JSON content policy file. An empty path yields
+// a policy that only enforces extension/content consistency.
+func LoadContentPolicy(path string) (*ContentPolicy, error) {
+	policy := &ContentPolicy{}
+	if path == "" {
+		return policy, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("parse content policy: %w", err)
+	}
+	return policy, nil
+}
+
+// rulesFor returns the effective rules for a user. Fields set in a user
+// override replace the deployment default.
+func (p *ContentPolicy) rulesFor(userID string) PolicyRules {
+	if p == nil {
+		return PolicyRules{}
+	}
+	rules := p.Default
+	if o, ok := p.Users[userID]; ok {
+		rules = rules.override(o)
+	}
+	return rules
+}
+
+// withOverrides layers another policy, such as a tenant's, over p. Fields
+// set in o replace those of p, for the default and per user.
+func (p *ContentPolicy) withOverrides(o *ContentPolicy) *ContentPolicy {
+	if o == nil {
+		return p
+	}
+	merged := &ContentPolicy{Users: map[string]PolicyRules{}}
+	if p != nil {
+		merged.Default = p.Default
+		for u, r := range p.Users {
+			merged.Users[u] = r
+		}
+	}
+	merged.Default = merged.Default.override(o.Default)
+	for u, r := range o.Users {
+		merged.Users[u] = merged.Users[u].override(r)
+	}
+	return merged
+}
+
+// override returns r with the fields set in o replaced
+func (r PolicyRules) override(o PolicyRules) PolicyRules {
+	if o.AllowedMIMETypes != nil {
+		r.AllowedMIMETypes = o.AllowedMIMETypes
+	}
+	if o.DeniedMIMETypes != nil {
+		r.DeniedMIMETypes = o.DeniedMIMETypes
+	}
+	if o.AllowedExtensions != nil {
+		r.AllowedExtensions = o.AllowedExtensions
+	}
+	if o.DeniedExtensions != nil {
+		r.DeniedExtensions = o.DeniedExtensions
+	}
+	if o.EnforceExtensionMatch != nil {
+		r.EnforceExtensionMatch = o.EnforceExtensionMatch
+	}
+	if o.AllowClientEncryption != nil {
+		r.AllowClientEncryption = o.AllowClientEncryption
+	}
+	return r
+}
+
+func (r PolicyRules) clientEncryptionAllowed() bool {
+	return r.AllowClientEncryption != nil && *r.AllowClientEncryption
+}
+
+// checkName validates a file name's extension against the rules
+func (r PolicyRules) checkName(fileName string) error {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if containsFold(r.DeniedExtensions, ext) {
+		return status.Errorf(codes.InvalidArgument, "file extension %q is not allowed", ext)
+	}
+	if len(r.AllowedExtensions) > 0 && !containsFold(r.AllowedExtensions, ext) {
+		return status.Errorf(codes.InvalidArgument, "file extension %q is not allowed", ext)
+	}
+	return nil
+}
+
+// checkEncrypted applies the rules that can be checked without the
+// plaintext of a client-side encrypted upload: the extension lists, and
+// refusing it outright when only some content types are allowed
+func (r PolicyRules) checkEncrypted(fileName string) error {
+	if err := r.checkName(fileName); err != nil {
+		return err
+	}
+	if len(r.AllowedMIMETypes) > 0 {
+		return status.Error(codes.InvalidArgument, "allowed content types cannot be verified for client-side encrypted uploads")
+	}
+	return nil
+}
+
+// checkContent validates the sniffed type of the first chunk against the
+// rules and the declared extension. It returns the detected MIME type.
+func (r PolicyRules) checkContent(fileName string, head []byte) (string, error) {
+	detected := sniffContentType(head)
+	if len(r.DeniedMIMETypes) > 0 && mimeAllowed(detected, r.DeniedMIMETypes) {
+		return detected, status.Errorf(codes.InvalidArgument, "content type %s is not allowed", detected)
+	}
+	if len(r.AllowedMIMETypes) > 0 && !mimeAllowed(detected, r.AllowedMIMETypes) {
+		return detected, status.Errorf(codes.InvalidArgument, "content type %s is not allowed", detected)
+	}
+	if r.EnforceExtensionMatch == nil || *r.EnforceExtensionMatch {
+		ext := strings.ToLower(filepath.Ext(fileName))
+		if expected, ok := extensionTypes[ext]; ok && !mimeAllowed(detected, expected) {
+			return detected, status.Errorf(codes.InvalidArgument, "file extension %q does not match detected content type %s", ext, detected)
+		}
+	}
+	return detected, nil
+}
+
+func containsFold(list []string, v string) bool {
+	for _, item := range list {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if !strings.HasPrefix(item, ".") {
+			item = "." + item
+		}
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// magic signatures checked before falling back to http.DetectContentType,
+// which does not recognise executables or most archive formats
+var magicTypes = []struct {
+	offset int
+	magic  []byte
+	mime   string
+}{
+	{0, []byte("MZ"), "application/x-msdownload"},
+	{0, []byte("\x7fELF"), "application/x-executable"},
+	{0, []byte{0xfe, 0xed, 0xfa, 0xce}, "application/x-mach-binary"},
+	{0, []byte{0xfe, 0xed, 0xfa, 0xcf}, "application/x-mach-binary"},
+	{0, []byte{0xcf, 0xfa, 0xed, 0xfe}, "application/x-mach-binary"},
+	{0, []byte{0xce, 0xfa, 0xed, 0xfe}, "application/x-mach-binary"},
+	{0, []byte{0xca, 0xfe, 0xba, 0xbe}, "application/x-mach-binary"},
+	{0, []byte("#!"), "text/x-shellscript"},
+	{0, []byte("%PDF-"), "application/pdf"},
+	{0, []byte("\x89PNG\r\n\x1a\n"), "image/png"},
+	{0, []byte{0xff, 0xd8, 0xff}, "image/jpeg"},
+	{0, []byte("GIF87a"), "image/gif"},
+	{0, []byte("GIF89a"), "image/gif"},
+	{0, []byte("PK\x03\x04"), "application/zip"},
+	{0, []byte("PK\x05\x06"), "application/zip"},
+	{0, []byte{0x1f, 0x8b}, "application/gzip"},
+	{0, []byte("BZh"), "application/x-bzip2"},
+	{0, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, "application/x-xz"},
+	{0, []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}, "application/x-7z-compressed"},
+	{0, []byte("Rar!\x1a\x07"), "application/vnd.rar"},
+	{0, []byte{0xd0, 0xcf, 0x11, 0xe0, 0xa1, 0xb1, 0x1a, 0xe1}, "application/x-ole-storage"},
+	{257, []byte("ustar"), "application/x-tar"},
+}
+
+// sniffContentType detects a MIME type from the leading bytes of a file
+func sniffContentType(head []byte) string {
+	for _, m := range magicTypes {
+		if len(head) >= m.offset+len(m.magic) && bytes.Equal(head[m.offset:m.offset+len(m.magic)], m.magic) {
+			return m.mime
+		}
+	}
+	detected, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	return detected
+}
+
+// extensionTypes lists the content types acceptable for well-known extensions
+var extensionTypes = map[string][]string{
+	".pdf":  {"application/pdf"},
+	".png":  {"image/png"},
+	".jpg":  {"image/jpeg"},
+	".jpeg": {"image/jpeg"},
+	".gif":  {"image/gif"},
+	".webp": {"image/webp"},
+	".bmp":  {"image/bmp"},
+	".zip":  {"application/zip"},
+	".docx": {"application/zip"},
+	".xlsx": {"application/zip"},
+	".pptx": {"application/zip"},
+	".doc":  {"application/x-ole-storage"},
+	".xls":  {"application/x-ole-storage"},
+	".ppt":  {"application/x-ole-storage"},
+	".gz":   {"application/gzip"},
+	".tgz":  {"application/gzip"},
+	".tar":  {"application/x-tar"},
+	".bz2":  {"application/x-bzip2"},
+	".xz":   {"application/x-xz"},
+	".7z":   {"application/x-7z-compressed"},
+	".rar":  {"application/vnd.rar"},
+	".txt":  {"text/plain"},
+	".csv":  {"text/plain"},
+	".md":   {"text/plain"},
+	".json": {"text/plain", "application/json"},
+	".html": {"text/html"},
+	".htm":  {"text/html"},
+	".mp3":  {"audio/mpeg"},
+	".mp4":  {"video/mp4"},
+	".exe":  {"application/x-msdownload"},
+	".dll":  {"application/x-msdownload"},
+}
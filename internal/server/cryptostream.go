@@ -0,0 +1,240 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Encrypted files are a small header followed by independently sealed
+// AES-256-GCM segments, so any byte range can be decrypted without reading
+// the whole file:
+//
+//	header:  "UBE1" | segment size (uint32)
+//	segment: nonce (12) | ciphertext (<= segment size) | tag (16)
+//
+// Each segment's additional data binds the stream ID (which chunk or file it
+// belongs to), its index and whether it is the last segment, preventing
+// segments from being reordered, swapped between files or truncated.
+const (
+	encMagic       = "UBE1"
+	encHeaderSize  = 8
+	encSegmentSize = 64 * 1024
+	encNonceSize   = 12
+	encTagSize     = 16
+	encOverhead    = encNonceSize + encTagSize
+)
+
+var errCorruptCiphertext = errors.New("encrypted file is corrupt")
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func segmentAAD(streamID string, index uint64, final bool) []byte {
+	aad := make([]byte, 0, len(streamID)+9)
+	aad = append(aad, streamID...)
+	aad = binary.BigEndian.AppendUint64(aad, index)
+	if final {
+		return append(aad, 1)
+	}
+	return append(aad, 0)
+}
+
+// segmentWriter encrypts everything written to it. Close must be called to
+// seal the final segment.
+type segmentWriter struct {
+	w        io.Writer
+	aead     cipher.AEAD
+	streamID string
+	buf      []byte
+	index    uint64
+}
+
+func newSegmentWriter(w io.Writer, key []byte, streamID string) (*segmentWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, encHeaderSize)
+	copy(header, encMagic)
+	binary.BigEndian.PutUint32(header[4:], encSegmentSize)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+	return &segmentWriter{w: w, aead: aead, streamID: streamID, buf: make([]byte, 0, encSegmentSize+1)}, nil
+}
+
+func (sw *segmentWriter) seal(plain []byte, final bool) error {
+	nonce := make([]byte, encNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	out := sw.aead.Seal(nonce, nonce, plain, segmentAAD(sw.streamID, sw.index, final))
+	sw.index++
+	_, err := sw.w.Write(out)
+	return err
+}
+
+func (sw *segmentWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := copy(sw.buf[len(sw.buf):cap(sw.buf)], p)
+		sw.buf = sw.buf[:len(sw.buf)+n]
+		p = p[n:]
+		// Keep one byte beyond a full segment buffered so the last segment
+		// is only sealed, with the final flag, on Close
+		if len(sw.buf) > encSegmentSize {
+			if err := sw.seal(sw.buf[:encSegmentSize], false); err != nil {
+				return 0, err
+			}
+			rest := copy(sw.buf, sw.buf[encSegmentSize:])
+			sw.buf = sw.buf[:rest]
+		}
+	}
+	return written, nil
+}
+
+func (sw *segmentWriter) Close() error {
+	return sw.seal(sw.buf, true)
+}
+
+// segmentReaderAt decrypts arbitrary ranges of an encrypted file
+type segmentReaderAt struct {
+	r        io.ReaderAt
+	aead     cipher.AEAD
+	streamID string
+	segments int64
+	size     int64 // plaintext size
+}
+
+func newSegmentReaderAt(r io.ReaderAt, cipherSize int64, key []byte, streamID string) (*segmentReaderAt, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, encHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, errCorruptCiphertext
+	}
+	if string(header[:4]) != encMagic || binary.BigEndian.Uint32(header[4:]) != encSegmentSize {
+		return nil, errCorruptCiphertext
+	}
+
+	body := cipherSize - encHeaderSize
+	full := int64(encSegmentSize + encOverhead)
+	segments := (body + full - 1) / full
+	if segments == 0 || body-(segments-1)*full < encOverhead {
+		return nil, errCorruptCiphertext
+	}
+	size := body - segments*encOverhead
+	return &segmentReaderAt{r: r, aead: aead, streamID: streamID, segments: segments, size: size}, nil
+}
+
+func (sr *segmentReaderAt) Size() int64 { return sr.size }
+
+func (sr *segmentReaderAt) readSegment(index int64) ([]byte, error) {
+	full := int64(encSegmentSize + encOverhead)
+	length := full
+	final := index == sr.segments-1
+	if final {
+		length = sr.size - index*encSegmentSize + encOverhead
+	}
+	buf := make([]byte, length)
+	if _, err := sr.r.ReadAt(buf, encHeaderSize+index*full); err != nil && err != io.EOF {
+		return nil, err
+	}
+	plain, err := sr.aead.Open(nil, buf[:encNonceSize], buf[encNonceSize:], segmentAAD(sr.streamID, uint64(index), final))
+	if err != nil {
+		return nil, errCorruptCiphertext
+	}
+	return plain, nil
+}
+
+// ReadAt implements io.ReaderAt over the plaintext
+func (sr *segmentReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset")
+	}
+	n := 0
+	for n < len(p) && off < sr.size {
+		index := off / encSegmentSize
+		plain, err := sr.readSegment(index)
+		if err != nil {
+			return n, err
+		}
+		c := copy(p[n:], plain[off-index*encSegmentSize:])
+		n += c
+		off += int64(c)
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// storedFile gives uniform random access to plaintext or encrypted files
+type storedFile struct {
+	f    *os.File
+	r    io.ReaderAt
+	size int64
+}
+
+// openStored opens a stored chunk or file, decrypting it when key is set
+func openStored(path string, key []byte, streamID string) (*storedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if key == nil {
+		return &storedFile{f: f, r: f, size: fi.Size()}, nil
+	}
+	sr, err := newSegmentReaderAt(f, fi.Size(), key, streamID)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &storedFile{f: f, r: sr, size: sr.Size()}, nil
+}
+
+func (sf *storedFile) ReadAt(p []byte, off int64) (int, error) { return sf.r.ReadAt(p, off) }
+func (sf *storedFile) Size() int64                             { return sf.size }
+func (sf *storedFile) Close() error                            { return sf.f.Close() }
+
+// Reader returns a sequential reader over the whole plaintext
+func (sf *storedFile) Reader() io.Reader { return io.NewSectionReader(sf, 0, sf.size) }
+
+// writeStored writes data to path, encrypting it when key is set
+func writeStored(path string, data []byte, key []byte, streamID string) error {
+	if key == nil {
+		return os.WriteFile(path, data, 0644)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	sw, err := newSegmentWriter(f, key, streamID)
+	if err == nil {
+		if _, err = sw.Write(data); err == nil {
+			err = sw.Close()
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -2,10 +2,28 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Postgres error codes the service maps to gRPC statuses
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// pgCode returns the Postgres error code of err, or "" if it has none
+func pgCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
 // UploadRecord represents a single upload record from the DB
 type UploadRecord struct {
 	FileID     string
@@ -13,10 +31,39 @@ type UploadRecord struct {
 	FileName   string
 	StoredPath string
 	Status     string
+	KeyID      string // master key wrapping WrappedKey
+	WrappedKey []byte // per-file data key; nil when stored in plaintext
+	SHA256     string // content blob referenced once completed; empty for legacy files
+	Manifest   bool   // content is assembled from content chunks instead of StoredPath
+	Size       int64
+	MimeType   string
+
+	// Versioning: VersionOf is the file_id of the file's first version and
+	// is empty for that version itself. RestoredFrom names the upload whose
+	// content a restored version or a copied file shares.
+	VersionOf    string
+	Version      int64
+	RestoredFrom string
+
+	// Folder holding the file; empty at the top level and for later versions
+	FolderID string
+
+	// User metadata and tags, kept on a file's first version
+	Metadata map[string]string
+	Tags     []string
+
+	// Client-side encryption metadata, stored opaquely for the download client
+	ClientEncScheme  string
+	ClientWrappedKey []byte
+	ClientKeyID      string
 }
 
+// UploadDB runs the service's queries. One created by NewUploadDB sees the
+// rows of every tenant; ForTenant returns one limited to a single tenant.
 type UploadDB struct {
-	pool *pgxpool.Pool
+	pool   dbConn
+	base   *pgxpool.Pool
+	tenant string
 }
 
 // NewUploadDB creates a new PostgreSQL connection pool
@@ -25,46 +72,98 @@ func NewUploadDB(connStr string) (*UploadDB, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &UploadDB{pool: pool}, nil
+	return &UploadDB{pool: pool, base: pool}, nil
 }
 
-// CreateUpload inserts a new upload entry
-func (db *UploadDB) CreateUpload(fileID, userID, fileName string, totalChunks int64) error {
+// nextVersionSQL computes the version number of a new upload row from its
+// version_of parameter
+const nextVersionSQL = `COALESCE((SELECT MAX(version) + 1 FROM uploads
+	WHERE file_id = NULLIF(%[1]s::text, '')::uuid OR version_of = NULLIF(%[1]s::text, '')::uuid), 1)`
+
+// CreateUpload inserts a new upload entry and sets rec.Version
+func (db *UploadDB) CreateUpload(rec *UploadRecord, totalChunks int64) error {
+	return db.pool.QueryRow(context.Background(),
+		`INSERT INTO uploads(file_id, user_id, file_name, total_chunks, status, enc_key_id, enc_wrapped_key,
+		                     client_enc_scheme, client_wrapped_key, client_key_id, version_of, version, restored_from,
+		                     folder_id, metadata, tags)
+		 VALUES($1, $2, $3, $4, 'in_progress', NULLIF($5, ''), $6, NULLIF($7, ''), $8, NULLIF($9, ''),
+		        NULLIF($10::text, '')::uuid, `+fmt.Sprintf(nextVersionSQL, "$10")+`, NULLIF($11::text, '')::uuid,
+		        NULLIF($12::text, '')::uuid, `+metadataValuesSQL("$13", "$14")+`)
+		 RETURNING version`,
+		rec.FileID, rec.UserID, rec.FileName, totalChunks, rec.KeyID, rec.WrappedKey,
+		rec.ClientEncScheme, rec.ClientWrappedKey, rec.ClientKeyID, rec.VersionOf, rec.RestoredFrom,
+		rec.FolderID, rec.Metadata, rec.Tags,
+	).Scan(&rec.Version)
+}
+
+// QuarantineUpload marks an upload as quarantined after a positive malware scan
+func (db *UploadDB) QuarantineUpload(fileID, storedPath string) error {
 	_, err := db.pool.Exec(context.Background(),
-		`INSERT INTO uploads(file_id, user_id, file_name, total_chunks, status)
-		 VALUES($1, $2, $3, $4, 'in_progress')`,
-		fileID, userID, fileName, totalChunks,
+		`UPDATE uploads SET status='quarantined', stored_path=$1 WHERE file_id=$2`,
+		storedPath, fileID,
 	)
 	return err
 }
 
-// CompleteUpload updates the upload record when merge is done
-func (db *UploadDB) CompleteUpload(fileID, storedPath string) error {
+// RecordScan stores the result of a malware scan
+func (db *UploadDB) RecordScan(fileID, result, signature, engine, version string) error {
 	_, err := db.pool.Exec(context.Background(),
-		`UPDATE uploads SET status='completed', stored_path=$1 WHERE file_id=$2`,
-		storedPath, fileID,
+		`UPDATE uploads SET scan_result=$1, scan_signature=NULLIF($2, ''), scan_engine=NULLIF($3, ''),
+		        scan_engine_version=NULLIF($4, ''), scanned_at=now()
+		 WHERE file_id=$5`,
+		result, signature, engine, version, fileID,
 	)
 	return err
 }
 
-// GetUploadByID retrieves a file upload record by its ID
+// FailUpload marks an upload as failed
+func (db *UploadDB) FailUpload(fileID string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE uploads SET status='failed' WHERE file_id=$1`,
+		fileID,
+	)
+	return err
+}
+
+// SetMimeType records the detected content type of an upload
+func (db *UploadDB) SetMimeType(fileID, mimeType string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE uploads SET mime_type=$1 WHERE file_id=$2`,
+		mimeType, fileID,
+	)
+	return err
+}
+
+// GetUploadByID retrieves a file upload record by its ID. Files in the
+// trash are not found.
 func (db *UploadDB) GetUploadByID(fileID string) (*UploadRecord, error) {
+	return db.getUpload(fileID, "AND deleted_at IS NULL")
+}
+
+// GetTrashedUpload retrieves an upload that is in the trash
+func (db *UploadDB) GetTrashedUpload(fileID string) (*UploadRecord, error) {
+	return db.getUpload(fileID, "AND deleted_at IS NOT NULL")
+}
+
+func (db *UploadDB) getUpload(fileID, filter string) (*UploadRecord, error) {
 	var rec UploadRecord
-	query := `SELECT file_id, user_id, file_name, stored_path, status FROM uploads WHERE file_id = $1`
+	query := `SELECT file_id, COALESCE(user_id::text, ''), file_name, COALESCE(stored_path, ''), status,
+	                 COALESCE(enc_key_id, ''), enc_wrapped_key,
+	                 COALESCE(client_enc_scheme, ''), client_wrapped_key, COALESCE(client_key_id, ''),
+	                 COALESCE(sha256, ''), manifest, COALESCE(size_bytes, 0), COALESCE(mime_type, ''),
+	                 COALESCE(version_of::text, ''), version, COALESCE(restored_from::text, ''),
+	                 COALESCE(folder_id::text, ''), metadata, tags
+	          FROM uploads WHERE file_id = $1 ` + filter
 	err := db.pool.QueryRow(context.Background(), query, fileID).Scan(
 		&rec.FileID, &rec.UserID, &rec.FileName, &rec.StoredPath, &rec.Status,
+		&rec.KeyID, &rec.WrappedKey,
+		&rec.ClientEncScheme, &rec.ClientWrappedKey, &rec.ClientKeyID,
+		&rec.SHA256, &rec.Manifest, &rec.Size, &rec.MimeType,
+		&rec.VersionOf, &rec.Version, &rec.RestoredFrom,
+		&rec.FolderID, &rec.Metadata, &rec.Tags,
 	)
 	if err != nil {
 		return nil, err
 	}
 	return &rec, nil
 }
-
-// DeleteUpload removes an upload record from the database
-func (db *UploadDB) DeleteUpload(fileID string) error {
-	_, err := db.pool.Exec(context.Background(),
-		`DELETE FROM uploads WHERE file_id=$1`,
-		fileID,
-	)
-	return err
-}
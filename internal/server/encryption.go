@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const dataKeySize = 32 // AES-256
+
+var dataKeyAAD = []byte("upload-backend data key")
+
+// KeyWrapper wraps and unwraps per-file data keys with a master key. The
+// local keyfile implementation can be swapped for a KMS client.
+type KeyWrapper interface {
+	// ActiveKeyID names the master key used for new data keys
+	ActiveKeyID() string
+	Wrap(keyID string, dataKey []byte) ([]byte, error)
+	Unwrap(keyID string, wrapped []byte) ([]byte, error)
+}
+
+// LocalKeyring holds master keys loaded from a JSON keyfile:
+//
+//	{"active": "2026-10", "keys": {"2026-10": "<base64 32 bytes>", "2025-01": "..."}}
+//
+// Old keys stay in the file until RotateDataKeys has rewrapped every data key.
+type LocalKeyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// LoadKeyring reads a master keyfile
+func LoadKeyring(path string) (*LocalKeyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Active string            `json:"active"`
+		Keys   map[string]string `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse keyfile: %w", err)
+	}
+
+	kr := &LocalKeyring{active: file.Active, keys: make(map[string][]byte, len(file.Keys))}
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != dataKeySize {
+			return nil, fmt.Errorf("master key %q must be 32 base64-encoded bytes", id)
+		}
+		kr.keys[id] = key
+	}
+	if _, ok := kr.keys[kr.active]; !ok {
+		return nil, fmt.Errorf("active master key %q not found in keyfile", kr.active)
+	}
+	return kr, nil
+}
+
+// ActiveKeyID implements KeyWrapper
+func (kr *LocalKeyring) ActiveKeyID() string { return kr.active }
+
+// Wrap implements KeyWrapper
+func (kr *LocalKeyring) Wrap(keyID string, dataKey []byte) ([]byte, error) {
+	master, ok := kr.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key %q", keyID)
+	}
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, encNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, dataKey, dataKeyAAD), nil
+}
+
+// Unwrap implements KeyWrapper
+func (kr *LocalKeyring) Unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	master, ok := kr.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown master key %q", keyID)
+	}
+	if len(wrapped) < encNonceSize {
+		return nil, fmt.Errorf("wrapped key too short")
+	}
+	aead, err := newAEAD(master)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, wrapped[:encNonceSize], wrapped[encNonceSize:], dataKeyAAD)
+}
+
+// newDataKey creates and wraps a fresh per-file data key
+func newDataKey(kw KeyWrapper) (keyID string, wrapped []byte, err error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", nil, err
+	}
+	keyID = kw.ActiveKeyID()
+	wrapped, err = kw.Wrap(keyID, dataKey)
+	return keyID, wrapped, err
+}
+
+// dataKey unwraps the data key of an upload. It returns nil for uploads
+// stored in plaintext.
+func (s *UploadService) dataKey(rec *UploadRecord) ([]byte, error) {
+	return s.unwrapKey(rec.KeyID, rec.WrappedKey)
+}
+
+// unwrapKey unwraps a stored data key; nil means the content is plaintext
+func (s *UploadService) unwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	if wrapped == nil {
+		return nil, nil
+	}
+	if s.keys == nil {
+		return nil, fmt.Errorf("content is encrypted with master key %q but no keyring is configured", keyID)
+	}
+	return s.keys.Unwrap(keyID, wrapped)
+}
+
+// readStoredFile returns the plaintext content of a completed upload
+func (s *UploadService) readStoredFile(rec *UploadRecord) ([]byte, error) {
+	sf, err := s.openUpload(rec)
+	if err != nil {
+		return nil, err
+	}
+	defer sf.Close()
+	data := make([]byte, sf.Size())
+	if _, err := sf.ReadAt(data, 0); err != nil && sf.Size() > 0 {
+		return nil, err
+	}
+	return data, nil
+}
+
+// fileStreamID identifies a merged file in segment AAD
+const fileStreamID = "file"
+
+// chunkStreamID identifies a temporary chunk in segment AAD
+func chunkStreamID(idx int64) string {
+	return fmt.Sprintf("chunk_%d", idx)
+}
+
+// WrappedKey is a data key wrapped by the named master key
+type WrappedKey struct {
+	KeyID   string
+	Wrapped []byte
+}
+
+// ListWrappedKeys returns file IDs whose data key is wrapped by a master key other than activeKeyID
+func (db *UploadDB) ListWrappedKeys(activeKeyID string) (map[string]WrappedKey, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT file_id, enc_key_id, enc_wrapped_key FROM uploads
+		 WHERE enc_wrapped_key IS NOT NULL AND enc_key_id <> $1`,
+		activeKeyID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make(map[string]WrappedKey)
+	for rows.Next() {
+		var fileID string
+		var wk WrappedKey
+		if err := rows.Scan(&fileID, &wk.KeyID, &wk.Wrapped); err != nil {
+			return nil, err
+		}
+		keys[fileID] = wk
+	}
+	return keys, rows.Err()
+}
+
+// UpdateWrappedKey replaces the wrapped data key of a file if it is still
+// wrapped by oldKeyID
+func (db *UploadDB) UpdateWrappedKey(fileID, oldKeyID string, wk WrappedKey) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE uploads SET enc_key_id=$1, enc_wrapped_key=$2 WHERE file_id=$3 AND enc_key_id=$4`,
+		wk.KeyID, wk.Wrapped, fileID, oldKeyID,
+	)
+	return err
+}
+
+// listContentKeys returns a lister of the stale data keys of a
+// content-addressed table (blobs or content_chunks), keyed by hash
+func (db *UploadDB) listContentKeys(table string) func(string) (map[string]WrappedKey, error) {
+	return func(activeKeyID string) (map[string]WrappedKey, error) {
+		rows, err := db.pool.Query(context.Background(),
+			`SELECT sha256, enc_key_id, enc_wrapped_key FROM `+table+`
+			 WHERE tenant_id = `+currentTenantSQL+` AND enc_wrapped_key IS NOT NULL AND enc_key_id <> $1`,
+			activeKeyID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		keys := make(map[string]WrappedKey)
+		for rows.Next() {
+			var sum string
+			var wk WrappedKey
+			if err := rows.Scan(&sum, &wk.KeyID, &wk.Wrapped); err != nil {
+				return nil, err
+			}
+			keys[sum] = wk
+		}
+		return keys, rows.Err()
+	}
+}
+
+// updateContentKey returns an updater replacing the wrapped data key of a
+// row in a content-addressed table if it is still wrapped by oldKeyID
+func (db *UploadDB) updateContentKey(table string) func(string, string, WrappedKey) error {
+	return func(sum, oldKeyID string, wk WrappedKey) error {
+		_, err := db.pool.Exec(context.Background(),
+			`UPDATE `+table+` SET enc_key_id=$1, enc_wrapped_key=$2 WHERE tenant_id = `+currentTenantSQL+` AND sha256=$3 AND enc_key_id=$4`,
+			wk.KeyID, wk.Wrapped, sum, oldKeyID,
+		)
+		return err
+	}
+}
+
+// RotateDataKeys rewraps every data key, of uploads and of the blobs and
+// content chunks they share, that is not wrapped by the active master key.
+// File contents are not re-encrypted. Tenants are rotated one at a time,
+// since content hashes are only unique within a tenant.
+func RotateDataKeys(db *UploadDB, kw KeyWrapper) (int, error) {
+	tenants, err := db.ListTenants()
+	if err != nil {
+		return 0, err
+	}
+	rotated := 0
+	for _, t := range tenants {
+		tdb := db.ForTenant(t.ID)
+		n, err := rotateKeys(kw, tdb.ListWrappedKeys, tdb.UpdateWrappedKey)
+		rotated += n
+		if err != nil {
+			return rotated, fmt.Errorf("tenant %s: %w", t.ID, err)
+		}
+		for _, table := range []string{"blobs", "content_chunks"} {
+			n, err := rotateKeys(kw, tdb.listContentKeys(table), tdb.updateContentKey(table))
+			rotated += n
+			if err != nil {
+				return rotated, fmt.Errorf("tenant %s: %w", t.ID, err)
+			}
+		}
+	}
+	return rotated, nil
+}
+
+// rotateKeys rewraps the stale keys returned by list and stores them with update
+func rotateKeys(kw KeyWrapper, list func(string) (map[string]WrappedKey, error), update func(string, string, WrappedKey) error) (int, error) {
+	active := kw.ActiveKeyID()
+	stale, err := list(active)
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for id, wk := range stale {
+		dataKey, err := kw.Unwrap(wk.KeyID, wk.Wrapped)
+		if err != nil {
+			return rotated, fmt.Errorf("unwrap key for %s: %w", id, err)
+		}
+		wrapped, err := kw.Wrap(active, dataKey)
+		if err != nil {
+			return rotated, fmt.Errorf("wrap key for %s: %w", id, err)
+		}
+		if err := update(id, wk.KeyID, WrappedKey{KeyID: active, Wrapped: wrapped}); err != nil {
+			return rotated, fmt.Errorf("update key for %s: %w", id, err)
+		}
+		rotated++
+	}
+	return rotated, nil
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"upload-backend/pb"
+)
+
+// Upload events are published on a Redis channel per upload, so a watcher
+// sees them whichever server handles the upload. Each server subscribes
+// once per watched upload and fans the events out to its local watchers.
+
+const (
+	eventChunkReceived = "chunk_received"
+	eventBytesReceived = "bytes_received"
+	eventMergeStarted  = "merge_started"
+	eventCompleted     = "completed"
+	eventFailed        = "failed"
+
+	// Events a watcher may fall behind by before it is disconnected
+	watcherBuffer = 64
+)
+
+func eventChannel(fileID string) string {
+	return "upload:" + fileID + ":events"
+}
+
+// publishEvent sends an upload event to its watchers on every server. Events
+// are best effort: a failure is logged and never fails the upload.
+func (s *UploadService) publishEvent(ev *pb.UploadEvent) {
+	ev.OccurredAt = time.Now().UnixMilli()
+	data, err := proto.Marshal(ev)
+	if err == nil {
+		err = s.rdb.Publish(context.Background(), eventChannel(ev.FileId), data).Err()
+	}
+	if err != nil {
+		log.Printf("Upload event error: file_id=%s, type=%s, error=%v", ev.FileId, ev.Type, err)
+	}
+}
+
+// chunkReceived publishes the progress of an upload after a chunk was stored
+func (s *UploadService) chunkReceived(fileID string, idx, chunks, totalChunks, received, totalBytes int64) {
+	s.publishEvent(&pb.UploadEvent{
+		FileId: fileID, Type: eventChunkReceived, ChunkIndex: idx, ChunksReceived: chunks, TotalChunks: totalChunks,
+		BytesReceived: received, TotalBytes: totalBytes, Status: "in_progress",
+	})
+	s.publishEvent(&pb.UploadEvent{
+		FileId: fileID, Type: eventBytesReceived, ChunksReceived: chunks, TotalChunks: totalChunks,
+		BytesReceived: received, TotalBytes: totalBytes, Status: "in_progress",
+	})
+}
+
+// mergeStarted publishes that all chunks arrived and the file is being assembled
+func (s *UploadService) mergeStarted(fileID string, totalChunks int64) {
+	s.publishEvent(&pb.UploadEvent{
+		FileId: fileID, Type: eventMergeStarted, ChunksReceived: totalChunks, TotalChunks: totalChunks, Status: "in_progress",
+	})
+}
+
+// failUpload marks an upload as failed and tells its watchers and webhooks why
+func (s *UploadService) failUpload(fileID, reason string) {
+	if err := s.db.FailUpload(fileID); err != nil {
+		log.Printf("Upload fail error: file_id=%s, error=%v", fileID, err)
+	}
+	s.publishEvent(&pb.UploadEvent{FileId: fileID, Type: eventFailed, Status: "failed", Error: reason})
+	s.notifyWebhooks(webhookUploadFailed, webhookData{FileID: fileID, Error: reason})
+}
+
+// uploadQuarantined tells the watchers and webhooks of an upload that it was quarantined
+func (s *UploadService) uploadQuarantined(fileID, signature string) {
+	s.publishEvent(&pb.UploadEvent{FileId: fileID, Type: eventFailed, Status: "quarantined", Error: "file quarantined: " + signature})
+	s.notifyWebhooks(webhookUploadFailed, webhookData{FileID: fileID, Error: "file quarantined: " + signature})
+}
+
+// eventHub shares one Redis subscription between the watchers of an upload
+// on this server
+type eventHub struct {
+	rdb *redis.Client
+
+	mu       sync.Mutex
+	pubsub   *redis.PubSub
+	watchers map[string]map[chan *pb.UploadEvent]struct{}
+	pending  map[string]chan struct{} // closed once Redis confirms the subscription
+}
+
+func newEventHub(rdb *redis.Client) *eventHub {
+	return &eventHub{
+		rdb:      rdb,
+		watchers: map[string]map[chan *pb.UploadEvent]struct{}{},
+		pending:  map[string]chan struct{}{},
+	}
+}
+
+// watch registers a watcher of an upload's events, returning once Redis
+// delivers every event published from then on. The channel is closed if
+// the watcher falls behind; the returned function unregisters it.
+func (h *eventHub) watch(ctx context.Context, fileID string) (<-chan *pb.UploadEvent, func(), error) {
+	h.mu.Lock()
+	if h.pubsub == nil {
+		h.pubsub = h.rdb.Subscribe(context.Background())
+		go h.run(h.pubsub.ChannelWithSubscriptions())
+	}
+	set := h.watchers[fileID]
+	if set == nil {
+		if err := h.pubsub.Subscribe(ctx, eventChannel(fileID)); err != nil {
+			h.mu.Unlock()
+			return nil, nil, err
+		}
+		set = map[chan *pb.UploadEvent]struct{}{}
+		h.watchers[fileID] = set
+		h.pending[fileID] = make(chan struct{})
+	}
+	ch := make(chan *pb.UploadEvent, watcherBuffer)
+	set[ch] = struct{}{}
+	ready := h.pending[fileID]
+	h.mu.Unlock()
+
+	stop := func() { h.unwatch(fileID, ch) }
+	if ready != nil {
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			stop()
+			return nil, nil, ctx.Err()
+		}
+	}
+	return ch, stop, nil
+}
+
+func (h *eventHub) unwatch(fileID string, ch chan *pb.UploadEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.watchers[fileID][ch]; !ok {
+		return
+	}
+	delete(h.watchers[fileID], ch)
+	close(ch)
+	h.release(fileID)
+}
+
+// release unsubscribes from an upload nobody on this server watches any
+// more. The caller holds h.mu.
+func (h *eventHub) release(fileID string) {
+	if set, ok := h.watchers[fileID]; !ok || len(set) > 0 {
+		return
+	}
+	delete(h.watchers, fileID)
+	delete(h.pending, fileID)
+	if err := h.pubsub.Unsubscribe(context.Background(), eventChannel(fileID)); err != nil {
+		log.Printf("Upload event unsubscribe error: file_id=%s, error=%v", fileID, err)
+	}
+}
+
+// run delivers the events received from Redis to the local watchers
+func (h *eventHub) run(msgs <-chan interface{}) {
+	for msg := range msgs {
+		switch m := msg.(type) {
+		case *redis.Subscription:
+			if m.Kind != "subscribe" {
+				continue
+			}
+			fileID := strings.TrimSuffix(strings.TrimPrefix(m.Channel, "upload:"), ":events")
+			h.mu.Lock()
+			if ready := h.pending[fileID]; ready != nil {
+				close(ready)
+				delete(h.pending, fileID)
+			}
+			h.mu.Unlock()
+		case *redis.Message:
+			ev := &pb.UploadEvent{}
+			if err := proto.Unmarshal([]byte(m.Payload), ev); err != nil {
+				log.Printf("Upload event decode error: channel=%s, error=%v", m.Channel, err)
+				continue
+			}
+			h.mu.Lock()
+			for ch := range h.watchers[ev.FileId] {
+				select {
+				case ch <- ev:
+				default:
+					// A stalled watcher must not hold up the others
+					delete(h.watchers[ev.FileId], ch)
+					close(ch)
+				}
+			}
+			h.release(ev.FileId)
+			h.mu.Unlock()
+		}
+	}
+}
+
+// uploadSnapshot describes the current state of an upload as an event
+func (s *UploadService) uploadSnapshot(ctx context.Context, rec *UploadRecord) (*pb.UploadEvent, error) {
+	ev := &pb.UploadEvent{FileId: rec.FileID, Status: rec.Status, OccurredAt: time.Now().UnixMilli()}
+	switch rec.Status {
+	case "completed":
+		ev.Type = eventCompleted
+		if content, err := s.openUpload(rec); err == nil {
+			ev.BytesReceived = content.Size()
+			ev.TotalBytes = ev.BytesReceived
+			content.Close()
+		}
+		return ev, nil
+	case "failed":
+		ev.Type = eventFailed
+		if imp, err := s.db.GetImport(rec.FileID); err == nil {
+			ev.Error = imp.Error
+		}
+		return ev, nil
+	case "quarantined":
+		ev.Type = eventFailed
+		ev.Error = "file quarantined"
+		return ev, nil
+	}
+
+	ev.Type = eventBytesReceived
+	chunks, received, err := receivedBytes(ctx, s.rdb, rec.FileID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "redis error: %v", err)
+	}
+	ev.ChunksReceived, ev.BytesReceived = chunks, received
+	// Imports know their progress, and often the size, better
+	if imp, err := s.db.GetImport(rec.FileID); err == nil {
+		ev.BytesReceived, ev.TotalBytes = imp.BytesReceived, imp.TotalBytes
+	}
+	return ev, nil
+}
+
+func terminalEvent(ev *pb.UploadEvent) bool {
+	return ev.Type == eventCompleted || ev.Type == eventFailed
+}
+
+// WatchUpload streams the events of an upload the caller can read until it
+// completes or fails
+func (s *UploadService) WatchUpload(req *pb.WatchUploadRequest, stream pb.FileUploadService_WatchUploadServer) error {
+	ctx := stream.Context()
+	s = s.scoped(ctx)
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "upload not found: %v", err)
+	}
+	if _, err := s.authorize(ctx, rec, permRead); err != nil {
+		return err
+	}
+
+	// Watch before reading the state, so nothing happens in between unseen
+	events, stop, err := s.events.watch(ctx, rec.FileID)
+	if err != nil {
+		log.Printf("WatchUpload subscribe error: file_id=%s, error=%v", rec.FileID, err)
+		return status.Errorf(codes.Unavailable, "failed to subscribe to events: %v", err)
+	}
+	defer stop()
+	if rec, err = s.db.GetUploadByID(rec.FileID); err != nil {
+		return status.Errorf(codes.NotFound, "upload not found: %v", err)
+	}
+	ev, err := s.uploadSnapshot(ctx, rec)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(ev); err != nil || terminalEvent(ev) {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watcher fell behind; watch again to resume")
+			}
+			if err := stream.Send(ev); err != nil || terminalEvent(ev) {
+				return err
+			}
+		}
+	}
+}
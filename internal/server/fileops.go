@@ -0,0 +1,216 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// RenameFile sets the name of every version of a file
+func (db *UploadDB) RenameFile(rootID, name string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE uploads SET file_name = $2 WHERE file_id = $1 OR version_of = $1`,
+		rootID, name,
+	)
+	return err
+}
+
+// MoveFile places a file in a folder, or at the top level
+func (db *UploadDB) MoveFile(rootID, folderID string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE uploads SET folder_id = NULLIF($2::text, '')::uuid WHERE file_id = $1`,
+		rootID, folderID,
+	)
+	return err
+}
+
+// GetFileEntry describes a file by its current version
+func (db *UploadDB) GetFileEntry(rootID string) (*FileEntry, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT `+fileEntryColumns+` FROM `+fileEntryFrom+` WHERE r.file_id = $1`,
+		rootID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanFileEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return entries[0], nil
+}
+
+// checkNewName sanitizes a new name for rec and checks it against the
+// content policy of userID, including the stored content when it is readable
+func (s *UploadService) checkNewName(userID string, rec *UploadRecord, name string) (string, error) {
+	safe := sanitizeFilename(filepath.Base(name))
+	rules := s.policy.rulesFor(userID)
+	if err := rules.checkName(safe); err != nil {
+		return "", err
+	}
+	// Client-side encrypted content cannot be sniffed
+	if rec.Status != "completed" || rec.ClientEncScheme != "" {
+		return safe, nil
+	}
+	content, err := s.openUpload(rec)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to open file: %v", err)
+	}
+	defer content.Close()
+	head := make([]byte, blobHeadSize)
+	n, err := content.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", status.Errorf(codes.Internal, "failed to read file: %v", err)
+	}
+	if _, err := rules.checkContent(safe, head[:n]); err != nil {
+		return "", err
+	}
+	return safe, nil
+}
+
+// fileEntryResponse loads the listing entry of a file after a change
+func (s *UploadService) fileEntryResponse(rootID string) (*pb.FileEntry, error) {
+	e, err := s.db.GetFileEntry(rootID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	return fileEntryToPB(e), nil
+}
+
+// RenameFile changes the name of a file and all its versions without touching its content
+func (s *UploadService) RenameFile(ctx context.Context, req *pb.RenameFileRequest) (*pb.FileEntry, error) {
+	s = s.scoped(ctx)
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if _, err := s.authorize(ctx, rec, permWrite); err != nil {
+		return nil, err
+	}
+	if req.FileName == "" {
+		return nil, status.Error(codes.InvalidArgument, "file_name is required")
+	}
+	root, err := s.rootOf(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	current, err := s.currentVersion(root)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "version lookup error: %v", err)
+	}
+	name, err := s.checkNewName(root.UserID, current, req.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.RenameFile(root.FileID, name); err != nil {
+		return nil, status.Errorf(codes.Internal, "db update error: %v", err)
+	}
+
+	log.Printf("RenameFile success: file_id=%s, old_name=%s, new_name=%s", root.FileID, root.FileName, name)
+	return s.fileEntryResponse(root.FileID)
+}
+
+// MoveFile moves one of the caller's files to another folder of theirs
+func (s *UploadService) MoveFile(ctx context.Context, req *pb.MoveFileRequest) (*pb.FileEntry, error) {
+	s = s.scoped(ctx)
+	rec, userID, err := s.requireOwner(ctx, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+	if rec.VersionOf != "" {
+		return nil, status.Error(codes.InvalidArgument, "move the file by its first file_id, not a version")
+	}
+	folder, err := s.resolveFolder(userID, req.FolderId, req.FolderPath)
+	if err != nil {
+		return nil, err
+	}
+	folderID := ""
+	if folder != nil {
+		folderID = folder.ID
+	}
+
+	if err := s.db.MoveFile(rec.FileID, folderID); err != nil {
+		return nil, status.Errorf(codes.Internal, "db update error: %v", err)
+	}
+
+	log.Printf("MoveFile success: file_id=%s, folder_id=%s", rec.FileID, folderID)
+	return s.fileEntryResponse(rec.FileID)
+}
+
+// CopyFile creates a new file owned by the caller with the content of the
+// source's current version. The content is shared by reference, so no bytes
+// are copied unless the source predates content-addressed storage.
+func (s *UploadService) CopyFile(ctx context.Context, req *pb.CopyFileRequest) (*pb.FileEntry, error) {
+	s = s.scoped(ctx)
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	c, err := s.authorize(ctx, rec, permRead)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, status.Error(codes.PermissionDenied, "copying files requires a signed-in user")
+	}
+	src, err := s.resolveVersion(rec, 0)
+	if err != nil {
+		return nil, err
+	}
+	if src.Status != "completed" {
+		return nil, status.Error(codes.FailedPrecondition, "upload is not completed")
+	}
+	if err := s.checkQuota(src.Size); err != nil {
+		return nil, err
+	}
+
+	name := src.FileName
+	if req.FileName != "" {
+		name = req.FileName
+	}
+	if name, err = s.checkNewName(c.UserID, src, name); err != nil {
+		return nil, err
+	}
+	folder, err := s.resolveFolder(c.UserID, req.FolderId, req.FolderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cp := &UploadRecord{
+		FileID:           uuid.NewString(),
+		UserID:           c.UserID,
+		FileName:         name,
+		RestoredFrom:     contentID(src),
+		ClientEncScheme:  src.ClientEncScheme,
+		ClientWrappedKey: src.ClientWrappedKey,
+		ClientKeyID:      src.ClientKeyID,
+	}
+	if folder != nil {
+		cp.FolderID = folder.ID
+	}
+	// Metadata and tags are kept on the source's first version
+	if root, err := s.rootOf(src); err == nil {
+		cp.Metadata = root.Metadata
+		cp.Tags = root.Tags
+	}
+	if err := s.cloneContent(cp, src); err != nil {
+		log.Printf("CopyFile error: file_id=%s, error=%v", src.FileID, err)
+		return nil, status.Errorf(codes.Internal, "failed to copy file: %v", err)
+	}
+
+	s.indexText(cp.FileID)
+
+	log.Printf("CopyFile success: file_id=%s, copy_id=%s, user_id=%s", src.FileID, cp.FileID, c.UserID)
+	return s.fileEntryResponse(cp.FileID)
+}
@@ -0,0 +1,618 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// Folders form a virtual tree per user in Postgres. They only group files
+// for listing and path lookup; where content is stored does not change.
+
+const (
+	maxFolderDepth        = 64
+	defaultFolderPageSize = 100
+	maxFolderPageSize     = 1000
+)
+
+// Folder is a node of a user's folder tree
+type Folder struct {
+	ID        string
+	OwnerID   string
+	ParentID  string // empty at the top level
+	Name      string
+	Path      string
+	CreatedAt time.Time
+}
+
+// FileEntry is a file as it appears in listings, described by its current version
+type FileEntry struct {
+	FileID    string
+	FileName  string
+	FolderID  string
+	Size      int64
+	Status    string
+	Version   int64
+	CreatedAt time.Time
+	Metadata  map[string]string
+	Tags      []string
+}
+
+// fileEntryColumns and fileEntryFrom select FileEntry rows from the first
+// versions of files, aliased r, joined with their current version
+const (
+	fileEntryColumns = `r.file_id::text, r.file_name, COALESCE(r.folder_id::text, ''),
+	       COALESCE(cur.size_bytes, r.size_bytes, 0), COALESCE(cur.status, r.status),
+	       COALESCE(cur.version, r.version), r.created_at, r.metadata, r.tags`
+	fileEntryFrom = `uploads r LEFT JOIN LATERAL (
+	         SELECT file_id, size_bytes, status, version FROM uploads v
+	         WHERE (v.file_id = r.file_id OR v.version_of = r.file_id) AND v.status = 'completed' AND v.deleted_at IS NULL
+	         ORDER BY v.version DESC LIMIT 1) cur ON true`
+)
+
+func scanFileEntries(rows pgx.Rows) ([]*FileEntry, error) {
+	defer rows.Close()
+	var entries []*FileEntry
+	for rows.Next() {
+		var e FileEntry
+		if err := rows.Scan(&e.FileID, &e.FileName, &e.FolderID, &e.Size, &e.Status, &e.Version, &e.CreatedAt, &e.Metadata, &e.Tags); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// folderPathSQL computes the path of the folder aliased f
+const folderPathSQL = `(WITH RECURSIVE up AS (
+	  SELECT id, parent_id, name, 0 AS depth FROM folders WHERE id = f.id
+	  UNION ALL
+	  SELECT p.id, p.parent_id, p.name, up.depth + 1 FROM folders p JOIN up ON p.id = up.parent_id
+	) SELECT '/' || string_agg(name, '/' ORDER BY depth DESC) FROM up)`
+
+const folderColumns = `f.id::text, f.owner_id, COALESCE(f.parent_id::text, ''), f.name, ` + folderPathSQL + `, f.created_at`
+
+func scanFolder(row pgx.Row) (*Folder, error) {
+	var f Folder
+	if err := row.Scan(&f.ID, &f.OwnerID, &f.ParentID, &f.Name, &f.Path, &f.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// CreateFolder inserts a folder
+func (db *UploadDB) CreateFolder(f *Folder) error {
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO folders(id, owner_id, parent_id, name) VALUES($1, $2, NULLIF($3::text, '')::uuid, $4)`,
+		f.ID, f.OwnerID, f.ParentID, f.Name,
+	)
+	return err
+}
+
+// GetFolder loads a folder of ownerID
+func (db *UploadDB) GetFolder(ownerID, folderID string) (*Folder, error) {
+	return scanFolder(db.pool.QueryRow(context.Background(),
+		`SELECT `+folderColumns+` FROM folders f WHERE f.id = $1 AND f.owner_id = $2`,
+		folderID, ownerID,
+	))
+}
+
+// FolderChild looks up a folder of ownerID by name inside parentID, or at the top level
+func (db *UploadDB) FolderChild(ownerID, parentID, name string) (*Folder, error) {
+	return scanFolder(db.pool.QueryRow(context.Background(),
+		`SELECT `+folderColumns+` FROM folders f
+		 WHERE f.owner_id = $1 AND f.parent_id IS NOT DISTINCT FROM NULLIF($2::text, '')::uuid AND f.name = $3`,
+		ownerID, parentID, name,
+	))
+}
+
+// ListSubfolders returns the folders directly inside parentID by name
+func (db *UploadDB) ListSubfolders(ownerID, parentID string) ([]*Folder, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT `+folderColumns+` FROM folders f
+		 WHERE f.owner_id = $1 AND f.parent_id IS NOT DISTINCT FROM NULLIF($2::text, '')::uuid
+		 ORDER BY f.name`,
+		ownerID, parentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var folders []*Folder
+	for rows.Next() {
+		f, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, f)
+	}
+	return folders, rows.Err()
+}
+
+// ListFolderFiles returns the files of ownerID directly inside folderID that
+// match filter, by name
+func (db *UploadDB) ListFolderFiles(ownerID, folderID string, filter FileFilter, offset, limit int) ([]*FileEntry, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT `+fileEntryColumns+` FROM `+fileEntryFrom+`
+		 WHERE r.version_of IS NULL AND r.deleted_at IS NULL AND r.user_id::text = $1
+		   AND r.folder_id IS NOT DISTINCT FROM NULLIF($2::text, '')::uuid
+		   AND `+filter.sql("$5", "$6")+`
+		 ORDER BY r.file_name, r.created_at DESC
+		 OFFSET $3 LIMIT $4`,
+		ownerID, folderID, offset, limit, filter.metadata(), filter.tags(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanFileEntries(rows)
+}
+
+// FolderFileByName returns the newest file of ownerID called name inside folderID
+func (db *UploadDB) FolderFileByName(ownerID, folderID, name string) (*FileEntry, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT `+fileEntryColumns+` FROM `+fileEntryFrom+`
+		 WHERE r.version_of IS NULL AND r.deleted_at IS NULL AND r.user_id::text = $1
+		   AND r.folder_id IS NOT DISTINCT FROM NULLIF($2::text, '')::uuid AND r.file_name = $3
+		 ORDER BY r.created_at DESC LIMIT 1`,
+		ownerID, folderID, name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := scanFileEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return entries[0], nil
+}
+
+// RenameFolder changes a folder's name
+func (db *UploadDB) RenameFolder(ownerID, folderID, name string) error {
+	tag, err := db.pool.Exec(context.Background(),
+		`UPDATE folders SET name = $3, updated_at = now() WHERE id = $1 AND owner_id = $2`,
+		folderID, ownerID, name,
+	)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = pgx.ErrNoRows
+	}
+	return err
+}
+
+// errFolderCycle is returned when a folder would be moved into its own subtree
+var errFolderCycle = errors.New("cannot move a folder into itself")
+
+// MoveFolder changes a folder's parent, refusing moves into its own subtree
+func (db *UploadDB) MoveFolder(ownerID, folderID, parentID string) error {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize moves per owner so two concurrent moves cannot form a cycle
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('folders:' || $1))`, ownerID); err != nil {
+		return err
+	}
+	if parentID != "" {
+		var cycle bool
+		err = tx.QueryRow(ctx,
+			`WITH RECURSIVE sub AS (
+			   SELECT id FROM folders WHERE id = $1
+			   UNION ALL
+			   SELECT f.id FROM folders f JOIN sub ON f.parent_id = sub.id
+			 ) SELECT EXISTS (SELECT 1 FROM sub WHERE id = $2)`,
+			folderID, parentID,
+		).Scan(&cycle)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return errFolderCycle
+		}
+	}
+	tag, err := tx.Exec(ctx,
+		`UPDATE folders SET parent_id = NULLIF($3::text, '')::uuid, updated_at = now() WHERE id = $1 AND owner_id = $2`,
+		folderID, ownerID, parentID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return tx.Commit(ctx)
+}
+
+// FolderIsEmpty reports whether a folder holds no subfolders and no files outside the trash
+func (db *UploadDB) FolderIsEmpty(folderID string) (bool, error) {
+	var busy bool
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT EXISTS (SELECT 1 FROM folders WHERE parent_id = $1)
+		     OR EXISTS (SELECT 1 FROM uploads WHERE folder_id = $1 AND deleted_at IS NULL)`,
+		folderID,
+	).Scan(&busy)
+	return !busy, err
+}
+
+// SubtreeFileIDs returns the files outside the trash anywhere below folderID
+func (db *UploadDB) SubtreeFileIDs(folderID string) ([]string, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`WITH RECURSIVE sub AS (
+		   SELECT id FROM folders WHERE id = $1
+		   UNION ALL
+		   SELECT f.id FROM folders f JOIN sub ON f.parent_id = sub.id
+		 ) SELECT u.file_id::text FROM uploads u JOIN sub ON u.folder_id = sub.id WHERE u.deleted_at IS NULL`,
+		folderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeleteFolder removes a folder and its subfolders. Files still pointing into
+// the subtree, i.e. trashed ones, drop back to the top level.
+func (db *UploadDB) DeleteFolder(ownerID, folderID string) error {
+	tag, err := db.pool.Exec(context.Background(),
+		`DELETE FROM folders WHERE id = $1 AND owner_id = $2`,
+		folderID, ownerID,
+	)
+	if err == nil && tag.RowsAffected() == 0 {
+		err = pgx.ErrNoRows
+	}
+	return err
+}
+
+// splitPath splits a slash-separated path into names, rejecting names that
+// sanitizeFilename would change
+func splitPath(p string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(p, "/") {
+		if name == "" {
+			continue
+		}
+		if name != sanitizeFilename(name) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid path segment %q", name)
+		}
+		names = append(names, name)
+	}
+	if len(names) > maxFolderDepth {
+		return nil, status.Errorf(codes.InvalidArgument, "path deeper than %d levels", maxFolderDepth)
+	}
+	return names, nil
+}
+
+// folderName validates a folder name
+func folderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 255 || name != sanitizeFilename(name) {
+		return "", status.Errorf(codes.InvalidArgument, "invalid folder name %q", name)
+	}
+	return name, nil
+}
+
+// resolveFolder finds a folder of userID by ID or by path. It returns nil
+// for the top level.
+func (s *UploadService) resolveFolder(userID, folderID, path string) (*Folder, error) {
+	if folderID != "" && path != "" {
+		return nil, status.Error(codes.InvalidArgument, "give either a folder ID or a path, not both")
+	}
+	if folderID != "" {
+		if _, err := uuid.Parse(folderID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid folder_id")
+		}
+		f, err := s.db.GetFolder(userID, folderID)
+		if err != nil {
+			return nil, folderError(err)
+		}
+		return f, nil
+	}
+	names, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+	var f *Folder
+	for _, name := range names {
+		parentID := ""
+		if f != nil {
+			parentID = f.ID
+		}
+		if f, err = s.db.FolderChild(userID, parentID, name); err != nil {
+			return nil, folderError(err)
+		}
+	}
+	return f, nil
+}
+
+// folderError maps database errors of folder operations to statuses
+func folderError(err error) error {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return status.Error(codes.NotFound, "folder not found")
+	case errors.Is(err, errFolderCycle):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case pgCode(err) == pgUniqueViolation:
+		return status.Error(codes.AlreadyExists, "a folder with this name already exists here")
+	}
+	return status.Errorf(codes.Internal, "db error: %v", err)
+}
+
+func folderToPB(f *Folder) *pb.Folder {
+	return &pb.Folder{
+		FolderId:  f.ID,
+		ParentId:  f.ParentID,
+		Name:      f.Name,
+		Path:      f.Path,
+		CreatedAt: f.CreatedAt.Unix(),
+	}
+}
+
+func fileEntryToPB(e *FileEntry) *pb.FileEntry {
+	return &pb.FileEntry{
+		FileId:    e.FileID,
+		FileName:  e.FileName,
+		FolderId:  e.FolderID,
+		Size:      e.Size,
+		Status:    e.Status,
+		Version:   e.Version,
+		CreatedAt: e.CreatedAt.Unix(),
+		Metadata:  e.Metadata,
+		Tags:      e.Tags,
+	}
+}
+
+// CreateFolder creates a folder in the caller's tree
+func (s *UploadService) CreateFolder(ctx context.Context, req *pb.CreateFolderRequest) (*pb.Folder, error) {
+	s = s.scoped(ctx)
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err := folderName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if req.ParentId != "" {
+		if _, err := s.resolveFolder(userID, req.ParentId, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	f := &Folder{ID: uuid.NewString(), OwnerID: userID, ParentID: req.ParentId, Name: name}
+	if err := s.db.CreateFolder(f); err != nil {
+		return nil, folderError(err)
+	}
+	if f, err = s.db.GetFolder(userID, f.ID); err != nil {
+		return nil, folderError(err)
+	}
+
+	log.Printf("CreateFolder success: user_id=%s, folder_id=%s, path=%s", userID, f.ID, f.Path)
+	return folderToPB(f), nil
+}
+
+// RenameFolder renames one of the caller's folders
+func (s *UploadService) RenameFolder(ctx context.Context, req *pb.RenameFolderRequest) (*pb.Folder, error) {
+	s = s.scoped(ctx)
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name, err := folderName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.resolveFolder(userID, req.FolderId, ""); err != nil {
+		return nil, err
+	}
+	if err := s.db.RenameFolder(userID, req.FolderId, name); err != nil {
+		return nil, folderError(err)
+	}
+	f, err := s.db.GetFolder(userID, req.FolderId)
+	if err != nil {
+		return nil, folderError(err)
+	}
+
+	log.Printf("RenameFolder success: user_id=%s, folder_id=%s, path=%s", userID, f.ID, f.Path)
+	return folderToPB(f), nil
+}
+
+// MoveFolder moves one of the caller's folders under another, or to the top level
+func (s *UploadService) MoveFolder(ctx context.Context, req *pb.MoveFolderRequest) (*pb.Folder, error) {
+	s = s.scoped(ctx)
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.resolveFolder(userID, req.FolderId, ""); err != nil {
+		return nil, err
+	}
+	if req.ParentId != "" {
+		if _, err := s.resolveFolder(userID, req.ParentId, ""); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.db.MoveFolder(userID, req.FolderId, req.ParentId); err != nil {
+		return nil, folderError(err)
+	}
+	f, err := s.db.GetFolder(userID, req.FolderId)
+	if err != nil {
+		return nil, folderError(err)
+	}
+
+	log.Printf("MoveFolder success: user_id=%s, folder_id=%s, path=%s", userID, f.ID, f.Path)
+	return folderToPB(f), nil
+}
+
+// DeleteFolder deletes one of the caller's folders. Recursive deletes move
+// every file below it to the trash first.
+func (s *UploadService) DeleteFolder(ctx context.Context, req *pb.DeleteFolderRequest) (*pb.DeleteResponse, error) {
+	s = s.scoped(ctx)
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.resolveFolder(userID, req.FolderId, ""); err != nil {
+		return nil, err
+	}
+
+	if !req.Recursive {
+		empty, err := s.db.FolderIsEmpty(req.FolderId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+		}
+		if !empty {
+			return nil, status.Error(codes.FailedPrecondition, "folder is not empty")
+		}
+	} else {
+		ids, err := s.db.SubtreeFileIDs(req.FolderId)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+		}
+		// Check every file first so a held file leaves the whole tree untouched
+		for _, id := range ids {
+			if err := s.checkRetention(ctx, userID, "delete", id); err != nil {
+				return nil, err
+			}
+		}
+		for _, id := range ids {
+			if _, err := s.db.TrashUpload(id, userID); err != nil {
+				return nil, status.Errorf(codes.Internal, "db update error: %v", err)
+			}
+			s.auditAs(ctx, userID, "delete", id, "ok", "folder "+req.FolderId+" deleted")
+			s.notifyWebhooks(webhookFileDeleted, webhookData{FileID: id, DeletedBy: userID})
+		}
+	}
+
+	if err := s.db.DeleteFolder(userID, req.FolderId); err != nil {
+		return nil, folderError(err)
+	}
+
+	log.Printf("DeleteFolder success: user_id=%s, folder_id=%s, recursive=%t", userID, req.FolderId, req.Recursive)
+	return &pb.DeleteResponse{Success: true, Message: "Folder deleted"}, nil
+}
+
+// ListFolder lists the subfolders and files of a folder of the caller.
+// Subfolders are returned on the first page only.
+func (s *UploadService) ListFolder(ctx context.Context, req *pb.ListFolderRequest) (*pb.ListFolderResponse, error) {
+	s = s.scoped(ctx)
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.resolveFolder(userID, req.FolderId, req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.PageSize)
+	if limit <= 0 {
+		limit = defaultFolderPageSize
+	}
+	if limit > maxFolderPageSize {
+		limit = maxFolderPageSize
+	}
+	offset := 0
+	if req.PageToken != "" {
+		if offset, err = strconv.Atoi(req.PageToken); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	folderID := ""
+	resp := &pb.ListFolderResponse{}
+	if f != nil {
+		folderID = f.ID
+		resp.Folder = folderToPB(f)
+	}
+	if offset == 0 {
+		folders, err := s.db.ListSubfolders(userID, folderID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+		}
+		for _, sub := range folders {
+			resp.Folders = append(resp.Folders, folderToPB(sub))
+		}
+	}
+	filter, err := newFileFilter(req.MetadataFilter, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	files, err := s.db.ListFolderFiles(userID, folderID, filter, offset, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	for _, e := range files {
+		resp.Files = append(resp.Files, fileEntryToPB(e))
+	}
+	if len(files) == limit {
+		resp.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	return resp, nil
+}
+
+// LookupPath resolves a path in the caller's tree to a folder or a file. When
+// several files in a folder share a name, the newest one is returned.
+func (s *UploadService) LookupPath(ctx context.Context, req *pb.LookupPathRequest) (*pb.LookupPathResponse, error) {
+	s = s.scoped(ctx)
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := splitPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "path is required")
+	}
+
+	parent, err := s.resolveFolder(userID, "", strings.Join(names[:len(names)-1], "/"))
+	if err != nil {
+		return nil, err
+	}
+	parentID := ""
+	if parent != nil {
+		parentID = parent.ID
+	}
+	last := names[len(names)-1]
+
+	f, err := s.db.FolderChild(userID, parentID, last)
+	if err == nil {
+		return &pb.LookupPathResponse{Entry: &pb.LookupPathResponse_Folder{Folder: folderToPB(f)}}, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	e, err := s.db.FolderFileByName(userID, parentID, last)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "no such file or folder: %s", req.Path)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	return &pb.LookupPathResponse{Entry: &pb.LookupPathResponse_File{File: fileEntryToPB(e)}}, nil
+}
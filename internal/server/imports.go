@@ -0,0 +1,658 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// Imports are uploads whose content the server fetches itself. The content
+// is written as encrypted chunks like an uploaded file, so an interrupted
+// fetch resumes with a Range request after the last complete chunk. A
+// server fetching an import holds a lease on it; imports left without one,
+// e.g. by a restarted server, are picked up again by ResumeImports.
+
+const (
+	importChunkSize       = 4 << 20
+	maxConcurrentImports  = 4
+	maxImportAttempts     = 8
+	maxImportRedirects    = 5
+	maxImportURLLen       = 2048
+	defaultImportMaxSize  = 10 << 30
+	importLease           = 2 * time.Minute
+	importStallTimeout    = time.Minute
+	importFinishLease     = time.Hour // merging and scanning large files takes a while
+	importMaxRetryBackoff = 5 * time.Minute
+)
+
+// blockedNets are address ranges imports may not reach unless allowlisted:
+// loopback, private, link-local, shared, reserved and multicast ranges, and
+// IPv6 prefixes that embed IPv4 addresses
+var blockedNets = mustParseNets("0.0.0.0/8,10.0.0.0/8,100.64.0.0/10,127.0.0.0/8,169.254.0.0/16,172.16.0.0/12," +
+	"192.0.0.0/24,192.0.2.0/24,192.168.0.0/16,198.18.0.0/15,198.51.100.0/24,203.0.113.0/24,224.0.0.0/4,240.0.0.0/4," +
+	"::/128,::1/128,64:ff9b::/96,64:ff9b:1::/48,2001::/32,2001:db8::/32,2002::/16,fc00::/7,fe80::/10,ff00::/8")
+
+// ImportPolicy bounds what ImportFromURL may fetch
+type ImportPolicy struct {
+	MaxSize     int64        // bytes per import; 0 for the default of 10 GiB
+	AllowedNets []*net.IPNet // blocked ranges that may be fetched anyway
+}
+
+// ParseNets parses a comma-separated list of CIDR ranges
+func ParseNets(list string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func mustParseNets(list string) []*net.IPNet {
+	nets, err := ParseNets(list)
+	if err != nil {
+		panic(err)
+	}
+	return nets
+}
+
+// importError marks failures retrying cannot fix
+type importError struct{ err error }
+
+func (e *importError) Error() string { return e.err.Error() }
+func (e *importError) Unwrap() error { return e.err }
+
+func permanent(err error) error { return &importError{err} }
+
+// urlImporter fetches import sources over HTTP(S)
+type urlImporter struct {
+	client  *http.Client
+	allowed []*net.IPNet
+	maxSize int64
+	slots   chan struct{}
+}
+
+func newURLImporter(p ImportPolicy) *urlImporter {
+	im := &urlImporter{allowed: p.AllowedNets, maxSize: p.MaxSize, slots: make(chan struct{}, maxConcurrentImports)}
+	if im.maxSize <= 0 {
+		im.maxSize = defaultImportMaxSize
+	}
+	im.client = &http.Client{
+		Transport: guardedTransport(im.allowed),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxImportRedirects {
+				return permanent(errors.New("too many redirects"))
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return permanent(fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme))
+			}
+			return nil
+		},
+	}
+	return im
+}
+
+// guardedTransport makes outbound requests that cannot reach blocked ranges
+// outside allowed. Addresses are checked as connections are made, after
+// name resolution and for every redirect, so DNS tricks cannot get around it.
+func guardedTransport(allowed []*net.IPNet) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return permanent(err)
+			}
+			return checkOutboundIP(net.ParseIP(host), allowed)
+		},
+	}
+	return &http.Transport{
+		// No proxy: it would connect on our behalf, past the address check
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+	}
+}
+
+// checkOutboundIP refuses addresses in blocked ranges that are not allowlisted
+func checkOutboundIP(ip net.IP, allowed []*net.IPNet) error {
+	if ip == nil {
+		return permanent(errors.New("invalid address"))
+	}
+	for _, n := range allowed {
+		if n.Contains(ip) {
+			return nil
+		}
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return permanent(fmt.Errorf("address %s is not publicly routable", ip))
+		}
+	}
+	return nil
+}
+
+// SetImportPolicy configures the limits of ImportFromURL
+func (s *UploadService) SetImportPolicy(p ImportPolicy) {
+	s.importer = newURLImporter(p)
+}
+
+// URLImport is the state of fetching the content of an upload
+type URLImport struct {
+	FileID        string
+	SourceURL     string
+	SHA256        string // expected content hash, if given
+	Status        string // pending, downloading, completed or failed
+	BytesReceived int64
+	TotalBytes    int64 // 0 while unknown
+	Validator     string
+	Attempts      int
+	Error         string
+}
+
+// CreateImport records that the content of an upload is to be fetched from sourceURL
+func (db *UploadDB) CreateImport(fileID, sourceURL, sha256 string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO url_imports (file_id, source_url, expected_sha256) VALUES ($1, $2, NULLIF($3, ''))`,
+		fileID, sourceURL, sha256,
+	)
+	return err
+}
+
+// ClaimImport takes the lease on an unfinished import nobody holds and
+// counts the attempt. It returns pgx.ErrNoRows if there is none.
+func (db *UploadDB) ClaimImport(fileID string, lease time.Duration) (*URLImport, error) {
+	imp := &URLImport{FileID: fileID, Status: "downloading"}
+	err := db.pool.QueryRow(context.Background(),
+		`UPDATE url_imports SET status = 'downloading', attempts = attempts + 1,
+		        lease_until = now() + $2 * interval '1 second', updated_at = now()
+		 WHERE file_id = $1 AND status IN ('pending', 'downloading') AND (lease_until IS NULL OR lease_until < now())
+		 RETURNING source_url, COALESCE(expected_sha256, ''), bytes_received, total_bytes, COALESCE(validator, ''), attempts`,
+		fileID, lease.Seconds(),
+	).Scan(&imp.SourceURL, &imp.SHA256, &imp.BytesReceived, &imp.TotalBytes, &imp.Validator, &imp.Attempts)
+	if err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+// ImportProgress records the bytes fetched so far and renews the lease
+func (db *UploadDB) ImportProgress(fileID string, received, total int64, validator string, lease time.Duration) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE url_imports SET bytes_received = $2, total_bytes = $3, validator = NULLIF($4, ''),
+		        lease_until = now() + $5 * interval '1 second', updated_at = now()
+		 WHERE file_id = $1`,
+		fileID, received, total, validator, lease.Seconds(),
+	)
+	return err
+}
+
+// ReleaseImport gives up the lease after a failed attempt, keeping the error
+func (db *UploadDB) ReleaseImport(fileID, errMsg string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE url_imports SET error = $2, lease_until = NULL, updated_at = now() WHERE file_id = $1`,
+		fileID, errMsg,
+	)
+	return err
+}
+
+// FinishImport stores the final status of an import
+func (db *UploadDB) FinishImport(fileID, status, errMsg string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE url_imports SET status = $2, error = NULLIF($3, ''), lease_until = NULL, updated_at = now()
+		 WHERE file_id = $1`,
+		fileID, status, errMsg,
+	)
+	return err
+}
+
+// GetImport loads the import of an upload
+func (db *UploadDB) GetImport(fileID string) (*URLImport, error) {
+	imp := &URLImport{FileID: fileID}
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT source_url, COALESCE(expected_sha256, ''), status, bytes_received, total_bytes,
+		        COALESCE(validator, ''), attempts, COALESCE(error, '')
+		 FROM url_imports WHERE file_id = $1`,
+		fileID,
+	).Scan(&imp.SourceURL, &imp.SHA256, &imp.Status, &imp.BytesReceived, &imp.TotalBytes, &imp.Validator, &imp.Attempts, &imp.Error)
+	if err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+// UnfinishedImports returns the imports nobody holds a lease on
+func (db *UploadDB) UnfinishedImports() ([]string, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT file_id::text FROM url_imports
+		 WHERE status IN ('pending', 'downloading') AND (lease_until IS NULL OR lease_until < now())`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// parseImportURL validates the source of an import
+func (s *UploadService) parseImportURL(raw string) (*url.URL, error) {
+	if len(raw) > maxImportURLLen {
+		return nil, status.Errorf(codes.InvalidArgument, "url exceeds %d bytes", maxImportURLLen)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, status.Error(codes.InvalidArgument, "url must be an absolute http or https URL")
+	}
+	if u.User != nil {
+		return nil, status.Error(codes.InvalidArgument, "credentials in the url are not supported")
+	}
+	// Names are checked once they resolve; literal addresses can be refused now
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if err := checkOutboundIP(ip, s.importer.allowed); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	u.Fragment = ""
+	return u, nil
+}
+
+// fillChunk reads into buf until it is full or the body ends. Unlike
+// io.ReadFull it returns the body's own error, so only io.EOF means the
+// body ended cleanly and a truncated one surfaces as io.ErrUnexpectedEOF.
+func fillChunk(r io.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		m, err := r.Read(buf[n:])
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// parseContentRange parses "bytes first-last/size" into first and size,
+// with size -1 when the server does not know it. Ranges that are reversed
+// or end past the size are refused.
+func parseContentRange(v string) (int64, int64, bool) {
+	rest, ok := strings.CutPrefix(v, "bytes ")
+	if !ok {
+		return 0, 0, false
+	}
+	span, size, ok := strings.Cut(rest, "/")
+	if !ok {
+		return 0, 0, false
+	}
+	first, last, ok := strings.Cut(span, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseUint(first, 10, 63)
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err := strconv.ParseUint(last, 10, 63)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+	if size == "*" {
+		return int64(start), -1, true
+	}
+	total, err := strconv.ParseUint(size, 10, 63)
+	if err != nil || end >= total {
+		return 0, 0, false
+	}
+	return int64(start), int64(total), true
+}
+
+// importValidator returns what identifies the version of a response for
+// If-Range: a strong ETag, or else the modification time
+func importValidator(h http.Header) string {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return h.Get("Last-Modified")
+}
+
+// runImport fetches an import in the background, retrying failed attempts
+// with exponential backoff
+func (s *UploadService) runImport(fileID string) {
+	go func() {
+		for {
+			s.importer.slots <- struct{}{}
+			imp, err := s.db.ClaimImport(fileID, importLease)
+			if err != nil {
+				<-s.importer.slots
+				if !errors.Is(err, pgx.ErrNoRows) {
+					log.Printf("Import error: file_id=%s, error=%v", fileID, err)
+				}
+				return
+			}
+			err = s.fetchImport(imp)
+			<-s.importer.slots
+			if err == nil {
+				return
+			}
+
+			var ie *importError
+			if errors.As(err, &ie) || imp.Attempts >= maxImportAttempts {
+				s.failImport(fileID, err)
+				return
+			}
+			backoff := min(time.Second<<imp.Attempts, importMaxRetryBackoff)
+			log.Printf("Import retry: file_id=%s, attempt=%d, backoff=%s, error=%v", fileID, imp.Attempts, backoff, err)
+			if err := s.db.ReleaseImport(fileID, err.Error()); err != nil {
+				log.Printf("Import error: file_id=%s, error=%v", fileID, err)
+			}
+			time.Sleep(backoff)
+		}
+	}()
+}
+
+// failImport gives up on an import and fails its upload
+func (s *UploadService) failImport(fileID string, cause error) {
+	log.Printf("Import failed: file_id=%s, error=%v", fileID, cause)
+	if err := s.db.FinishImport(fileID, "failed", cause.Error()); err != nil {
+		log.Printf("Import error: file_id=%s, error=%v", fileID, err)
+	}
+	ctx := context.Background()
+	rec, err := s.db.getUpload(fileID, "")
+	if err != nil {
+		return
+	}
+	// finishUpload may already have failed the upload with a better reason
+	if rec.Status == "in_progress" {
+		s.failUpload(fileID, cause.Error())
+		tmpDir, _, _ := paths(s.tempDir, fileID, rec.FileName)
+		os.RemoveAll(tmpDir)
+		cleanupChunks(ctx, s.rdb, fileID)
+	}
+	s.auditAs(ctx, rec.UserID, "complete", fileID, "failed", "import")
+}
+
+// fetchImport makes one attempt at fetching an import, continuing after
+// the chunks earlier attempts stored
+func (s *UploadService) fetchImport(imp *URLImport) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rec, err := s.db.GetUploadByID(imp.FileID)
+	if err != nil {
+		return permanent(fmt.Errorf("upload not found: %w", err))
+	}
+	if rec.Status != "in_progress" {
+		return permanent(fmt.Errorf("upload is already %s", rec.Status))
+	}
+	key, err := s.dataKey(rec)
+	if err != nil {
+		return permanent(err)
+	}
+	tmpDir, _, _ := paths(s.tempDir, rec.FileID, rec.FileName)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+
+	offset := imp.BytesReceived / importChunkSize * importChunkSize
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imp.SourceURL, nil)
+	if err != nil {
+		return permanent(err)
+	}
+	// Byte ranges must refer to the stored representation, not a compressed one
+	req.Header.Set("Accept-Encoding", "identity")
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+		if imp.Validator != "" {
+			req.Header.Set("If-Range", imp.Validator)
+		}
+	}
+	resp, err := s.importer.client.Do(req)
+	if err != nil {
+		// The error is stored and shown to clients, so it must not carry the query
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			ue.URL = redactURL(ue.URL)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	var total int64
+	switch code := resp.StatusCode; {
+	case code == http.StatusPartialContent && offset > 0:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			// Start over rather than guess what the server sent
+			s.db.ImportProgress(rec.FileID, 0, 0, "", importLease)
+			return fmt.Errorf("unexpected Content-Range %q", resp.Header.Get("Content-Range"))
+		}
+		total = max(size, 0)
+	case code == http.StatusOK:
+		// The server ignored the range or the resource changed
+		offset = 0
+		total = max(resp.ContentLength, 0)
+	case code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500:
+		return fmt.Errorf("source responded %s", resp.Status)
+	default:
+		return permanent(fmt.Errorf("source responded %s", resp.Status))
+	}
+	if total > s.importer.maxSize {
+		return permanent(fmt.Errorf("source is larger than %d bytes", s.importer.maxSize))
+	}
+	if total > 0 {
+		if err := s.checkQuota(total); err != nil {
+			return permanent(err)
+		}
+	}
+	if offset == 0 && imp.BytesReceived > 0 {
+		os.RemoveAll(tmpDir)
+		cleanupChunks(ctx, s.rdb, rec.FileID)
+		if err := os.MkdirAll(tmpDir, 0755); err != nil {
+			return err
+		}
+	}
+	validator := importValidator(resp.Header)
+	if err := s.db.ImportProgress(rec.FileID, offset, total, validator, importLease); err != nil {
+		return err
+	}
+
+	// Stalled transfers are cut off and retried
+	stall := time.AfterFunc(importStallTimeout, cancel)
+	defer stall.Stop()
+
+	received := offset
+	idx := offset / importChunkSize
+	buf := make([]byte, importChunkSize)
+	for {
+		n, rerr := fillChunk(resp.Body, buf)
+		if rerr != nil && rerr != io.EOF {
+			// A cut connection is retried; the partial chunk is fetched again
+			return rerr
+		}
+		if n > 0 || idx == 0 {
+			received += int64(n)
+			if received > s.importer.maxSize {
+				return permanent(fmt.Errorf("source is larger than %d bytes", s.importer.maxSize))
+			}
+			if err := s.checkFileSize(received); err != nil {
+				return permanent(err)
+			}
+			if idx == 0 {
+				detected, err := s.policy.rulesFor(rec.UserID).checkContent(rec.FileName, buf[:n])
+				if err != nil {
+					return permanent(err)
+				}
+				if err := s.db.SetMimeType(rec.FileID, detected); err != nil {
+					log.Printf("Import mime_type update error: file_id=%s, error=%v", rec.FileID, err)
+				}
+			}
+			chunkPath := filepath.Join(tmpDir, fmt.Sprintf("chunk_%d", idx))
+			if err := writeStored(chunkPath, buf[:n], key, chunkStreamID(idx)); err != nil {
+				return err
+			}
+			if err := markChunk(ctx, s.rdb, rec.FileID, idx); err != nil {
+				return err
+			}
+			if _, err := reserveChunk(ctx, s.rdb, rec.FileID, idx, int64(n), 0); err != nil {
+				return err
+			}
+			idx++
+			if err := s.db.ImportProgress(rec.FileID, received, total, validator, importLease); err != nil {
+				return err
+			}
+			s.chunkReceived(rec.FileID, idx-1, idx, 0, received, total)
+			stall.Reset(importStallTimeout)
+		}
+		if rerr == io.EOF {
+			break
+		}
+	}
+	stall.Stop()
+	if total > 0 && received != total {
+		return fmt.Errorf("connection closed after %d of %d bytes", received, total)
+	}
+
+	if err := s.db.ImportProgress(rec.FileID, received, received, validator, importFinishLease); err != nil {
+		return err
+	}
+	blob, result, err := s.finishUpload(context.Background(), rec, idx, key, imp.SHA256, "import")
+	if err != nil {
+		return permanent(err)
+	}
+	if result != nil && !result.Clean {
+		log.Printf("Import quarantined: file_id=%s, signature=%s", rec.FileID, result.Signature)
+	} else {
+		log.Printf("Import success: file_id=%s, sha256=%s, size=%d", rec.FileID, blob.SHA256, received)
+	}
+	if err := s.db.FinishImport(rec.FileID, "completed", ""); err != nil {
+		log.Printf("Import error: file_id=%s, error=%v", rec.FileID, err)
+	}
+	return nil
+}
+
+// ResumeImports restarts the unfinished imports of every tenant that no
+// server is working on, e.g. after a restart
+func (s *UploadService) ResumeImports() {
+	s.eachTenant(func(ts *UploadService) {
+		ids, err := ts.db.UnfinishedImports()
+		if err != nil {
+			log.Printf("Import resume error: tenant=%s, error=%v", ts.tenant.ID, err)
+			return
+		}
+		for _, id := range ids {
+			ts.runImport(id)
+		}
+	})
+}
+
+// importState returns the import of an upload for GetUploadMetadata, or nil
+// when it was uploaded by a client
+func (s *UploadService) importState(fileID string) *pb.URLImport {
+	imp, err := s.db.GetImport(fileID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("GetUploadMetadata import error: file_id=%s, error=%v", fileID, err)
+		}
+		return nil
+	}
+	return &pb.URLImport{
+		SourceUrl:     redactURL(imp.SourceURL),
+		Status:        imp.Status,
+		BytesReceived: imp.BytesReceived,
+		TotalBytes:    imp.TotalBytes,
+		Attempts:      int32(imp.Attempts),
+		Error:         imp.Error,
+	}
+}
+
+// redactURL reduces a URL to its scheme, host and path, since credentials
+// such as presigned signatures often travel in the query string
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}).String()
+}
+
+// ImportFromURL creates an upload for the caller and fetches its content
+// from an HTTP(S) URL in the background
+func (s *UploadService) ImportFromURL(ctx context.Context, req *pb.ImportFromURLRequest) (*pb.ImportFromURLResponse, error) {
+	s = s.scoped(ctx)
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.parseImportURL(req.Url)
+	if err != nil {
+		return nil, err
+	}
+	if req.Sha256 != "" && !validSHA256(req.Sha256) {
+		return nil, status.Error(codes.InvalidArgument, "sha256 must be 64 lowercase hex characters")
+	}
+	name := req.FileName
+	if name == "" {
+		name = path.Base(u.Path)
+		if name == "/" || name == "." {
+			name = "download"
+		}
+	}
+
+	// The upload is created exactly like one a client would send
+	init, err := s.InitUpload(ctx, &pb.InitRequest{
+		FileName:   name,
+		FolderId:   req.FolderId,
+		FolderPath: req.FolderPath,
+		Metadata:   req.Metadata,
+		Tags:       req.Tags,
+		Extract:    req.Extract,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.CreateImport(init.FileId, u.String(), req.Sha256); err != nil {
+		log.Printf("ImportFromURL error: user_id=%s, file_id=%s, error=%v", c.UserID, init.FileId, err)
+		s.failUpload(init.FileId, "failed to record the import")
+		return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+	}
+	s.runImport(init.FileId)
+
+	// Query strings often carry credentials, so only the host is logged
+	log.Printf("ImportFromURL accepted: user_id=%s, file_id=%s, host=%s", c.UserID, init.FileId, u.Host)
+	return &pb.ImportFromURLResponse{FileId: init.FileId}, nil
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// Limits on user metadata and tags of one file
+const (
+	maxMetadataEntries  = 64
+	maxMetadataKeyLen   = 128
+	maxMetadataValueLen = 1024
+	maxTags             = 64
+	maxTagLen           = 64
+)
+
+// metadataValuesSQL inserts the metadata and tags parameters, storing empty
+// values for nil ones
+func metadataValuesSQL(metadata, tags string) string {
+	return "COALESCE(" + metadata + "::jsonb, '{}'::jsonb), COALESCE(" + tags + "::text[], '{}')"
+}
+
+// validMetadataKey allows letters, digits and . _ - : /
+func validMetadataKey(k string) bool {
+	if k == "" || len(k) > maxMetadataKeyLen {
+		return false
+	}
+	for _, c := range k {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '_', c == '-', c == ':', c == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// checkMetadata validates a complete set of metadata
+func checkMetadata(m map[string]string) error {
+	if len(m) > maxMetadataEntries {
+		return status.Errorf(codes.InvalidArgument, "at most %d metadata entries are allowed", maxMetadataEntries)
+	}
+	for k, v := range m {
+		if !validMetadataKey(k) {
+			return status.Errorf(codes.InvalidArgument, "invalid metadata key %q", k)
+		}
+		if len(v) > maxMetadataValueLen {
+			return status.Errorf(codes.InvalidArgument, "metadata value of %q exceeds %d bytes", k, maxMetadataValueLen)
+		}
+	}
+	return nil
+}
+
+// normalizeTags trims, validates, dedupes and sorts tags
+func normalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	out := []string{}
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || len(t) > maxTagLen || strings.ContainsAny(t, ",\n\r\t") {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid tag %q", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	if len(out) > maxTags {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d tags are allowed", maxTags)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// FileFilter restricts listings to files having all of the given metadata
+// entries and tags
+type FileFilter struct {
+	Metadata map[string]string
+	Tags     []string
+}
+
+// newFileFilter validates filter values from a request
+func newFileFilter(metadata map[string]string, tags []string) (FileFilter, error) {
+	if err := checkMetadata(metadata); err != nil {
+		return FileFilter{}, err
+	}
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return FileFilter{}, err
+	}
+	return FileFilter{Metadata: metadata, Tags: normalized}, nil
+}
+
+// sql is the condition on files aliased r, given the placeholders for the
+// metadata and tags arguments
+func (f FileFilter) sql(metadata, tags string) string {
+	return "r.metadata @> " + metadata + "::jsonb AND r.tags @> " + tags + "::text[]"
+}
+
+func (f FileFilter) metadata() map[string]string {
+	if f.Metadata == nil {
+		return map[string]string{}
+	}
+	return f.Metadata
+}
+
+func (f FileFilter) tags() []string {
+	if f.Tags == nil {
+		return []string{}
+	}
+	return f.Tags
+}
+
+// UpdateFileMetadata applies changes to the metadata and tags of a file,
+// validating the result before storing it
+func (db *UploadDB) UpdateFileMetadata(rootID string, set map[string]string, removeKeys, addTags, removeTags []string) error {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var metadata map[string]string
+	var tags []string
+	err = tx.QueryRow(ctx,
+		`SELECT metadata, tags FROM uploads WHERE file_id = $1 FOR UPDATE`,
+		rootID,
+	).Scan(&metadata, &tags)
+	if err != nil {
+		return err
+	}
+
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	for k, v := range set {
+		metadata[k] = v
+	}
+	for _, k := range removeKeys {
+		delete(metadata, k)
+	}
+	if err := checkMetadata(metadata); err != nil {
+		return err
+	}
+	drop := make(map[string]bool, len(removeTags))
+	for _, t := range removeTags {
+		drop[strings.TrimSpace(t)] = true
+	}
+	var kept []string
+	for _, t := range append(tags, addTags...) {
+		if !drop[strings.TrimSpace(t)] {
+			kept = append(kept, t)
+		}
+	}
+	if tags, err = normalizeTags(kept); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE uploads SET metadata = $2, tags = $3 WHERE file_id = $1`,
+		rootID, metadata, tags,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UpdateFileMetadata changes the user metadata and tags of a file
+func (s *UploadService) UpdateFileMetadata(ctx context.Context, req *pb.UpdateFileMetadataRequest) (*pb.FileEntry, error) {
+	s = s.scoped(ctx)
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if _, err := s.authorize(ctx, rec, permWrite); err != nil {
+		return nil, err
+	}
+	root, err := s.rootOf(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+
+	err = s.db.UpdateFileMetadata(root.FileID, req.Metadata, req.RemoveKeys, req.AddTags, req.RemoveTags)
+	if err != nil {
+		// Validation of the merged result fails with a status
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "db update error: %v", err)
+	}
+
+	log.Printf("UpdateFileMetadata success: file_id=%s, set=%d, removed=%d, tags_added=%d, tags_removed=%d",
+		root.FileID, len(req.Metadata), len(req.RemoveKeys), len(req.AddTags), len(req.RemoveTags))
+	return s.fileEntryResponse(root.FileID)
+}
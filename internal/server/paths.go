@@ -24,4 +24,17 @@ func sanitizeFilename(name string) string {
 		name = "file"
 	}
 	return name
-}
\ No newline at end of file
+}
+
+// validSHA256 reports whether s is a lowercase hex SHA-256 digest
+func validSHA256(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
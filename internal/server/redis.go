@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"time"
 
@@ -30,6 +31,74 @@ func listedChunks(ctx context.Context, rdb *redis.Client, fileID string) (map[in
 	return set, nil
 }
 
+// errChunkLimit is returned by reserveChunk when a chunk would take an
+// upload over its size limit
+var errChunkLimit = errors.New("upload size limit exceeded")
+
+// reserveScript sets the plaintext size of chunk ARGV[1] to ARGV[2] and
+// adjusts the upload's total by the difference, unless the total would
+// exceed ARGV[4] (0 for no limit), in which case it returns -1
+var reserveScript = redis.NewScript(`
+local old = tonumber(redis.call("HGET", KEYS[1], ARGV[1]) or "0")
+local n = tonumber(ARGV[2])
+local total = tonumber(redis.call("GET", KEYS[2]) or "0") + n - old
+local limit = tonumber(ARGV[4])
+if limit > 0 and total > limit then
+	return -1
+end
+redis.call("HSET", KEYS[1], ARGV[1], n)
+redis.call("INCRBY", KEYS[2], n - old)
+redis.call("EXPIRE", KEYS[1], ARGV[3])
+redis.call("EXPIRE", KEYS[2], ARGV[3])
+return total
+`)
+
+// releaseScript forgets the size of chunk ARGV[1] and takes it off the total
+var releaseScript = redis.NewScript(`
+local old = redis.call("HGET", KEYS[1], ARGV[1])
+if old then
+	redis.call("HDEL", KEYS[1], ARGV[1])
+	redis.call("DECRBY", KEYS[2], old)
+end
+return 0
+`)
+
+// reserveChunk counts chunk idx of n plaintext bytes towards an upload's
+// total before it is written, atomically across streams, and returns the
+// new total. A chunk sent again replaces its earlier size rather than
+// adding to it. With limit > 0 a chunk that would take the total over it
+// is refused with errChunkLimit.
+func reserveChunk(ctx context.Context, rdb *redis.Client, fileID string, idx, n, limit int64) (int64, error) {
+	keys := []string{"upload:" + fileID + ":sizes", "upload:" + fileID + ":bytes"}
+	total, err := reserveScript.Run(ctx, rdb, keys, idx, n, int64((24 * time.Hour).Seconds()), limit).Int64()
+	if err != nil {
+		return 0, err
+	}
+	if total < 0 {
+		return 0, errChunkLimit
+	}
+	return total, nil
+}
+
+// releaseChunk undoes the reservation of a chunk that could not be stored
+func releaseChunk(ctx context.Context, rdb *redis.Client, fileID string, idx int64) error {
+	keys := []string{"upload:" + fileID + ":sizes", "upload:" + fileID + ":bytes"}
+	return releaseScript.Run(ctx, rdb, keys, idx).Err()
+}
+
+// receivedBytes returns the chunks and plaintext bytes of an upload received
+// so far. Chunks on disk may be encrypted and larger than their plaintext.
+func receivedBytes(ctx context.Context, rdb *redis.Client, fileID string) (int64, int64, error) {
+	pipe := rdb.Pipeline()
+	bytes := pipe.Get(ctx, "upload:"+fileID+":bytes")
+	chunks := pipe.SCard(ctx, "upload:"+fileID+":chunks")
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, 0, err
+	}
+	n, _ := bytes.Int64()
+	return chunks.Val(), n, nil
+}
+
 func cleanupChunks(ctx context.Context, rdb *redis.Client, fileID string) error {
-	return rdb.Del(ctx, "upload:"+fileID+":chunks").Err()
+	return rdb.Del(ctx, "upload:"+fileID+":chunks", "upload:"+fileID+":bytes", "upload:"+fileID+":sizes").Err()
 }
\ No newline at end of file
@@ -0,0 +1,436 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// retentionSystemActor is recorded as the actor of automatic policy decisions
+const retentionSystemActor = "retention-policy"
+
+// RetentionPolicy bounds how long files are kept. Zero durations mean no bound.
+type RetentionPolicy struct {
+	ID           string
+	Name         string
+	MinRetention time.Duration
+	MaxRetention time.Duration
+	CreatedBy    string
+	CreatedAt    time.Time
+}
+
+// RetentionState is what decides whether an upload may be deleted. Holds
+// and policies apply to a file as a whole and are read from its first
+// version. CreatedAt is the upload's creation time, or for a file's first
+// version that of its newest version, since deleting it deletes them all.
+type RetentionState struct {
+	CreatedAt  time.Time
+	LegalHold  bool
+	HoldReason string
+	Policy     *RetentionPolicy // nil when no policy applies
+}
+
+// RetainUntil is the earliest time the upload may be deleted, or zero
+func (r *RetentionState) RetainUntil() time.Time {
+	if r.Policy == nil || r.Policy.MinRetention <= 0 {
+		return time.Time{}
+	}
+	return r.CreatedAt.Add(r.Policy.MinRetention)
+}
+
+// CreateRetentionPolicy stores a new policy
+func (db *UploadDB) CreateRetentionPolicy(p *RetentionPolicy) error {
+	return db.pool.QueryRow(context.Background(),
+		`INSERT INTO retention_policies(id, name, min_retention_seconds, max_retention_seconds, created_by)
+		 VALUES($1, $2, $3, $4, $5)
+		 RETURNING created_at`,
+		p.ID, p.Name, int64(p.MinRetention/time.Second), int64(p.MaxRetention/time.Second), p.CreatedBy,
+	).Scan(&p.CreatedAt)
+}
+
+// ListRetentionPolicies returns all policies by name
+func (db *UploadDB) ListRetentionPolicies() ([]*RetentionPolicy, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT id::text, name, min_retention_seconds, max_retention_seconds, created_by, created_at
+		 FROM retention_policies ORDER BY name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*RetentionPolicy
+	for rows.Next() {
+		var p RetentionPolicy
+		var minSec, maxSec int64
+		if err := rows.Scan(&p.ID, &p.Name, &minSec, &maxSec, &p.CreatedBy, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		p.MinRetention = time.Duration(minSec) * time.Second
+		p.MaxRetention = time.Duration(maxSec) * time.Second
+		policies = append(policies, &p)
+	}
+	return policies, rows.Err()
+}
+
+// SetFileRetentionPolicy assigns a policy to a file; an empty policyID clears it
+func (db *UploadDB) SetFileRetentionPolicy(fileID, policyID string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE uploads SET retention_policy_id = NULLIF($2::text, '')::uuid WHERE file_id = $1`,
+		fileID, policyID,
+	)
+	return err
+}
+
+// SetUserRetentionPolicy assigns a policy to every file of a user; an empty policyID clears it
+func (db *UploadDB) SetUserRetentionPolicy(userID, policyID string) error {
+	if policyID == "" {
+		_, err := db.pool.Exec(context.Background(),
+			`DELETE FROM user_retention_policies WHERE user_id = $1`, userID)
+		return err
+	}
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO user_retention_policies(user_id, policy_id) VALUES($1, $2)
+		 ON CONFLICT (tenant_id, user_id) DO UPDATE SET policy_id = EXCLUDED.policy_id, assigned_at = now()`,
+		userID, policyID,
+	)
+	return err
+}
+
+// SetLegalHold places or releases a legal hold on a file
+func (db *UploadDB) SetLegalHold(fileID string, hold bool, reason, setBy string) (time.Time, error) {
+	var at time.Time
+	err := db.pool.QueryRow(context.Background(),
+		`UPDATE uploads SET legal_hold = $2,
+		        legal_hold_reason = CASE WHEN $2 THEN NULLIF($3, '') END,
+		        legal_hold_by = $4, legal_hold_at = now()
+		 WHERE file_id = $1
+		 RETURNING legal_hold_at`,
+		fileID, hold, reason, setBy,
+	).Scan(&at)
+	return at, err
+}
+
+// RetentionState loads the hold and effective policy of an upload, trashed or not
+func (db *UploadDB) RetentionState(fileID string) (*RetentionState, error) {
+	var st RetentionState
+	var policyID, name string
+	var minSec, maxSec int64
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT (SELECT MAX(created_at) FROM uploads WHERE file_id = u.file_id OR version_of = u.file_id),
+		        r.legal_hold, COALESCE(r.legal_hold_reason, ''),
+		        COALESCE(p.id::text, ''), COALESCE(p.name, ''),
+		        COALESCE(p.min_retention_seconds, 0), COALESCE(p.max_retention_seconds, 0)
+		 FROM uploads u
+		 JOIN uploads r ON r.file_id = COALESCE(u.version_of, u.file_id)
+		 LEFT JOIN user_retention_policies up ON up.user_id = r.user_id::text
+		 LEFT JOIN retention_policies p ON p.id = COALESCE(r.retention_policy_id, up.policy_id)
+		 WHERE u.file_id = $1`,
+		fileID,
+	).Scan(&st.CreatedAt, &st.LegalHold, &st.HoldReason, &policyID, &name, &minSec, &maxSec)
+	if err != nil {
+		return nil, err
+	}
+	if policyID != "" {
+		st.Policy = &RetentionPolicy{
+			ID:           policyID,
+			Name:         name,
+			MinRetention: time.Duration(minSec) * time.Second,
+			MaxRetention: time.Duration(maxSec) * time.Second,
+		}
+	}
+	return &st, nil
+}
+
+// ExpiredByRetention returns up to limit files older than their policy's
+// maximum retention that are neither held nor already in the trash
+func (db *UploadDB) ExpiredByRetention(limit int) ([]string, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT r.file_id::text
+		 FROM uploads r
+		 LEFT JOIN user_retention_policies up ON up.user_id = r.user_id::text
+		 JOIN retention_policies p ON p.id = COALESCE(r.retention_policy_id, up.policy_id)
+		 WHERE r.version_of IS NULL AND r.deleted_at IS NULL AND NOT r.legal_hold
+		   AND p.max_retention_seconds > 0
+		   AND r.created_at + p.max_retention_seconds * interval '1 second' < now()
+		 ORDER BY r.created_at
+		 LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// retentionDenial returns why an upload may not be deleted yet, or nil
+func (s *UploadService) retentionDenial(fileID string) (denial error, err error) {
+	st, err := s.db.RetentionState(fileID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "retention lookup error: %v", err)
+	}
+	switch {
+	case st.LegalHold:
+		denial = status.Error(codes.FailedPrecondition, "file is under legal hold")
+	case time.Now().Before(st.RetainUntil()):
+		denial = status.Errorf(codes.FailedPrecondition, "retention policy %q keeps this file until %s",
+			st.Policy.Name, st.RetainUntil().UTC().Format(time.RFC3339))
+	}
+	return denial, nil
+}
+
+// checkRetention refuses to delete or purge an upload that is on legal hold
+// or younger than its minimum retention. Refusals are audited under action.
+func (s *UploadService) checkRetention(ctx context.Context, actor, action, fileID string) error {
+	denial, err := s.retentionDenial(fileID)
+	if err != nil {
+		return err
+	}
+	if denial != nil {
+		s.auditAs(ctx, actor, action, fileID, "denied", status.Convert(denial).Message())
+		log.Printf("Retention denied: file_id=%s, action=%s, error=%v", fileID, action, denial)
+	}
+	return denial
+}
+
+// retentionPolicyFromRequest validates a request and builds the policy it describes
+func retentionPolicyFromRequest(req *pb.CreateRetentionPolicyRequest) (*RetentionPolicy, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 128 {
+		return nil, status.Error(codes.InvalidArgument, "name must be 1-128 characters")
+	}
+	if req.MinRetentionSeconds < 0 || req.MaxRetentionSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "retention periods must not be negative")
+	}
+	if req.MinRetentionSeconds == 0 && req.MaxRetentionSeconds == 0 {
+		return nil, status.Error(codes.InvalidArgument, "a policy needs a minimum or a maximum retention")
+	}
+	if req.MaxRetentionSeconds > 0 && req.MinRetentionSeconds > req.MaxRetentionSeconds {
+		return nil, status.Error(codes.InvalidArgument, "minimum retention exceeds maximum retention")
+	}
+	return &RetentionPolicy{
+		ID:           uuid.NewString(),
+		Name:         name,
+		MinRetention: time.Duration(req.MinRetentionSeconds) * time.Second,
+		MaxRetention: time.Duration(req.MaxRetentionSeconds) * time.Second,
+	}, nil
+}
+
+func retentionPolicyToPB(p *RetentionPolicy) *pb.RetentionPolicy {
+	return &pb.RetentionPolicy{
+		PolicyId:            p.ID,
+		Name:                p.Name,
+		MinRetentionSeconds: int64(p.MinRetention / time.Second),
+		MaxRetentionSeconds: int64(p.MaxRetention / time.Second),
+		CreatedBy:           p.CreatedBy,
+		CreatedAt:           p.CreatedAt.Unix(),
+	}
+}
+
+// CreateRetentionPolicy defines a new retention policy
+func (s *UploadService) CreateRetentionPolicy(ctx context.Context, req *pb.CreateRetentionPolicyRequest) (*pb.RetentionPolicy, error) {
+	s = s.scoped(ctx)
+	c, err := s.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p, err := retentionPolicyFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	p.CreatedBy = c.UserID
+	if err := s.db.CreateRetentionPolicy(p); err != nil {
+		return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+	}
+
+	s.auditAs(ctx, c.UserID, "retention-policy", "", "ok",
+		fmt.Sprintf("created policy %s (%q): min=%s max=%s", p.ID, p.Name, p.MinRetention, p.MaxRetention))
+	log.Printf("CreateRetentionPolicy success: policy_id=%s, name=%s", p.ID, p.Name)
+	return retentionPolicyToPB(p), nil
+}
+
+// ListRetentionPolicies lists all retention policies
+func (s *UploadService) ListRetentionPolicies(ctx context.Context, req *pb.ListRetentionPoliciesRequest) (*pb.ListRetentionPoliciesResponse, error) {
+	s = s.scoped(ctx)
+	if _, err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	policies, err := s.db.ListRetentionPolicies()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	resp := &pb.ListRetentionPoliciesResponse{}
+	for _, p := range policies {
+		resp.Policies = append(resp.Policies, retentionPolicyToPB(p))
+	}
+	return resp, nil
+}
+
+// AssignRetentionPolicy applies a policy to a file or to all files of a user
+func (s *UploadService) AssignRetentionPolicy(ctx context.Context, req *pb.AssignRetentionPolicyRequest) (*pb.AssignRetentionPolicyResponse, error) {
+	s = s.scoped(ctx)
+	c, err := s.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.PolicyId != "" {
+		if _, err := uuid.Parse(req.PolicyId); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid policy_id")
+		}
+	}
+
+	var fileID, detail string
+	switch target := req.Target.(type) {
+	case *pb.AssignRetentionPolicyRequest_FileId:
+		rec, err := s.db.getUpload(target.FileId, "")
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+		}
+		// Policies are kept on the file's first version
+		if rec.VersionOf != "" {
+			rec, err = s.db.getUpload(rec.VersionOf, "")
+			if err != nil {
+				return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+			}
+		}
+		fileID = rec.FileID
+		err = s.db.SetFileRetentionPolicy(fileID, req.PolicyId)
+		if err != nil {
+			return nil, retentionAssignError(err)
+		}
+		detail = "file policy set to " + orNone(req.PolicyId)
+	case *pb.AssignRetentionPolicyRequest_UserId:
+		if target.UserId == "" {
+			return nil, status.Error(codes.InvalidArgument, "user_id is required")
+		}
+		if err := s.db.SetUserRetentionPolicy(target.UserId, req.PolicyId); err != nil {
+			return nil, retentionAssignError(err)
+		}
+		detail = fmt.Sprintf("user %s policy set to %s", target.UserId, orNone(req.PolicyId))
+	default:
+		return nil, status.Error(codes.InvalidArgument, "file_id or user_id is required")
+	}
+
+	s.auditAs(ctx, c.UserID, "retention-policy", fileID, "ok", detail)
+	log.Printf("AssignRetentionPolicy success: file_id=%s, %s", fileID, detail)
+	return &pb.AssignRetentionPolicyResponse{PolicyId: req.PolicyId}, nil
+}
+
+// retentionAssignError maps a failed assignment, usually an unknown policy, to a status
+func retentionAssignError(err error) error {
+	if pgCode(err) == pgForeignKeyViolation {
+		return status.Error(codes.NotFound, "retention policy not found")
+	}
+	return status.Errorf(codes.Internal, "db update error: %v", err)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// SetLegalHold places or releases a legal hold, which blocks deletion and
+// purging of every version of a file regardless of policy
+func (s *UploadService) SetLegalHold(ctx context.Context, req *pb.SetLegalHoldRequest) (*pb.LegalHold, error) {
+	s = s.scoped(ctx)
+	c, err := s.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Reason) > 1024 {
+		return nil, status.Error(codes.InvalidArgument, "reason too long")
+	}
+	rec, err := s.db.getUpload(req.FileId, "")
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if rec.VersionOf != "" {
+		if rec, err = s.db.getUpload(rec.VersionOf, ""); err != nil {
+			return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+		}
+	}
+
+	at, err := s.db.SetLegalHold(rec.FileID, req.Hold, req.Reason, c.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "file not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db update error: %v", err)
+	}
+
+	detail := "hold released"
+	if req.Hold {
+		detail = "hold placed: " + req.Reason
+	}
+	s.auditAs(ctx, c.UserID, "legal-hold", rec.FileID, "ok", detail)
+	log.Printf("SetLegalHold success: file_id=%s, hold=%t, by=%s", rec.FileID, req.Hold, c.UserID)
+
+	out := &pb.LegalHold{FileId: rec.FileID, Hold: req.Hold, SetBy: c.UserID, SetAt: at.Unix()}
+	if req.Hold {
+		out.Reason = req.Reason
+	}
+	return out, nil
+}
+
+// RunRetention periodically moves files past their maximum retention to the
+// trash, until ctx is cancelled
+func (s *UploadService) RunRetention(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		s.eachTenant(func(ts *UploadService) { ts.expireRetained(ctx) })
+	}
+}
+
+// expireRetained trashes the files of the service's tenant that are past
+// the maximum age of their retention policy
+func (s *UploadService) expireRetained(ctx context.Context) {
+	ids, err := s.db.ExpiredByRetention(maxTrashPageSize)
+	if err != nil {
+		log.Printf("Retention error: tenant_id=%s, error=%v", s.tenant.ID, err)
+		return
+	}
+	expired := 0
+	for _, id := range ids {
+		trashed, err := s.db.TrashUpload(id, retentionSystemActor)
+		if err != nil {
+			log.Printf("Retention error: file_id=%s, error=%v", id, err)
+			s.auditAs(ctx, retentionSystemActor, "delete", id, "error", err.Error())
+			continue
+		}
+		if trashed {
+			s.auditAs(ctx, retentionSystemActor, "delete", id, "ok", "maximum retention reached")
+			s.notifyWebhooks(webhookFileDeleted, webhookData{FileID: id, DeletedBy: retentionSystemActor})
+			expired++
+		}
+	}
+	if expired > 0 {
+		log.Printf("Retention success: tenant_id=%s, expired=%d", s.tenant.ID, expired)
+	}
+}
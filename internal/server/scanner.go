@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrScanTooLarge is returned by a scanner that refuses content over its
+// size limit, such as clamd past StreamMaxLength
+var ErrScanTooLarge = errors.New("content exceeds the scanner's size limit")
+
+// ScanResult is the verdict of a malware scan
+type ScanResult struct {
+	Clean     bool
+	Signature string // name of the detected threat when not clean
+	Engine    string
+	Version   string // engine and signature database version
+	Skipped   bool   // too large to scan and let through, see SetSkipOversizeScans
+}
+
+// Scanner checks a completed file for malware before it becomes downloadable
+type Scanner interface {
+	// Scan reads the plaintext content of the named file from r
+	Scan(ctx context.Context, name string, r io.Reader) (*ScanResult, error)
+}
+
+// NewScanner builds a scanner from its configured kind: "clamd", "stub" or
+// "" for no scanning
+func NewScanner(kind, clamdAddr string) (Scanner, error) {
+	switch kind {
+	case "":
+		return nil, nil
+	case "clamd":
+		return NewClamdScanner(clamdAddr, 5*time.Minute), nil
+	case "stub":
+		return &StubScanner{}, nil
+	default:
+		return nil, fmt.Errorf("unknown scanner %q", kind)
+	}
+}
+
+// eicarSignature is the industry-standard antivirus test string
+var eicarSignature = []byte(`X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`)
+
+// StubScanner is a local scanner for tests and development. It reports the
+// EICAR test string and any file whose name is listed in Infected.
+type StubScanner struct {
+	Infected []string
+}
+
+// Scan implements Scanner
+func (s *StubScanner) Scan(ctx context.Context, name string, r io.Reader) (*ScanResult, error) {
+	result := &ScanResult{Clean: true, Engine: "stub", Version: "1"}
+	for _, infected := range s.Infected {
+		if filepath.Base(name) == infected {
+			result.Clean = false
+			result.Signature = "Stub.Test.Infected"
+			return result, nil
+		}
+	}
+
+	// Scan in overlapping windows so the signature is found across reads
+	buf := make([]byte, 64*1024)
+	var tail []byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			window := append(tail, buf[:n]...)
+			if bytes.Contains(window, eicarSignature) {
+				result.Clean = false
+				result.Signature = "Eicar-Test-Signature"
+				return result, nil
+			}
+			if len(window) > len(eicarSignature) {
+				tail = append([]byte(nil), window[len(window)-len(eicarSignature):]...)
+			} else {
+				tail = window
+			}
+		}
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// scanFailure maps a scan error to the reason recorded on the failed upload
+// and the status returned to the client
+func scanFailure(err error) (string, error) {
+	if errors.Is(err, ErrScanTooLarge) {
+		return "file is too large to scan", status.Error(codes.FailedPrecondition, "file is too large to scan")
+	}
+	return "malware scan failed", status.Errorf(codes.Internal, "malware scan failed: %v", err)
+}
+
+// quarantine moves an infected file out of the download area
+func (s *UploadService) quarantine(path string) (string, error) {
+	dir := filepath.Join(s.tempDir, "quarantine")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(dir, filepath.Base(path))
+	if err := os.Rename(path, dest); err != nil {
+		return "", err
+	}
+	return dest, os.Chmod(dest, 0600)
+}
+
+// scanUpload runs the configured scanner on a merged file and records the
+// verdict in Postgres. It returns nil when no scanner is configured.
+func (s *UploadService) scanUpload(ctx context.Context, fileID, mergedPath string, key []byte) (*ScanResult, error) {
+	if s.scanner == nil {
+		return nil, nil
+	}
+
+	sf, err := openStored(mergedPath, key, fileStreamID)
+	if err != nil {
+		return nil, err
+	}
+	defer sf.Close()
+	return s.scanContent(ctx, fileID, mergedPath, sf.Reader())
+}
+
+// scanContent scans the plaintext of an upload and records the verdict. It
+// returns nil when no scanner is configured.
+func (s *UploadService) scanContent(ctx context.Context, fileID, name string, r io.Reader) (*ScanResult, error) {
+	if s.scanner == nil {
+		return nil, nil
+	}
+
+	result, err := s.scanner.Scan(ctx, name, r)
+	if errors.Is(err, ErrScanTooLarge) {
+		if !s.skipOversizeScans {
+			s.db.RecordScan(fileID, "too_large", "", "", "")
+			return nil, err
+		}
+		if err := s.db.RecordScan(fileID, "skipped", "", "", ""); err != nil {
+			return nil, err
+		}
+		return &ScanResult{Clean: true, Skipped: true}, nil
+	}
+	if err != nil {
+		s.db.RecordScan(fileID, "error", err.Error(), "", "")
+		return nil, err
+	}
+
+	verdict := "clean"
+	if !result.Clean {
+		verdict = "infected"
+	}
+	if err := s.db.RecordScan(fileID, verdict, result.Signature, result.Engine, result.Version); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/internal/textextract"
+	"upload-backend/pb"
+)
+
+// Bounds on text extraction and search results
+const (
+	maxExtractedText         = 1 << 20 // bytes of text indexed per file
+	maxConcurrentExtractions = 2
+	maxSearchQueryLen        = 256
+	defaultSearchPageSize    = 20
+	maxSearchPageSize        = 100
+)
+
+// searchHeadline formats snippets; matches are wrapped in <mark></mark>
+const searchHeadline = `StartSel=<mark>, StopSel=</mark>, MaxWords=24, MinWords=8, MaxFragments=2, FragmentDelimiter=" … "`
+
+// SearchHit is a file matching a search
+type SearchHit struct {
+	FileEntry
+	Rank    float32
+	Shared  bool
+	Snippet string
+}
+
+// SetFileText stores the text extracted from the content of a file
+func (db *UploadDB) SetFileText(fileID, text string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO file_text (file_id, content) VALUES ($1, $2)
+		 ON CONFLICT (file_id) DO UPDATE SET content = EXCLUDED.content, extracted_at = now()`,
+		fileID, text,
+	)
+	return err
+}
+
+// SearchFiles ranks the live files userID owns or can read through a grant
+// against a web search style query. Names, tags and metadata match by exact
+// words; the text of the current version matches by English word stems.
+func (db *UploadDB) SearchFiles(userID string, groups []string, query string, ownedOnly bool, filter FileFilter, offset, limit int) ([]*SearchHit, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`WITH q AS (SELECT websearch_to_tsquery('simple', $1) AS qs, websearch_to_tsquery('english', $1) AS qe),
+		 hits AS (
+		   SELECT r.file_id AS root_id, r.user_id::text <> $2 AS shared,
+		          ts_rank(r.search_vector, q.qs) + ts_rank(COALESCE(ft.tsv, ''::tsvector), q.qe) AS rank,
+		          COALESCE(ft.tsv @@ q.qe, false) AS in_content
+		   FROM q, `+fileEntryFrom+` LEFT JOIN file_text ft ON ft.file_id = cur.file_id
+		   WHERE r.version_of IS NULL AND r.deleted_at IS NULL
+		     AND (r.search_vector @@ q.qs OR ft.tsv @@ q.qe)
+		     AND (r.user_id::text = $2 OR (NOT $4 AND cur.file_id IS NOT NULL AND EXISTS (
+		           SELECT 1 FROM file_grants g
+		           WHERE g.file_id = r.file_id AND $5 = ANY(g.permissions)
+		             AND (g.expires_at IS NULL OR g.expires_at > now())
+		             AND ((g.grantee_type = 'user' AND g.grantee_id = $2)
+		               OR (g.grantee_type = 'group' AND g.grantee_id = ANY($3))))))
+		     AND `+filter.sql("$8", "$9")+`
+		   ORDER BY rank DESC, r.created_at DESC
+		   OFFSET $6 LIMIT $7)
+		 SELECT `+fileEntryColumns+`, hits.rank, hits.shared,
+		        CASE WHEN hits.in_content THEN ts_headline('english', ft.content, q.qe, $10)
+		             ELSE ts_headline('simple', concat_ws(' ', r.file_name, array_to_string(r.tags, ' '),
+		                    (SELECT string_agg(value, ' ') FROM jsonb_each_text(r.metadata))), q.qs, $10) END
+		 FROM q, hits JOIN (`+fileEntryFrom+`) ON r.file_id = hits.root_id
+		      LEFT JOIN file_text ft ON ft.file_id = cur.file_id
+		 ORDER BY hits.rank DESC, r.created_at DESC`,
+		query, userID, groups, ownedOnly, permRead, offset, limit, filter.metadata(), filter.tags(), searchHeadline,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hits []*SearchHit
+	for rows.Next() {
+		var h SearchHit
+		if err := rows.Scan(&h.FileID, &h.FileName, &h.FolderID, &h.Size, &h.Status, &h.Version, &h.CreatedAt, &h.Metadata, &h.Tags,
+			&h.Rank, &h.Shared, &h.Snippet); err != nil {
+			return nil, err
+		}
+		hits = append(hits, &h)
+	}
+	return hits, rows.Err()
+}
+
+// indexText extracts the text of a completed upload in the background so
+// SearchFiles can match its content. Client-side encrypted content cannot
+// be read and is only found by name, tags and metadata.
+func (s *UploadService) indexText(fileID string) {
+	go func() {
+		s.extractSlots <- struct{}{}
+		defer func() { <-s.extractSlots }()
+
+		rec, err := s.db.GetUploadByID(fileID)
+		if err != nil || rec.Status != "completed" || rec.ClientEncScheme != "" {
+			return
+		}
+		content, err := s.openUpload(rec)
+		if err != nil {
+			log.Printf("Text extraction error: file_id=%s, error=%v", fileID, err)
+			return
+		}
+		defer content.Close()
+
+		text, err := textextract.Extract(rec.FileName, rec.MimeType, content, rec.Size, maxExtractedText)
+		if errors.Is(err, textextract.ErrUnsupported) {
+			return
+		}
+		if err != nil {
+			log.Printf("Text extraction error: file_id=%s, error=%v", fileID, err)
+			return
+		}
+		if strings.TrimSpace(text) == "" {
+			return
+		}
+		if err := s.db.SetFileText(fileID, text); err != nil {
+			log.Printf("Text extraction error: file_id=%s, error=%v", fileID, err)
+			return
+		}
+		log.Printf("Text extracted: file_id=%s, bytes=%d", fileID, len(text))
+	}()
+}
+
+// SearchFiles finds files by name, tags, metadata and content among the
+// caller's files and those shared with them for reading
+func (s *UploadService) SearchFiles(ctx context.Context, req *pb.SearchFilesRequest) (*pb.SearchFilesResponse, error) {
+	s = s.scoped(ctx)
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if len(query) > maxSearchQueryLen {
+		return nil, status.Errorf(codes.InvalidArgument, "query exceeds %d bytes", maxSearchQueryLen)
+	}
+	filter, err := newFileFilter(req.MetadataFilter, req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.PageSize)
+	if limit <= 0 {
+		limit = defaultSearchPageSize
+	}
+	if limit > maxSearchPageSize {
+		limit = maxSearchPageSize
+	}
+	offset := 0
+	if req.PageToken != "" {
+		if offset, err = strconv.Atoi(req.PageToken); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	hits, err := s.db.SearchFiles(c.UserID, c.Groups, query, req.OwnedOnly, filter, offset, limit)
+	if err != nil {
+		log.Printf("SearchFiles error: user_id=%s, error=%v", c.UserID, err)
+		return nil, status.Errorf(codes.Internal, "search error: %v", err)
+	}
+	resp := &pb.SearchFilesResponse{}
+	for _, h := range hits {
+		resp.Results = append(resp.Results, &pb.SearchResult{
+			File:    fileEntryToPB(&h.FileEntry),
+			Rank:    h.Rank,
+			Snippet: h.Snippet,
+			Shared:  h.Shared,
+		})
+	}
+	if len(hits) == limit {
+		resp.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	return resp, nil
+}
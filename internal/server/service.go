@@ -2,16 +2,23 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"upload-backend/pb"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"upload-backend/pb"
 )
 
 // UploadService implements the gRPC server
@@ -20,6 +27,25 @@ type UploadService struct {
 	rdb     *redis.Client
 	db      *UploadDB
 	tempDir string
+	policy  *ContentPolicy
+	scanner Scanner
+	keys    KeyWrapper
+
+	skipOversizeScans bool
+
+	versionRetention int
+	trashRetention   time.Duration
+	extractSlots     chan struct{}
+	unpackSlots      chan struct{}
+	importer         *urlImporter
+	events           *eventHub
+	webhooks         *webhookSender
+	trustedProxies   []*net.IPNet
+
+	// Requests run on a copy scoped to their tenant, see forTenant
+	tenant  *Tenant
+	system  *UploadService
+	tenants *tenantCache
 }
 
 // NewUploadService creates a new UploadService
@@ -27,20 +53,234 @@ func NewUploadService(redisAddr, tempDir string, db *UploadDB) *UploadService {
 	rdb := redis.NewClient(&redis.Options{
 		Addr: redisAddr,
 	})
-	return &UploadService{rdb: rdb, db: db, tempDir: tempDir}
+	return &UploadService{
+		rdb:          rdb,
+		db:           db,
+		tempDir:      tempDir,
+		extractSlots: make(chan struct{}, maxConcurrentExtractions),
+		unpackSlots:  make(chan struct{}, maxConcurrentUnpacks),
+		importer:     newURLImporter(ImportPolicy{}),
+		events:       newEventHub(rdb),
+		webhooks:     newWebhookSender(WebhookPolicy{}),
+		tenants:      &tenantCache{entries: map[string]tenantCacheEntry{}},
+	}
+}
+
+// SetKeyWrapper enables encryption at rest for new uploads; nil stores new files in plaintext
+func (s *UploadService) SetKeyWrapper(kw KeyWrapper) {
+	s.keys = kw
+}
+
+// SetScanner configures the malware scanner run after merge; nil disables scanning
+func (s *UploadService) SetScanner(scanner Scanner) {
+	s.scanner = scanner
+}
+
+// SetSkipOversizeScans lets files the scanner refuses as too large complete
+// unscanned instead of failing them
+func (s *UploadService) SetSkipOversizeScans(skip bool) {
+	s.skipOversizeScans = skip
+}
+
+// SetContentPolicy configures the allowed file types for uploads
+func (s *UploadService) SetContentPolicy(policy *ContentPolicy) {
+	s.policy = policy
 }
 
 // InitUpload generates server-owned file ID and initializes upload
 func (s *UploadService) InitUpload(ctx context.Context, req *pb.InitRequest) (*pb.InitResponse, error) {
+	s = s.scoped(ctx)
+	// A valid JWT overrides the client-supplied user ID; minting an upload token requires one
+	userID := req.UserId
+	if jwtUser, err := s.validateJWT(ctx); err == nil {
+		userID = jwtUser
+	} else if req.IssueUploadToken {
+		return nil, err
+	}
+
+	ttl := defaultUploadTokenTTL
+	if req.IssueUploadToken {
+		if req.UploadTokenTtlSeconds > 0 {
+			ttl = time.Duration(req.UploadTokenTtlSeconds) * time.Second
+		}
+		if ttl > maxUploadTokenTTL {
+			return nil, status.Errorf(codes.InvalidArgument, "upload token ttl exceeds maximum of %s", maxUploadTokenTTL)
+		}
+		if req.MaxSize < 0 {
+			return nil, status.Error(codes.InvalidArgument, "max_size must not be negative")
+		}
+	}
+	if err := validateClientEncryption(req); err != nil {
+		return nil, err
+	}
+	if req.Sha256 != "" && (!validSHA256(req.Sha256) || req.Size < 0) {
+		return nil, status.Error(codes.InvalidArgument, "sha256 must be 64 lowercase hex characters with a non-negative size")
+	}
+	// The declared size is checked now; the actual size while chunks arrive
+	if err := s.checkQuota(req.Size); err != nil {
+		return nil, err
+	}
+
 	id := uuid.NewString()
 	safe := sanitizeFilename(filepath.Base(req.FileName))
-	if err := s.db.CreateUpload(id, req.UserId, safe, req.TotalChunks); err != nil {
-		log.Printf("InitUpload error: user_id=%s, file_id=%s, error=%v", req.UserId, id, err)
+	rules := s.policy.rulesFor(userID)
+	nameCheck := rules.checkName
+	if req.EncryptionScheme != "" && rules.clientEncryptionAllowed() {
+		nameCheck = rules.checkEncrypted
+	}
+	if err := nameCheck(safe); err != nil {
+		log.Printf("InitUpload rejected: user_id=%s, file_name=%s, error=%v", userID, safe, err)
+		return nil, err
+	}
+	rec := &UploadRecord{
+		FileID:           id,
+		UserID:           userID,
+		FileName:         safe,
+		ClientEncScheme:  req.EncryptionScheme,
+		ClientWrappedKey: req.WrappedKey,
+		ClientKeyID:      req.KeyId,
+	}
+
+	// Uploading a new version of an existing file needs write access to it
+	if req.TargetFileId != "" {
+		target, err := s.db.GetUploadByID(req.TargetFileId)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "target file not found: %v", err)
+		}
+		c, err := s.authorize(ctx, target, permWrite)
+		if err != nil {
+			return nil, err
+		}
+		if c == nil {
+			return nil, status.Error(codes.PermissionDenied, "uploading a new version requires a signed-in user")
+		}
+		root, err := s.rootOf(target)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "target file not found: %v", err)
+		}
+		rec.VersionOf = root.FileID
+	}
+
+	// Metadata and tags belong to the file, so they are only accepted for new files
+	if len(req.Metadata) > 0 || len(req.Tags) > 0 {
+		if rec.VersionOf != "" {
+			return nil, status.Error(codes.InvalidArgument, "set metadata of an existing file with UpdateFileMetadata")
+		}
+		if err := checkMetadata(req.Metadata); err != nil {
+			return nil, err
+		}
+		tags, err := normalizeTags(req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		rec.Metadata = req.Metadata
+		rec.Tags = tags
+	}
+
+	// Place new files into the caller's folder tree; versions stay with their file
+	if req.FolderId != "" || req.FolderPath != "" {
+		if rec.VersionOf != "" {
+			return nil, status.Error(codes.InvalidArgument, "a new version cannot be placed in a folder")
+		}
+		c, err := s.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		folder, err := s.resolveFolder(c.UserID, req.FolderId, req.FolderPath)
+		if err != nil {
+			return nil, err
+		}
+		if folder != nil {
+			rec.FolderID = folder.ID
+		}
+	}
+
+	// Extracted members become files of the caller, who must be signed in
+	if req.Extract {
+		if rec.VersionOf != "" {
+			return nil, status.Error(codes.InvalidArgument, "only new files can be extracted")
+		}
+		if req.EncryptionScheme != "" {
+			return nil, status.Error(codes.InvalidArgument, "client-side encrypted archives cannot be extracted")
+		}
+		if _, err := s.authenticate(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Skip the transfer entirely when the caller can already read identical content
+	if req.Sha256 != "" && req.EncryptionScheme == "" && !req.IssueUploadToken {
+		if c, err := s.authenticate(ctx); err == nil {
+			done, err := s.initFromBlob(c, rec, req.Sha256, req.Size, req.TotalChunks)
+			if err != nil {
+				log.Printf("InitUpload dedup error: user_id=%s, sha256=%s, error=%v", userID, req.Sha256, err)
+				return nil, err
+			}
+			if done {
+				if req.Extract {
+					if err := s.db.CreateExtraction(id); err != nil {
+						return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+					}
+				}
+				s.versionCompleted(ctx, rec)
+				log.Printf("InitUpload success: user_id=%s, file_id=%s, file_name=%s, sha256=%s, already_uploaded=true", userID, id, safe, req.Sha256)
+				return &pb.InitResponse{FileId: id, AlreadyUploaded: true, Version: rec.Version}, nil
+			}
+		}
+	}
+
+	if s.keys != nil {
+		var err error
+		if rec.KeyID, rec.WrappedKey, err = newDataKey(s.keys); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create data key: %v", err)
+		}
+	}
+
+	if err := s.db.CreateUpload(rec, req.TotalChunks); err != nil {
+		log.Printf("InitUpload error: user_id=%s, file_id=%s, error=%v", userID, id, err)
 		return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
 	}
+	if req.Extract {
+		if err := s.db.CreateExtraction(id); err != nil {
+			log.Printf("InitUpload error: user_id=%s, file_id=%s, error=%v", userID, id, err)
+			return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+		}
+	}
+
+	resp := &pb.InitResponse{FileId: id, Version: rec.Version}
+	if req.IssueUploadToken {
+		token, expiresAt, err := issueUploadToken(id, userID, req.MaxSize, req.AllowedMimeTypes, ttl)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to sign upload token: %v", err)
+		}
+		resp.UploadToken = token
+		resp.UploadTokenExpiresAt = expiresAt.Unix()
+	}
 
-	log.Printf("InitUpload success: user_id=%s, file_id=%s, file_name=%s, total_chunks=%d", req.UserId, id, safe, req.TotalChunks)
-	return &pb.InitResponse{FileId: id}, nil
+	log.Printf("InitUpload success: user_id=%s, file_id=%s, file_name=%s, total_chunks=%d, upload_token=%t, version=%d", userID, id, safe, req.TotalChunks, req.IssueUploadToken, rec.Version)
+	return resp, nil
+}
+
+// validateClientEncryption checks the opaque client-side encryption fields of
+// InitUpload. The content type of encrypted uploads cannot be verified, so
+// they cannot be combined with MIME-restricted upload tokens.
+func validateClientEncryption(req *pb.InitRequest) error {
+	if req.EncryptionScheme == "" {
+		if len(req.WrappedKey) > 0 || req.KeyId != "" {
+			return status.Error(codes.InvalidArgument, "wrapped_key requires encryption_scheme")
+		}
+		return nil
+	}
+	if len(req.EncryptionScheme) > 64 || len(req.KeyId) > 128 {
+		return status.Error(codes.InvalidArgument, "encryption metadata too long")
+	}
+	if len(req.WrappedKey) == 0 || len(req.WrappedKey) > 1024 {
+		return status.Error(codes.InvalidArgument, "encrypted uploads need a wrapped_key of at most 1024 bytes")
+	}
+	if req.IssueUploadToken && len(req.AllowedMimeTypes) > 0 {
+		return status.Error(codes.InvalidArgument, "allowed_mime_types cannot be enforced for client-side encrypted uploads")
+	}
+	return nil
 }
 
 // UploadFile handles streaming upload chunks from client
@@ -55,6 +295,9 @@ func (s *UploadService) UploadFile(stream pb.FileUploadService_UploadFileServer)
 	if err != nil {
 		return status.Errorf(codes.Internal, "stream recv: %v", err)
 	}
+	// Streams without a JWT or upload token get their tenant from the first chunk
+	ctx = stream.Context()
+	s = s.scoped(ctx)
 
 	fileID := firstChunk.FileId
 	totalChunks := firstChunk.TotalChunks
@@ -66,13 +309,40 @@ func (s *UploadService) UploadFile(stream pb.FileUploadService_UploadFileServer)
 		return status.Errorf(codes.NotFound, "invalid file_id: %v", err)
 	}
 
+	scope, err := s.authorizeUpload(ctx, rec)
+	if err != nil {
+		log.Printf("UploadFile unauthorized: file_id=%s, error=%v", fileID, err)
+		return err
+	}
+	if rec.Status == "failed" {
+		return status.Error(codes.FailedPrecondition, "upload was rejected; start a new upload")
+	}
+	if rec.Status != "in_progress" {
+		return status.Errorf(codes.FailedPrecondition, "upload is already %s", rec.Status)
+	}
+
+	key, err := s.dataKey(rec)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to unwrap data key: %v", err)
+	}
+
 	tmpDir, _, _ := paths(s.tempDir, fileID, rec.FileName)
 	if err := os.MkdirAll(tmpDir, 0755); err != nil {
 		return status.Errorf(codes.Internal, "failed to create temp dir: %v", err)
 	}
 
+	// The token's and the tenant's size limits apply to the upload as a
+	// whole, across resumed and concurrent streams; saveChunk enforces them
+	maxSize := scope.maxSize()
+	if fs := s.maxFileSize(); fs > 0 && (maxSize == 0 || fs < maxSize) {
+		maxSize = fs
+	}
+	if err := scope.checkChunk(firstChunk); err != nil {
+		return err
+	}
+
 	// Save first chunk
-	if err := s.saveChunk(ctx, tmpDir, fileID, firstChunk, totalChunks); err != nil {
+	if err := s.saveChunk(ctx, tmpDir, rec, key, firstChunk, totalChunks, maxSize); err != nil {
 		return err
 	}
 
@@ -92,38 +362,119 @@ func (s *UploadService) UploadFile(stream pb.FileUploadService_UploadFileServer)
 			return status.Errorf(codes.Internal, "recv chunk error: %v", err)
 		}
 
-		if err := s.saveChunk(ctx, tmpDir, fileID, chunk, totalChunks); err != nil {
+		if err := scope.checkChunk(chunk); err != nil {
+			return err
+		}
+
+		if err := s.saveChunk(ctx, tmpDir, rec, key, chunk, totalChunks, maxSize); err != nil {
 			return err
 		}
 	}
 
-	// Merge chunks
-	mergedPath, err := s.mergeChunks(fileID, rec.FileName, totalChunks)
+	blob, result, err := s.finishUpload(ctx, rec, totalChunks, key, "", "")
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to merge chunks: %v", err)
+		return err
 	}
-
-	// Mark upload completed in DB
-	if err := s.db.CompleteUpload(fileID, mergedPath); err != nil {
-		return status.Errorf(codes.Internal, "failed to update upload status: %v", err)
+	if result != nil && !result.Clean {
+		log.Printf("UploadFile quarantined: file_id=%s, signature=%s, engine=%s", fileID, result.Signature, result.Engine)
+		return stream.SendAndClose(&pb.UploadStatus{
+			Success: false,
+			Message: "file quarantined: " + result.Signature,
+		})
 	}
 
-	// Cleanup Redis and temp files
-	cleanupChunks(ctx, s.rdb, fileID)
-	os.RemoveAll(tmpDir)
-
-	log.Printf("UploadFile success: file_id=%s, stored_path=%s", fileID, mergedPath)
+	log.Printf("UploadFile success: file_id=%s, sha256=%s, stored_path=%s", fileID, blob.SHA256, blob.StoredPath)
 
 	// Return success
 	return stream.SendAndClose(&pb.UploadStatus{
 		Success:    true,
 		Message:    "upload saved",
-		StoredPath: mergedPath,
+		StoredPath: blob.StoredPath,
 	})
 }
 
-// saveChunk saves a chunk to disk and marks it in Redis with validation
-func (s *UploadService) saveChunk(ctx context.Context, tmpDir, fileID string, chunk *pb.FileChunk, totalChunks int64) error {
+// finishUpload merges the received chunks of rec, scans the result and
+// completes the upload, or quarantines it when the scanner flags it. With
+// wantSHA256 set, content with a different hash fails the upload. The scan
+// result is nil when no scanner is configured. A completed or quarantined
+// upload is audited with detail; failures are left to the caller.
+func (s *UploadService) finishUpload(ctx context.Context, rec *UploadRecord, totalChunks int64, key []byte, wantSHA256, detail string) (*Blob, *ScanResult, error) {
+	fileID := rec.FileID
+	tmpDir, _, _ := paths(s.tempDir, fileID, rec.FileName)
+
+	// Merge chunks
+	s.mergeStarted(fileID, totalChunks)
+	mergedPath, sum, size, err := s.mergeChunks(fileID, rec.FileName, totalChunks, key)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "failed to merge chunks: %v", err)
+	}
+	if wantSHA256 != "" && sum != wantSHA256 {
+		s.failUpload(fileID, "content does not match the expected sha256")
+		os.Remove(mergedPath)
+		cleanupChunks(ctx, s.rdb, fileID)
+		os.RemoveAll(tmpDir)
+		return nil, nil, status.Errorf(codes.InvalidArgument, "content has sha256 %s, expected %s", sum, wantSHA256)
+	}
+
+	// Scan before the file becomes downloadable
+	result, err := s.scanUpload(ctx, fileID, mergedPath, key)
+	if err != nil {
+		log.Printf("Upload scan error: file_id=%s, error=%v", fileID, err)
+		reason, err := scanFailure(err)
+		s.failUpload(fileID, reason)
+		os.Remove(mergedPath)
+		return nil, nil, err
+	}
+	if result != nil && !result.Clean {
+		quarantined, err := s.quarantine(mergedPath)
+		if err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "failed to quarantine file: %v", err)
+		}
+		if err := s.db.QuarantineUpload(fileID, quarantined); err != nil {
+			return nil, nil, status.Errorf(codes.Internal, "failed to update upload status: %v", err)
+		}
+		s.uploadQuarantined(fileID, result.Signature)
+		cleanupChunks(ctx, s.rdb, fileID)
+		os.RemoveAll(tmpDir)
+		if detail != "" {
+			detail += ", "
+		}
+		s.auditCompleted(ctx, rec, detail+"quarantined: "+result.Signature)
+		return nil, result, nil
+	}
+
+	if err := s.checkQuota(size); err != nil {
+		s.failUpload(fileID, status.Convert(err).Message())
+		os.Remove(mergedPath)
+		cleanupChunks(ctx, s.rdb, fileID)
+		os.RemoveAll(tmpDir)
+		return nil, nil, err
+	}
+
+	// Store the content once by hash and mark the upload completed
+	blob, err := s.db.CommitBlob(rec, mergedPath, s.tempDir, sum, size)
+	if err != nil {
+		os.Remove(mergedPath)
+		return nil, nil, status.Errorf(codes.Internal, "failed to update upload status: %v", err)
+	}
+
+	// Cleanup Redis and temp files
+	cleanupChunks(ctx, s.rdb, fileID)
+	os.RemoveAll(tmpDir)
+	s.auditCompleted(ctx, rec, detail)
+	s.versionCompleted(ctx, rec)
+	return blob, result, nil
+}
+
+// saveChunk saves a chunk to disk and marks it in Redis with validation. The
+// chunk's plaintext size is reserved first, refusing it if the upload would
+// exceed maxSize bytes (0 for no limit). The chunk is audited with the outcome.
+func (s *UploadService) saveChunk(ctx context.Context, tmpDir string, rec *UploadRecord, key []byte, chunk *pb.FileChunk, totalChunks, maxSize int64) (err error) {
+	fileID := rec.FileID
+	defer func() {
+		s.audit(ctx, "chunk", fileID, auditResult(err), "index="+strconv.FormatInt(chunk.ChunkIndex, 10))
+	}()
+
 	// Validate chunk index
 	if chunk.ChunkIndex < 0 || chunk.ChunkIndex >= totalChunks {
 		return status.Errorf(codes.InvalidArgument, "invalid chunk index %d", chunk.ChunkIndex)
@@ -137,20 +488,68 @@ func (s *UploadService) saveChunk(ctx context.Context, tmpDir, fileID string, ch
 		}
 	}
 
+	// Sniff the first chunk's magic bytes before accepting the rest of the upload.
+	// Client-side encrypted content is opaque, so where the policy allows it
+	// only the rules that need no plaintext are applied.
+	if chunk.ChunkIndex == 0 {
+		rules := s.policy.rulesFor(rec.UserID)
+		var detected string
+		var err error
+		if rec.ClientEncScheme != "" && rules.clientEncryptionAllowed() {
+			err = rules.checkEncrypted(rec.FileName)
+		} else {
+			detected, err = rules.checkContent(rec.FileName, chunk.Content)
+		}
+		if err != nil {
+			log.Printf("UploadFile content rejected: file_id=%s, file_name=%s, detected=%s, error=%v", fileID, rec.FileName, detected, err)
+			s.failUpload(fileID, status.Convert(err).Message())
+			cleanupChunks(ctx, s.rdb, fileID)
+			os.RemoveAll(tmpDir)
+			return err
+		}
+		if detected != "" {
+			if err := s.db.SetMimeType(fileID, detected); err != nil {
+				log.Printf("UploadFile mime_type update error: file_id=%s, error=%v", fileID, err)
+			}
+		}
+	}
+
+	_, err = reserveChunk(ctx, s.rdb, fileID, chunk.ChunkIndex, int64(len(chunk.Content)), maxSize)
+	if errors.Is(err, errChunkLimit) {
+		return status.Errorf(codes.ResourceExhausted, "upload exceeds maximum size of %d bytes", maxSize)
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "redis error: %v", err)
+	}
+	stored := false
+	defer func() {
+		if !stored {
+			releaseChunk(ctx, s.rdb, fileID, chunk.ChunkIndex)
+		}
+	}()
+
 	chunkPath := filepath.Join(tmpDir, fmt.Sprintf("chunk_%d", chunk.ChunkIndex))
-	if err := os.WriteFile(chunkPath, chunk.Content, 0644); err != nil {
+	if err := writeStored(chunkPath, chunk.Content, key, chunkStreamID(chunk.ChunkIndex)); err != nil {
 		return status.Errorf(codes.Internal, "write chunk error: %v", err)
 	}
 
 	if err := markChunk(ctx, s.rdb, fileID, chunk.ChunkIndex); err != nil {
 		return status.Errorf(codes.Internal, "redis set error: %v", err)
 	}
+	stored = true
+	chunks, received, err := receivedBytes(ctx, s.rdb, fileID)
+	if err != nil {
+		log.Printf("UploadFile progress error: file_id=%s, error=%v", fileID, err)
+	} else {
+		s.chunkReceived(fileID, chunk.ChunkIndex, chunks, totalChunks, received, 0)
+	}
 
 	return nil
 }
 
 // GetUploadedChunks returns list of uploaded chunk indices from Redis
 func (s *UploadService) GetUploadedChunks(ctx context.Context, req *pb.GetChunksRequest) (*pb.GetChunksResponse, error) {
+	s = s.scoped(ctx)
 	set, err := listedChunks(ctx, s.rdb, req.FileId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "redis error: %v", err)
@@ -166,62 +565,99 @@ func (s *UploadService) GetUploadedChunks(ctx context.Context, req *pb.GetChunks
 	}, nil
 }
 
-// mergeChunks joins all chunk files into the final file with validation
-func (s *UploadService) mergeChunks(fileID, fileName string, totalChunks int64) (string, error) {
+// mergeChunks joins all chunk files into the final file with validation and
+// returns the SHA-256 and size of the plaintext. With a data key, chunks are
+// decrypted and the file is re-encrypted as one stream.
+func (s *UploadService) mergeChunks(fileID, fileName string, totalChunks int64, key []byte) (string, string, int64, error) {
 	tmpDir, finalPath, tempFinal := paths(s.tempDir, fileID, fileName)
 
 	// Ensure all chunks exist before merging
 	for i := int64(0); i < totalChunks; i++ {
 		p := filepath.Join(tmpDir, fmt.Sprintf("chunk_%d", i))
 		if _, err := os.Stat(p); err != nil {
-			return "", fmt.Errorf("missing chunk %d", i)
+			return "", "", 0, fmt.Errorf("missing chunk %d", i)
 		}
 	}
 
 	// Create final directory
 	if err := os.MkdirAll(filepath.Dir(finalPath), 0755); err != nil {
-		return "", err
+		return "", "", 0, err
 	}
 
 	// Write to temp file first (atomic)
 	out, err := os.Create(tempFinal)
 	if err != nil {
-		return "", err
+		return "", "", 0, err
 	}
 	defer out.Close()
 
+	hash := sha256.New()
+	var w io.Writer = out
+	var sw *segmentWriter
+	if key != nil {
+		if sw, err = newSegmentWriter(out, key, fileStreamID); err != nil {
+			return "", "", 0, err
+		}
+		w = sw
+	}
+
 	// Merge chunks in order
+	var size int64
 	for i := int64(0); i < totalChunks; i++ {
-		f, err := os.Open(filepath.Join(tmpDir, fmt.Sprintf("chunk_%d", i)))
+		f, err := openStored(filepath.Join(tmpDir, fmt.Sprintf("chunk_%d", i)), key, chunkStreamID(i))
 		if err != nil {
-			return "", err
+			return "", "", 0, err
 		}
-		io.Copy(out, f)
+		n, err := io.Copy(io.MultiWriter(w, hash), f.Reader())
 		f.Close()
+		size += n
+		if err != nil {
+			return "", "", 0, fmt.Errorf("merge chunk %d: %w", i, err)
+		}
 	}
 
-	out.Close()
+	if sw != nil {
+		if err := sw.Close(); err != nil {
+			return "", "", 0, err
+		}
+	}
+	if err := out.Close(); err != nil {
+		return "", "", 0, err
+	}
 	// Atomic rename
 	if err := os.Rename(tempFinal, finalPath); err != nil {
-		return "", err
+		return "", "", 0, err
 	}
 
-	return finalPath, nil
+	return finalPath, hex.EncodeToString(hash.Sum(nil)), size, nil
 }
 
 func (s *UploadService) DownloadFile(ctx context.Context, req *pb.DownloadRequest) (*pb.DownloadResponse, error) {
+	s = s.scoped(ctx)
 	fileID := req.FileId
-	var filePath, fileName string
 
 	// Query metadata from DB
-	err := s.db.pool.QueryRow(ctx, `SELECT stored_path, file_name FROM uploads WHERE file_id=$1`, fileID).Scan(&filePath, &fileName)
+	rec, err := s.db.GetUploadByID(fileID)
 	if err != nil {
 		log.Printf("DownloadFile not found: file_id=%s, error=%v", fileID, err)
 		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
 	}
+	if _, err := s.authorize(ctx, rec, permRead); err != nil {
+		log.Printf("DownloadFile denied: file_id=%s, error=%v", fileID, err)
+		return nil, err
+	}
+	if rec, err = s.resolveVersion(rec, req.Version); err != nil {
+		return nil, err
+	}
+	if rec.Status == "quarantined" {
+		return nil, status.Error(codes.FailedPrecondition, "file is quarantined")
+	}
+	if rec.Status != "completed" {
+		return nil, status.Error(codes.FailedPrecondition, "upload is not completed")
+	}
 
 	// Read file
-	data, err := os.ReadFile(filePath)
+	data, err := s.readStoredFile(rec)
 	if err != nil {
 		log.Printf("DownloadFile read error: file_id=%s, error=%v", fileID, err)
 		return nil, status.Errorf(codes.Internal, "failed to read file: %v", err)
@@ -229,45 +665,53 @@ func (s *UploadService) DownloadFile(ctx context.Context, req *pb.DownloadReques
 
 	log.Printf("DownloadFile success: file_id=%s, size=%d", fileID, len(data))
 	return &pb.DownloadResponse{
-		Content:  data,
-		FileName: fileName,
+		Content:          data,
+		FileName:         rec.FileName,
+		EncryptionScheme: rec.ClientEncScheme,
+		WrappedKey:       rec.ClientWrappedKey,
+		KeyId:            rec.ClientKeyID,
+		Version:          rec.Version,
+		VersionId:        contentID(rec),
 	}, nil
 }
 
 // GetUploadMetadata returns file metadata from PostgreSQL and uploaded chunk indices from Redis
 func (s *UploadService) GetUploadMetadata(ctx context.Context, req *pb.GetMetadataRequest) (*pb.UploadMetadata, error) {
+	s = s.scoped(ctx)
 	// Fetch DB record
 	rec, err := s.db.GetUploadByID(req.FileId)
 	if err != nil {
 		log.Printf("GetUploadMetadata not found: file_id=%s, error=%v", req.FileId, err)
 		return nil, status.Errorf(codes.NotFound, "upload not found: %v", err)
 	}
+	if _, err := s.authorize(ctx, rec, permRead); err != nil {
+		return nil, err
+	}
+	// A file's first ID describes its current version
+	root, err := s.rootOf(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "upload not found: %v", err)
+	}
+	if rec, err = s.currentVersion(rec); err != nil {
+		return nil, status.Errorf(codes.Internal, "version lookup error: %v", err)
+	}
 
 	// Determine size
 	var size int64
-	if rec.Status == "completed" && rec.StoredPath != "" {
-		if fi, err := os.Stat(rec.StoredPath); err == nil {
-			size = fi.Size()
+	if rec.Status == "completed" && (rec.StoredPath != "" || rec.Manifest) {
+		if content, err := s.openUpload(rec); err == nil {
+			size = content.Size()
+			content.Close()
 		}
 	} else {
-		// Sum sizes of chunk files if present
-		tmpDir, _, _ := paths(s.tempDir, rec.FileID, rec.FileName)
-		entries, err := os.ReadDir(tmpDir)
-		if err == nil {
-			for _, entry := range entries {
-				if entry.IsDir() {
-					continue
-				}
-				fp := filepath.Join(tmpDir, entry.Name())
-				if fi, err := os.Stat(fp); err == nil {
-					size += fi.Size()
-				}
-			}
+		// Plaintext bytes of the chunks received so far
+		if _, size, err = receivedBytes(ctx, s.rdb, rec.FileID); err != nil {
+			return nil, status.Errorf(codes.Internal, "redis error: %v", err)
 		}
 	}
 
 	// Get uploaded chunks from Redis using sets
-	set, err := listedChunks(ctx, s.rdb, req.FileId)
+	set, err := listedChunks(ctx, s.rdb, rec.FileID)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "redis error: %v", err)
 	}
@@ -276,17 +720,38 @@ func (s *UploadService) GetUploadMetadata(ctx context.Context, req *pb.GetMetada
 		chunks = append(chunks, chunk)
 	}
 
+	// Retention is reported for the file as a whole
+	var legalHold bool
+	var retainUntil int64
+	if st, err := s.db.RetentionState(root.FileID); err == nil {
+		legalHold = st.LegalHold
+		if t := st.RetainUntil(); !t.IsZero() {
+			retainUntil = t.Unix()
+		}
+	}
+
 	return &pb.UploadMetadata{
-		FileId:         rec.FileID,
-		FileName:       rec.FileName,
-		Size:           size,
-		UploadedChunks: chunks,
-		Status:         rec.Status,
+		FileId:           rec.FileID,
+		FileName:         rec.FileName,
+		Size:             size,
+		UploadedChunks:   chunks,
+		Status:           rec.Status,
+		EncryptionScheme: rec.ClientEncScheme,
+		WrappedKey:       rec.ClientWrappedKey,
+		KeyId:            rec.ClientKeyID,
+		Version:          rec.Version,
+		LegalHold:        legalHold,
+		RetainUntil:      retainUntil,
+		Metadata:         root.Metadata,
+		Tags:             root.Tags,
+		Extraction:       s.extractionState(root.FileID),
+		UrlImport:        s.importState(rec.FileID),
 	}, nil
 }
 
-// DeleteFile removes a file and its metadata
+// DeleteFile moves a file, or a single version of it, to the trash
 func (s *UploadService) DeleteFile(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	s = s.scoped(ctx)
 	fileID := req.FileId
 
 	// Get file info from database
@@ -298,33 +763,34 @@ func (s *UploadService) DeleteFile(ctx context.Context, req *pb.DeleteRequest) (
 			Message: "File not found",
 		}, nil
 	}
-
-	// Delete physical file if it exists
-	if rec.StoredPath != "" {
-		if err := os.Remove(rec.StoredPath); err != nil {
-			log.Printf("DeleteFile remove error: file_id=%s, path=%s, error=%v", fileID, rec.StoredPath, err)
-		}
+	if _, err := s.authorize(ctx, rec, permDelete); err != nil {
+		log.Printf("DeleteFile denied: file_id=%s, error=%v", fileID, err)
+		return nil, err
+	}
+	if err := s.checkRetention(ctx, s.auditActor(ctx), "delete", fileID); err != nil {
+		return nil, err
 	}
 
-	// Delete temp directory if it exists
-	tmpDir, _, _ := paths(s.tempDir, fileID, rec.FileName)
-	os.RemoveAll(tmpDir)
-
-	// Clean up Redis keys
-	cleanupChunks(ctx, s.rdb, fileID)
-
-	// Delete from database
-	if err := s.db.DeleteUpload(fileID); err != nil {
+	// Move the file to the trash; its content is kept until it is purged
+	trashed, err := s.db.TrashUpload(fileID, s.auditActor(ctx))
+	if err != nil {
 		log.Printf("DeleteFile db error: file_id=%s, error=%v", fileID, err)
 		return &pb.DeleteResponse{
 			Success: false,
 			Message: "Database deletion failed",
 		}, nil
 	}
+	if !trashed {
+		return &pb.DeleteResponse{
+			Success: false,
+			Message: "File not found",
+		}, nil
+	}
+	s.notifyWebhooks(webhookFileDeleted, webhookData{FileID: fileID, DeletedBy: s.auditActor(ctx)})
 
 	log.Printf("DeleteFile success: file_id=%s", fileID)
 	return &pb.DeleteResponse{
 		Success: true,
-		Message: "File deleted successfully",
+		Message: "File moved to trash",
 	}, nil
 }
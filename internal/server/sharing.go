@@ -0,0 +1,301 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+const (
+	permRead   = "read"
+	permWrite  = "write"
+	permDelete = "delete"
+
+	granteeUser  = "user"
+	granteeGroup = "group"
+	granteeLink  = "link"
+)
+
+// Grant represents a single access grant on a file
+type Grant struct {
+	GrantID     string
+	FileID      string
+	GranteeType string
+	GranteeID   string
+	Permissions []string
+	ExpiresAt   *time.Time
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+// CreateGrant inserts a new access grant
+func (db *UploadDB) CreateGrant(g *Grant) error {
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO file_grants(grant_id, file_id, grantee_type, grantee_id, permissions, expires_at, created_by)
+		 VALUES($1, $2, $3, $4, $5, $6, $7)`,
+		g.GrantID, g.FileID, g.GranteeType, g.GranteeID, g.Permissions, g.ExpiresAt, g.CreatedBy,
+	)
+	return err
+}
+
+// DeleteGrant removes a grant from a file, reporting whether it existed
+func (db *UploadDB) DeleteGrant(fileID, grantID string) (bool, error) {
+	tag, err := db.pool.Exec(context.Background(),
+		`DELETE FROM file_grants WHERE file_id=$1 AND grant_id=$2`,
+		fileID, grantID,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListGrants returns all grants on a file, newest first
+func (db *UploadDB) ListGrants(fileID string) ([]*Grant, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT grant_id, file_id, grantee_type, grantee_id, permissions, expires_at, created_by, created_at
+		 FROM file_grants WHERE file_id=$1 ORDER BY created_at DESC`,
+		fileID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []*Grant
+	for rows.Next() {
+		var g Grant
+		if err := rows.Scan(&g.GrantID, &g.FileID, &g.GranteeType, &g.GranteeID, &g.Permissions, &g.ExpiresAt, &g.CreatedBy, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		grants = append(grants, &g)
+	}
+	return grants, rows.Err()
+}
+
+// HasGrant reports whether an unexpired grant gives perm on fileID to the
+// given user or groups, or to the holder of a link token hash.
+func (db *UploadDB) HasGrant(fileID, perm, userID string, groups []string, linkHash string) (bool, error) {
+	var exists bool
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT EXISTS (
+		   SELECT 1 FROM file_grants
+		   WHERE file_id=$1 AND $2 = ANY(permissions)
+		     AND (expires_at IS NULL OR expires_at > now())
+		     AND ((grantee_type='user' AND grantee_id=$3 AND $3 <> '')
+		       OR (grantee_type='group' AND grantee_id = ANY($4))
+		       OR (grantee_type='link' AND grantee_id=$5 AND $5 <> ''))
+		 )`,
+		fileID, perm, userID, groups, linkHash,
+	).Scan(&exists)
+	return exists, err
+}
+
+func hashLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func shareTokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get("x-share-token"); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// authorize checks that the caller may perform perm on rec. Owners have every
+// permission; other callers need a grant, either through their JWT identity
+// or a public link token in the x-share-token metadata. The returned caller is
+// nil for link-token access. Access to any version of a file follows the
+// owner and grants of its first version.
+func (s *UploadService) authorize(ctx context.Context, rec *UploadRecord, perm string) (*caller, error) {
+	rec, err := s.rootOf(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if tok := shareTokenFromContext(ctx); tok != "" {
+		ok, err := s.db.HasGrant(rec.FileID, perm, "", nil, hashLinkToken(tok))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "grant lookup error: %v", err)
+		}
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, "share link does not grant access")
+		}
+		return nil, nil
+	}
+
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.UserID == rec.UserID {
+		return c, nil
+	}
+	ok, err := s.db.HasGrant(rec.FileID, perm, c.UserID, c.Groups, "")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "grant lookup error: %v", err)
+	}
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "no %s access to this file", perm)
+	}
+	return c, nil
+}
+
+// requireOwner loads a file and checks the JWT caller owns it. Versions are
+// owned by the owner of the file's first version, whoever uploaded them.
+func (s *UploadService) requireOwner(ctx context.Context, fileID string) (*UploadRecord, string, error) {
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	rec, err := s.db.GetUploadByID(fileID)
+	if err != nil {
+		return nil, "", status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	root, err := s.rootOf(rec)
+	if err != nil {
+		return nil, "", status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if root.UserID != userID {
+		return nil, "", status.Error(codes.PermissionDenied, "not the owner of this file")
+	}
+	return rec, userID, nil
+}
+
+func grantToPB(g *Grant) *pb.ShareGrant {
+	out := &pb.ShareGrant{
+		GrantId:     g.GrantID,
+		FileId:      g.FileID,
+		GranteeType: g.GranteeType,
+		Permissions: g.Permissions,
+		CreatedAt:   g.CreatedAt.Unix(),
+	}
+	// Link grants store a token hash, which is never returned
+	if g.GranteeType != granteeLink {
+		out.GranteeId = g.GranteeID
+	}
+	if g.ExpiresAt != nil {
+		out.ExpiresAt = g.ExpiresAt.Unix()
+	}
+	return out
+}
+
+func validPermissions(perms []string) bool {
+	if len(perms) == 0 {
+		return false
+	}
+	for _, p := range perms {
+		if p != permRead && p != permWrite && p != permDelete {
+			return false
+		}
+	}
+	return true
+}
+
+// ShareFile grants a user, group or public link access to a file
+func (s *UploadService) ShareFile(ctx context.Context, req *pb.ShareFileRequest) (*pb.ShareGrant, error) {
+	s = s.scoped(ctx)
+	rec, userID, err := s.requireOwner(ctx, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+	if !validPermissions(req.Permissions) {
+		return nil, status.Error(codes.InvalidArgument, "permissions must be any of read, write, delete")
+	}
+	if req.ExpiresInSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "expires_in_seconds must not be negative")
+	}
+
+	g := &Grant{
+		GrantID:     uuid.NewString(),
+		FileID:      rec.FileID,
+		GranteeType: req.GranteeType,
+		GranteeID:   req.GranteeId,
+		Permissions: req.Permissions,
+		CreatedBy:   userID,
+		CreatedAt:   time.Now(),
+	}
+	if req.ExpiresInSeconds > 0 {
+		exp := time.Now().Add(time.Duration(req.ExpiresInSeconds) * time.Second)
+		g.ExpiresAt = &exp
+	}
+
+	var linkToken string
+	switch req.GranteeType {
+	case granteeUser, granteeGroup:
+		if req.GranteeId == "" {
+			return nil, status.Error(codes.InvalidArgument, "grantee_id is required")
+		}
+	case granteeLink:
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to generate link token: %v", err)
+		}
+		linkToken = base64.RawURLEncoding.EncodeToString(buf)
+		g.GranteeID = hashLinkToken(linkToken)
+	default:
+		return nil, status.Error(codes.InvalidArgument, "grantee_type must be user, group or link")
+	}
+
+	if err := s.db.CreateGrant(g); err != nil {
+		log.Printf("ShareFile error: user_id=%s, file_id=%s, error=%v", userID, rec.FileID, err)
+		return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+	}
+
+	log.Printf("ShareFile success: user_id=%s, file_id=%s, grant_id=%s, grantee_type=%s, permissions=%v", userID, rec.FileID, g.GrantID, g.GranteeType, g.Permissions)
+	out := grantToPB(g)
+	out.LinkToken = linkToken
+	return out, nil
+}
+
+// UnshareFile revokes a grant
+func (s *UploadService) UnshareFile(ctx context.Context, req *pb.UnshareFileRequest) (*pb.UnshareFileResponse, error) {
+	s = s.scoped(ctx)
+	rec, userID, err := s.requireOwner(ctx, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.db.DeleteGrant(rec.FileID, req.GrantId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db delete error: %v", err)
+	}
+	if !ok {
+		return nil, status.Error(codes.NotFound, "grant not found")
+	}
+
+	log.Printf("UnshareFile success: user_id=%s, file_id=%s, grant_id=%s", userID, rec.FileID, req.GrantId)
+	return &pb.UnshareFileResponse{Success: true}, nil
+}
+
+// ListGrants returns the grants on a file owned by the caller
+func (s *UploadService) ListGrants(ctx context.Context, req *pb.ListGrantsRequest) (*pb.ListGrantsResponse, error) {
+	s = s.scoped(ctx)
+	rec, _, err := s.requireOwner(ctx, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := s.db.ListGrants(rec.FileID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+
+	resp := &pb.ListGrantsResponse{}
+	for _, g := range grants {
+		resp.Grants = append(resp.Grants, grantToPB(g))
+	}
+	return resp, nil
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/internal/signing"
+	"upload-backend/pb"
+)
+
+const (
+	defaultSignedURLTTL = time.Hour
+	maxSignedURLTTL     = 7 * 24 * time.Hour
+)
+
+var (
+	signedURLSecret  = []byte(getEnvOrDefault("SIGNED_URL_SECRET", string(jwtSecret)))
+	publicGatewayURL = strings.TrimRight(getEnvOrDefault("PUBLIC_GATEWAY_URL", "http://localhost:8080"), "/")
+)
+
+// CreateSignedURL records a signed download URL for a file
+func (db *UploadDB) CreateSignedURL(id, fileID, createdBy string, expiresAt time.Time, maxDownloads int64, allowedIP string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO signed_urls(id, file_id, created_by, expires_at, max_downloads, allowed_ip)
+		 VALUES($1, $2, $3, $4, $5, NULLIF($6, ''))`,
+		id, fileID, createdBy, expiresAt, maxDownloads, allowedIP,
+	)
+	return err
+}
+
+// ConsumeSignedURL counts one download against a signed URL. It returns false
+// when the URL does not exist, is expired or has reached its download limit.
+func (db *UploadDB) ConsumeSignedURL(id, fileID string) (bool, error) {
+	var count int64
+	err := db.pool.QueryRow(context.Background(),
+		`UPDATE signed_urls SET download_count = download_count + 1
+		 WHERE id=$1 AND file_id=$2 AND expires_at > now()
+		   AND (max_downloads = 0 OR download_count < max_downloads)
+		 RETURNING download_count`,
+		id, fileID,
+	).Scan(&count)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateSignedURL issues a time-limited download URL for a completed file owned by the caller
+func (s *UploadService) CreateSignedURL(ctx context.Context, req *pb.CreateSignedURLRequest) (*pb.CreateSignedURLResponse, error) {
+	s = s.scoped(ctx)
+	rec, userID, err := s.requireOwner(ctx, req.FileId)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.currentVersion(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "version lookup error: %v", err)
+	}
+	if current.Status != "completed" {
+		return nil, status.Error(codes.FailedPrecondition, "upload is not completed")
+	}
+
+	ttl := defaultSignedURLTTL
+	if req.ExpiresInSeconds > 0 {
+		ttl = time.Duration(req.ExpiresInSeconds) * time.Second
+	}
+	if ttl > maxSignedURLTTL {
+		return nil, status.Errorf(codes.InvalidArgument, "expiry exceeds maximum of %s", maxSignedURLTTL)
+	}
+	if req.MaxDownloads < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_downloads must not be negative")
+	}
+
+	params := signing.URLParams{
+		FileID:       rec.FileID,
+		URLID:        uuid.NewString(),
+		ExpiresAt:    time.Now().Add(ttl).Unix(),
+		MaxDownloads: req.MaxDownloads,
+		AllowedIP:    req.AllowedIp,
+	}
+	expiresAt := time.Unix(params.ExpiresAt, 0)
+	if err := s.db.CreateSignedURL(params.URLID, rec.FileID, userID, expiresAt, params.MaxDownloads, params.AllowedIP); err != nil {
+		log.Printf("CreateSignedURL error: user_id=%s, file_id=%s, error=%v", userID, rec.FileID, err)
+		return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+	}
+
+	sig := signing.SignURL(signedURLSecret, params)
+	link := publicGatewayURL + "/v1/signed/" + url.PathEscape(rec.FileID) + "?" + params.Query(sig).Encode()
+
+	log.Printf("CreateSignedURL success: user_id=%s, file_id=%s, url_id=%s, expires_at=%d", userID, rec.FileID, params.URLID, params.ExpiresAt)
+	return &pb.CreateSignedURLResponse{
+		Url:       link,
+		UrlId:     params.URLID,
+		ExpiresAt: params.ExpiresAt,
+	}, nil
+}
+
+// DownloadSigned serves a file for a signed URL without a bearer token
+func (s *UploadService) DownloadSigned(ctx context.Context, req *pb.SignedDownloadRequest) (*pb.DownloadResponse, error) {
+	s = s.scoped(ctx)
+	params := signing.URLParams{
+		FileID:       req.FileId,
+		URLID:        req.UrlId,
+		ExpiresAt:    req.ExpiresAt,
+		MaxDownloads: req.MaxDownloads,
+		AllowedIP:    req.AllowedIp,
+	}
+	if err := signing.VerifyURL(signedURLSecret, params, req.Signature, time.Now(), s.clientIP(ctx)); err != nil {
+		log.Printf("DownloadSigned rejected: file_id=%s, url_id=%s, error=%v", req.FileId, req.UrlId, err)
+		return nil, status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+
+	ok, err := s.db.ConsumeSignedURL(req.UrlId, req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db error: %v", err)
+	}
+	if !ok {
+		return nil, status.Error(codes.PermissionDenied, "signed url is no longer valid")
+	}
+
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	// Links always serve the file's current version
+	if rec, err = s.currentVersion(rec); err != nil {
+		return nil, status.Errorf(codes.Internal, "version lookup error: %v", err)
+	}
+	if rec.Status != "completed" {
+		return nil, status.Error(codes.FailedPrecondition, "file is not available")
+	}
+	data, err := s.readStoredFile(rec)
+	if err != nil {
+		log.Printf("DownloadSigned read error: file_id=%s, error=%v", req.FileId, err)
+		return nil, status.Errorf(codes.Internal, "failed to read file: %v", err)
+	}
+
+	log.Printf("DownloadSigned success: file_id=%s, url_id=%s, size=%d", req.FileId, req.UrlId, len(data))
+	return &pb.DownloadResponse{
+		Content:          data,
+		FileName:         rec.FileName,
+		EncryptionScheme: rec.ClientEncScheme,
+		WrappedKey:       rec.ClientWrappedKey,
+		KeyId:            rec.ClientKeyID,
+		Version:          rec.Version,
+		VersionId:        contentID(rec),
+	}, nil
+}
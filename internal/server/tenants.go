@@ -0,0 +1,699 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+const (
+	// defaultTenantID owns requests without a tenant claim and all data
+	// created before tenants existed
+	defaultTenantID = "default"
+	maxTenantIDLen  = 63
+	tenantCacheTTL  = 30 * time.Second
+)
+
+// Tenant is a team hosted by the service. Its files, folders, grants and
+// audit events are invisible to other tenants, its content is stored under
+// its own directory, and it may have its own limits and content policy.
+type Tenant struct {
+	ID          string
+	Name        string
+	QuotaBytes  int64          // 0 for unlimited
+	MaxFileSize int64          // 0 for unlimited
+	Policy      *ContentPolicy // layered over the deployment policy; nil for none
+	Disabled    bool
+	CreatedAt   time.Time
+}
+
+// TenantUsage is the stored content of a tenant. Trashed files count until
+// they are purged.
+type TenantUsage struct {
+	Bytes int64
+	Files int64
+}
+
+// validTenantID allows lowercase letters, digits and dashes, since the ID
+// names the tenant's storage directory
+func validTenantID(id string) bool {
+	if id == "" || len(id) > maxTenantIDLen || id[0] == '-' {
+		return false
+	}
+	for _, c := range id {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// tenantStorageDir is where a tenant's content is stored. The default
+// tenant keeps the layout from before tenants existed.
+func tenantStorageDir(root, tenantID string) string {
+	if tenantID == defaultTenantID {
+		return root
+	}
+	return filepath.Join(root, "tenants", tenantID)
+}
+
+// dbConn is the part of a connection pool UploadDB queries through
+type dbConn interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// tenantConn runs every statement in a transaction that sets app.tenant_id,
+// so the row-level security policies of migrations.sql limit it to one
+// tenant and new rows default to that tenant
+type tenantConn struct {
+	pool   *pgxpool.Pool
+	tenant string
+}
+
+func (c tenantConn) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := c.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `SELECT set_config('app.tenant_id', $1, true)`, c.tenant); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (c tenantConn) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tx, err := c.Begin(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer tx.Rollback(ctx)
+	tag, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return tag, err
+	}
+	return tag, tx.Commit(ctx)
+}
+
+func (c tenantConn) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	tx, err := c.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	return &tenantRows{Rows: rows, ctx: ctx, tx: tx}, nil
+}
+
+func (c tenantConn) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	rows, err := c.Query(ctx, sql, args...)
+	return tenantRow{rows: rows, err: err}
+}
+
+// tenantRows ends the transaction of a query once its rows are read or
+// closed. Queries may write, e.g. UPDATE ... RETURNING, so it commits unless
+// reading failed.
+type tenantRows struct {
+	pgx.Rows
+	ctx  context.Context
+	tx   pgx.Tx
+	done bool
+	err  error
+}
+
+func (r *tenantRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.Close()
+	return false
+}
+
+func (r *tenantRows) Close() {
+	if r.done {
+		return
+	}
+	r.done = true
+	r.Rows.Close()
+	if r.Rows.Err() != nil {
+		r.tx.Rollback(r.ctx)
+		return
+	}
+	r.err = r.tx.Commit(r.ctx)
+}
+
+func (r *tenantRows) Err() error {
+	if err := r.Rows.Err(); err != nil {
+		return err
+	}
+	return r.err
+}
+
+type tenantRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r tenantRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	r.rows.Close()
+	return r.rows.Err()
+}
+
+// currentTenantSQL is the tenant a statement runs for: the one tenantConn
+// sets, else the default tenant new rows get. Statements on the tables keyed
+// by content hash compare tenant_id with it, so they stay within one tenant
+// even if row-level security were not applied.
+const currentTenantSQL = `COALESCE(NULLIF(current_setting('app.tenant_id', true), ''), 'default')`
+
+// ForTenant returns a UploadDB whose queries only see, and only create,
+// rows of the given tenant
+func (db *UploadDB) ForTenant(tenantID string) *UploadDB {
+	return &UploadDB{pool: tenantConn{pool: db.base, tenant: tenantID}, base: db.base, tenant: tenantID}
+}
+
+// CheckRowSecurity reports whether the database role is subject to
+// row-level security. Superusers and roles with BYPASSRLS see every tenant.
+func (db *UploadDB) CheckRowSecurity() error {
+	var bypass bool
+	err := db.base.QueryRow(context.Background(),
+		`SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user`,
+	).Scan(&bypass)
+	if err != nil {
+		return err
+	}
+	if bypass {
+		return errors.New("database role bypasses row-level security, so tenants are not isolated")
+	}
+	return nil
+}
+
+const tenantColumns = `id, name, quota_bytes, max_file_size, content_policy, disabled, created_at`
+
+func scanTenant(row pgx.Row) (*Tenant, error) {
+	var t Tenant
+	var policy []byte
+	if err := row.Scan(&t.ID, &t.Name, &t.QuotaBytes, &t.MaxFileSize, &policy, &t.Disabled, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		t.Policy = &ContentPolicy{}
+		if err := json.Unmarshal(policy, t.Policy); err != nil {
+			return nil, fmt.Errorf("content policy of tenant %s: %w", t.ID, err)
+		}
+	}
+	return &t, nil
+}
+
+// policyJSON encodes a tenant's content policy for storage; nil stays NULL
+func policyJSON(p *ContentPolicy) ([]byte, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return json.Marshal(p)
+}
+
+// CreateTenant stores a new tenant
+func (db *UploadDB) CreateTenant(t *Tenant) error {
+	policy, err := policyJSON(t.Policy)
+	if err != nil {
+		return err
+	}
+	return db.pool.QueryRow(context.Background(),
+		`INSERT INTO tenants (id, name, quota_bytes, max_file_size, content_policy)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING created_at`,
+		t.ID, t.Name, t.QuotaBytes, t.MaxFileSize, policy,
+	).Scan(&t.CreatedAt)
+}
+
+// GetTenant loads a tenant by ID
+func (db *UploadDB) GetTenant(id string) (*Tenant, error) {
+	return scanTenant(db.pool.QueryRow(context.Background(),
+		`SELECT `+tenantColumns+` FROM tenants WHERE id = $1`,
+		id,
+	))
+}
+
+// ListTenants returns every tenant ordered by ID
+func (db *UploadDB) ListTenants() ([]*Tenant, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT `+tenantColumns+` FROM tenants ORDER BY id`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenants []*Tenant
+	for rows.Next() {
+		t, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, rows.Err()
+}
+
+// UpdateTenant replaces the settings of a tenant
+func (db *UploadDB) UpdateTenant(t *Tenant) error {
+	policy, err := policyJSON(t.Policy)
+	if err != nil {
+		return err
+	}
+	return db.pool.QueryRow(context.Background(),
+		`UPDATE tenants SET name = $2, quota_bytes = $3, max_file_size = $4, content_policy = $5,
+		        disabled = $6, updated_at = now()
+		 WHERE id = $1
+		 RETURNING created_at`,
+		t.ID, t.Name, t.QuotaBytes, t.MaxFileSize, policy, t.Disabled,
+	).Scan(&t.CreatedAt)
+}
+
+// TenantUsage sums the stored files visible to db, i.e. those of its tenant
+func (db *UploadDB) TenantUsage() (*TenantUsage, error) {
+	var u TenantUsage
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT COALESCE(SUM(size_bytes), 0)::bigint,
+		        count(*) FILTER (WHERE version_of IS NULL AND deleted_at IS NULL)
+		 FROM uploads WHERE status IN ('completed', 'quarantined')`,
+	).Scan(&u.Bytes, &u.Files)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// FileTenant returns the tenant of an upload, looking across all tenants
+func (db *UploadDB) FileTenant(fileID string) (string, error) {
+	var tenantID string
+	err := db.base.QueryRow(context.Background(),
+		`SELECT tenant_id FROM uploads WHERE file_id = $1`,
+		fileID,
+	).Scan(&tenantID)
+	return tenantID, err
+}
+
+// tenantCache keeps tenant settings for tenantCacheTTL, so resolving the
+// tenant of a request rarely needs a query. Changes made through another
+// server take up to that long to apply.
+type tenantCache struct {
+	mu      sync.Mutex
+	entries map[string]tenantCacheEntry
+}
+
+type tenantCacheEntry struct {
+	tenant   *Tenant
+	loadedAt time.Time
+}
+
+// lookupTenant loads a tenant's settings through the cache
+func (s *UploadService) lookupTenant(id string) (*Tenant, error) {
+	s.tenants.mu.Lock()
+	e, ok := s.tenants.entries[id]
+	s.tenants.mu.Unlock()
+	if ok && time.Since(e.loadedAt) < tenantCacheTTL {
+		return e.tenant, nil
+	}
+
+	t, err := s.db.GetTenant(id)
+	if err != nil {
+		return nil, err
+	}
+	s.tenants.mu.Lock()
+	s.tenants.entries[id] = tenantCacheEntry{tenant: t, loadedAt: time.Now()}
+	s.tenants.mu.Unlock()
+	return t, nil
+}
+
+func (s *UploadService) forgetTenant(id string) {
+	s.tenants.mu.Lock()
+	delete(s.tenants.entries, id)
+	s.tenants.mu.Unlock()
+}
+
+// forTenant returns a copy of the service whose database access, storage
+// and content policy are those of tenant t
+func (s *UploadService) forTenant(t *Tenant) *UploadService {
+	base := s
+	if s.system != nil {
+		base = s.system
+	}
+	c := *base
+	c.system = base
+	c.tenant = t
+	c.db = base.db.ForTenant(t.ID)
+	c.tempDir = tenantStorageDir(base.tempDir, t.ID)
+	c.policy = base.policy.withOverrides(t.Policy)
+	return &c
+}
+
+type tenantKey struct{}
+
+func withTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// scoped returns the service limited to the tenant of a request, as found
+// by the tenant interceptors. Requests that bypass them run in the default
+// tenant; a service already scoped stays in its tenant.
+func (s *UploadService) scoped(ctx context.Context) *UploadService {
+	t, ok := ctx.Value(tenantKey{}).(*Tenant)
+	if !ok {
+		if s.tenant != nil {
+			return s
+		}
+		if t, _ = s.lookupTenant(defaultTenantID); t == nil {
+			t = &Tenant{ID: defaultTenantID}
+		}
+	}
+	if s.tenant != nil && s.tenant.ID == t.ID {
+		return s
+	}
+	return s.forTenant(t)
+}
+
+// requestTenant finds the tenant a request runs in: the tenant claim of its
+// bearer JWT, or without one the tenant of the file named by its upload
+// token or by the request itself, and otherwise the default tenant. Callers
+// without a JWT only reach files through capabilities that are checked
+// later, such as upload tokens, share links and signed URLs.
+func (s *UploadService) requestTenant(ctx context.Context, req interface{}) (*Tenant, error) {
+	id := defaultTenantID
+	if c, err := s.authenticate(ctx); err == nil {
+		id = c.Tenant
+	} else if fileID := capabilityFileID(ctx, req); fileID != "" {
+		if fileTenant, err := s.db.FileTenant(fileID); err == nil {
+			id = fileTenant
+		}
+	}
+
+	t, err := s.lookupTenant(id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.PermissionDenied, "unknown tenant %q", id)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "tenant lookup error: %v", err)
+	}
+	if t.Disabled {
+		return nil, status.Errorf(codes.PermissionDenied, "tenant %q is disabled", id)
+	}
+	return t, nil
+}
+
+func capabilityFileID(ctx context.Context, req interface{}) string {
+	if tok := uploadTokenFromContext(ctx); tok != "" {
+		if claims, err := parseUploadToken(tok); err == nil {
+			return claims.FileID
+		}
+	}
+	if r, ok := req.(fileIDGetter); ok {
+		return r.GetFileId()
+	}
+	return ""
+}
+
+// TenantUnaryInterceptor resolves the tenant of each unary request. It must
+// run before the audit interceptor so audit events land in the tenant.
+func (s *UploadService) TenantUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		t, err := s.requestTenant(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return handler(withTenant(ctx, t), req)
+	}
+}
+
+// tenantStream resolves the tenant from the first message of streams that
+// carry neither a JWT nor an upload token
+type tenantStream struct {
+	grpc.ServerStream
+	s        *UploadService
+	ctx      context.Context
+	resolved bool
+}
+
+func (t *tenantStream) Context() context.Context {
+	return t.ctx
+}
+
+func (t *tenantStream) RecvMsg(m interface{}) error {
+	if err := t.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !t.resolved {
+		t.resolved = true
+		tenant, err := t.s.requestTenant(t.ServerStream.Context(), m)
+		if err != nil {
+			return err
+		}
+		t.ctx = withTenant(t.ServerStream.Context(), tenant)
+	}
+	return nil
+}
+
+// TenantStreamInterceptor resolves the tenant of each stream
+func (s *UploadService) TenantStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ts := &tenantStream{ServerStream: ss, s: s, ctx: ss.Context()}
+		ctx := ss.Context()
+		if _, err := s.authenticate(ctx); err == nil || uploadTokenFromContext(ctx) != "" {
+			t, err := s.requestTenant(ctx, nil)
+			if err != nil {
+				return err
+			}
+			ts.ctx = withTenant(ctx, t)
+			ts.resolved = true
+		}
+		return handler(srv, ts)
+	}
+}
+
+// eachTenant runs fn once per tenant with the service scoped to it. Background
+// jobs use it so their work on each file happens within the file's tenant.
+func (s *UploadService) eachTenant(fn func(ts *UploadService)) {
+	tenants, err := s.db.ListTenants()
+	if err != nil {
+		log.Printf("Tenant list error: error=%v", err)
+		return
+	}
+	for _, t := range tenants {
+		fn(s.forTenant(t))
+	}
+}
+
+// checkFileSize enforces the tenant's maximum file size
+func (s *UploadService) checkFileSize(size int64) error {
+	if limit := s.maxFileSize(); limit > 0 && size > limit {
+		return status.Errorf(codes.InvalidArgument, "file exceeds the maximum size of %d bytes", limit)
+	}
+	return nil
+}
+
+// maxFileSize returns the tenant's limit on single files, or 0
+func (s *UploadService) maxFileSize() int64 {
+	if s.tenant == nil {
+		return 0
+	}
+	return s.tenant.MaxFileSize
+}
+
+// checkQuota refuses to store a file of size bytes when it is larger than
+// the tenant allows or would take the tenant over its quota
+func (s *UploadService) checkQuota(size int64) error {
+	if err := s.checkFileSize(size); err != nil {
+		return err
+	}
+	if s.tenant == nil || s.tenant.QuotaBytes == 0 {
+		return nil
+	}
+	usage, err := s.db.TenantUsage()
+	if err != nil {
+		return status.Errorf(codes.Internal, "quota lookup error: %v", err)
+	}
+	if usage.Bytes+size > s.tenant.QuotaBytes {
+		return status.Errorf(codes.ResourceExhausted, "storage quota exceeded: %d of %d bytes used", usage.Bytes, s.tenant.QuotaBytes)
+	}
+	return nil
+}
+
+// requireTenantAdmin checks for an admin of the default tenant, who manages
+// the other tenants
+func (s *UploadService) requireTenantAdmin(ctx context.Context) (*caller, error) {
+	c, err := s.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.Tenant != defaultTenantID {
+		return nil, status.Error(codes.PermissionDenied, "tenants are managed by admins of the default tenant")
+	}
+	return c, nil
+}
+
+// tenantSettings validates the settings of a create or update request
+func tenantSettings(t *Tenant, policy string) error {
+	if t.Name == "" {
+		t.Name = t.ID
+	}
+	if t.QuotaBytes < 0 || t.MaxFileSize < 0 {
+		return status.Error(codes.InvalidArgument, "quota_bytes and max_file_size must not be negative")
+	}
+	if policy != "" {
+		t.Policy = &ContentPolicy{}
+		if err := json.Unmarshal([]byte(policy), t.Policy); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid content_policy: %v", err)
+		}
+	}
+	return nil
+}
+
+// tenantResponse describes a tenant with its current usage
+func (s *UploadService) tenantResponse(t *Tenant) (*pb.Tenant, error) {
+	usage, err := s.db.ForTenant(t.ID).TenantUsage()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "usage lookup error: %v", err)
+	}
+	policy, err := policyJSON(t.Policy)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode content policy: %v", err)
+	}
+	return &pb.Tenant{
+		TenantId:      t.ID,
+		Name:          t.Name,
+		QuotaBytes:    t.QuotaBytes,
+		MaxFileSize:   t.MaxFileSize,
+		ContentPolicy: string(policy),
+		Disabled:      t.Disabled,
+		UsedBytes:     usage.Bytes,
+		FileCount:     usage.Files,
+		CreatedAt:     t.CreatedAt.Unix(),
+	}, nil
+}
+
+// CreateTenant adds a tenant
+func (s *UploadService) CreateTenant(ctx context.Context, req *pb.CreateTenantRequest) (*pb.Tenant, error) {
+	c, err := s.requireTenantAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !validTenantID(req.TenantId) {
+		return nil, status.Errorf(codes.InvalidArgument, "tenant_id must be 1-%d lowercase letters, digits and dashes", maxTenantIDLen)
+	}
+	t := &Tenant{ID: req.TenantId, Name: req.Name, QuotaBytes: req.QuotaBytes, MaxFileSize: req.MaxFileSize}
+	if err := tenantSettings(t, req.ContentPolicy); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.CreateTenant(t); err != nil {
+		if pgCode(err) == pgUniqueViolation {
+			return nil, status.Errorf(codes.AlreadyExists, "tenant %s already exists", t.ID)
+		}
+		return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+	}
+
+	log.Printf("CreateTenant success: tenant_id=%s, quota_bytes=%d, max_file_size=%d, by=%s", t.ID, t.QuotaBytes, t.MaxFileSize, c.UserID)
+	return s.tenantResponse(t)
+}
+
+// ListTenants returns every tenant with its usage
+func (s *UploadService) ListTenants(ctx context.Context, req *pb.ListTenantsRequest) (*pb.ListTenantsResponse, error) {
+	if _, err := s.requireTenantAdmin(ctx); err != nil {
+		return nil, err
+	}
+	tenants, err := s.db.ListTenants()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	resp := &pb.ListTenantsResponse{}
+	for _, t := range tenants {
+		pt, err := s.tenantResponse(t)
+		if err != nil {
+			return nil, err
+		}
+		resp.Tenants = append(resp.Tenants, pt)
+	}
+	return resp, nil
+}
+
+// GetTenant returns one tenant with its usage
+func (s *UploadService) GetTenant(ctx context.Context, req *pb.GetTenantRequest) (*pb.Tenant, error) {
+	if _, err := s.requireTenantAdmin(ctx); err != nil {
+		return nil, err
+	}
+	t, err := s.db.GetTenant(req.TenantId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "tenant %s not found", req.TenantId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	return s.tenantResponse(t)
+}
+
+// UpdateTenant replaces the settings of a tenant. Disabling a tenant refuses
+// its requests but keeps its data.
+func (s *UploadService) UpdateTenant(ctx context.Context, req *pb.UpdateTenantRequest) (*pb.Tenant, error) {
+	c, err := s.requireTenantAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.TenantId == defaultTenantID && req.Disabled {
+		return nil, status.Error(codes.InvalidArgument, "the default tenant cannot be disabled")
+	}
+	t := &Tenant{
+		ID:          req.TenantId,
+		Name:        req.Name,
+		QuotaBytes:  req.QuotaBytes,
+		MaxFileSize: req.MaxFileSize,
+		Disabled:    req.Disabled,
+	}
+	if err := tenantSettings(t, req.ContentPolicy); err != nil {
+		return nil, err
+	}
+
+	err = s.db.UpdateTenant(t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.NotFound, "tenant %s not found", req.TenantId)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db update error: %v", err)
+	}
+	s.forgetTenant(t.ID)
+
+	log.Printf("UpdateTenant success: tenant_id=%s, quota_bytes=%d, max_file_size=%d, disabled=%t, by=%s",
+		t.ID, t.QuotaBytes, t.MaxFileSize, t.Disabled, c.UserID)
+	return s.tenantResponse(t)
+}
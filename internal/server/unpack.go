@@ -0,0 +1,674 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"upload-backend/pb"
+)
+
+// Archives uploaded with extract are unpacked in the background once they
+// complete. Every member becomes a separate upload inside a new folder next
+// to the archive, which itself is kept. The archive is read twice: a first
+// pass checks its members against the limits below without writing
+// anything, the second creates the folders and files. Members that are
+// unsafe to extract are skipped; any other failure removes everything the
+// extraction created. Archives inside the archive are not unpacked.
+// A running extraction holds a lease; one whose server went away is undone
+// and started over by ResumeExtractions.
+
+const (
+	maxConcurrentUnpacks = 2
+	maxUnpackEntries     = 10000
+	maxUnpackSize        = 8 << 30 // bytes extracted from one archive
+
+	// Output beyond unpackRatioFloor may not exceed maxUnpackRatio times
+	// the packed size, for the whole archive and for single zip members
+	maxUnpackRatio   = 100
+	unpackRatioFloor = 64 << 20
+
+	unpackSniffSize = 512
+	unpackLease     = 2 * time.Minute
+)
+
+// Extraction is the state of unpacking one archive
+type Extraction struct {
+	FileID   string
+	Status   string // pending, running, completed or failed
+	FolderID string
+	Files    int
+	Skipped  int
+	Error    string
+}
+
+// CreateExtraction records that an upload is to be unpacked once it completes
+func (db *UploadDB) CreateExtraction(fileID string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`INSERT INTO archive_extractions (file_id) VALUES ($1) ON CONFLICT (file_id) DO NOTHING`,
+		fileID,
+	)
+	return err
+}
+
+// ClaimExtraction takes the lease on an extraction that is pending, or
+// running without a live lease, and marks it running. The returned
+// extraction names the folder an interrupted attempt left behind, if any.
+// It returns pgx.ErrNoRows if there is nothing to claim.
+func (db *UploadDB) ClaimExtraction(fileID string, lease time.Duration) (*Extraction, error) {
+	x := &Extraction{FileID: fileID, Status: "running"}
+	err := db.pool.QueryRow(context.Background(),
+		`UPDATE archive_extractions SET status = 'running', lease_until = now() + $2 * interval '1 second', updated_at = now()
+		 WHERE file_id = $1 AND (status = 'pending' OR (status = 'running' AND (lease_until IS NULL OR lease_until < now())))
+		 RETURNING COALESCE(folder_id::text, '')`,
+		fileID, lease.Seconds(),
+	).Scan(&x.FolderID)
+	if err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// RenewExtraction extends the lease on a running extraction
+func (db *UploadDB) RenewExtraction(fileID string, lease time.Duration) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE archive_extractions SET lease_until = now() + $2 * interval '1 second', updated_at = now()
+		 WHERE file_id = $1 AND status = 'running'`,
+		fileID, lease.Seconds(),
+	)
+	return err
+}
+
+// SetExtractionFolder records the folder a running extraction writes to, so
+// an interrupted attempt can be undone
+func (db *UploadDB) SetExtractionFolder(fileID, folderID string) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE archive_extractions SET folder_id = NULLIF($2::text, '')::uuid, updated_at = now() WHERE file_id = $1`,
+		fileID, folderID,
+	)
+	return err
+}
+
+// UnfinishedExtractions returns the extractions of completed uploads that
+// nobody holds a lease on
+func (db *UploadDB) UnfinishedExtractions() ([]string, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT x.file_id::text FROM archive_extractions x JOIN uploads u ON u.file_id = x.file_id
+		 WHERE x.status IN ('pending', 'running') AND (x.lease_until IS NULL OR x.lease_until < now())
+		   AND u.status = 'completed'`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// FinishExtraction stores the outcome of an extraction
+func (db *UploadDB) FinishExtraction(x *Extraction) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE archive_extractions SET status = $2, folder_id = NULLIF($3::text, '')::uuid, files = $4, skipped = $5,
+		        error = NULLIF($6, ''), lease_until = NULL, updated_at = now()
+		 WHERE file_id = $1`,
+		x.FileID, x.Status, x.FolderID, x.Files, x.Skipped, x.Error,
+	)
+	return err
+}
+
+// GetExtraction loads the extraction of an upload
+func (db *UploadDB) GetExtraction(fileID string) (*Extraction, error) {
+	x := &Extraction{FileID: fileID}
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT status, COALESCE(folder_id::text, ''), files, skipped, COALESCE(error, '')
+		 FROM archive_extractions WHERE file_id = $1`,
+		fileID,
+	).Scan(&x.Status, &x.FolderID, &x.Files, &x.Skipped, &x.Error)
+	if err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+func extractionToPB(x *Extraction) *pb.ArchiveExtraction {
+	return &pb.ArchiveExtraction{
+		Status:   x.Status,
+		FolderId: x.FolderID,
+		Files:    int32(x.Files),
+		Skipped:  int32(x.Skipped),
+		Error:    x.Error,
+	}
+}
+
+// Kinds of archive members
+const (
+	memberFile = iota
+	memberDir
+	memberLink
+	memberOther
+)
+
+// archiveMember is one member of an archive
+type archiveMember struct {
+	name   string
+	kind   int
+	size   int64
+	packed int64 // compressed size of zip members; -1 when unknown
+}
+
+// archiveKind detects the format of an archive from its first bytes
+func archiveKind(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return "zip"
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return "tar.gz"
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return "tar"
+	}
+	return ""
+}
+
+// walkArchive calls fn for every member of an archive in order. With
+// content, fn gets a reader for the data of regular files; without, it gets
+// nil and reading the archive stops as soon as fn returns an error.
+func walkArchive(kind string, r io.ReaderAt, size int64, content bool, fn func(m *archiveMember, data io.Reader) error) error {
+	if kind == "zip" {
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return err
+		}
+		for _, f := range zr.File {
+			m := &archiveMember{name: f.Name, size: int64(f.UncompressedSize64), packed: int64(f.CompressedSize64)}
+			switch mode := f.Mode(); {
+			case mode.IsDir():
+				m.kind = memberDir
+			case mode&fs.ModeSymlink != 0:
+				m.kind = memberLink
+			case mode.IsRegular():
+				m.kind = memberFile
+			default:
+				m.kind = memberOther
+			}
+			if !content || m.kind != memberFile {
+				if err := fn(m, nil); err != nil {
+					return err
+				}
+				continue
+			}
+			// The zip reader fails members whose data exceeds their
+			// declared size or does not match their checksum
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = fn(m, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var src io.Reader = io.NewSectionReader(r, 0, size)
+	if kind == "tar.gz" {
+		gz, err := gzip.NewReader(src)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		src = gz
+	}
+	tr := tar.NewReader(src)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		m := &archiveMember{name: h.Name, size: h.Size, packed: -1}
+		switch h.Typeflag {
+		case tar.TypeReg:
+			m.kind = memberFile
+		case tar.TypeDir:
+			m.kind = memberDir
+		case tar.TypeSymlink, tar.TypeLink:
+			m.kind = memberLink
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			m.kind = memberOther
+		}
+		var data io.Reader
+		if content && m.kind == memberFile {
+			data = tr
+		}
+		if err := fn(m, data); err != nil {
+			return err
+		}
+	}
+}
+
+// unpackPath splits the name of an archive member into safe folder and file
+// names. Absolute names and names that climb out of the archive with ".."
+// are refused rather than resolved, since that is what zip-slip relies on.
+func unpackPath(name string) ([]string, bool) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "/") || (len(name) >= 2 && name[1] == ':') {
+		return nil, false
+	}
+	var segs []string
+	for _, seg := range strings.Split(name, "/") {
+		switch seg {
+		case "", ".":
+			continue
+		case "..":
+			return nil, false
+		}
+		seg = archiveSegment(seg)
+		if len(seg) > 255 {
+			return nil, false
+		}
+		segs = append(segs, seg)
+	}
+	// Resource forks macOS adds to zips it creates are not content
+	if len(segs) == 0 || len(segs) > maxFolderDepth || segs[0] == "__MACOSX" {
+		return nil, false
+	}
+	return segs, true
+}
+
+// errUnpackLimit is wrapped by errors of archives exceeding the limits
+var errUnpackLimit = errors.New("archive exceeds extraction limits")
+
+// planUnpack checks the members of an archive against the limits without
+// extracting anything and returns the number of bytes it would write
+func planUnpack(kind string, r io.ReaderAt, size int64) (int64, error) {
+	var entries int
+	var total int64
+	err := walkArchive(kind, r, size, false, func(m *archiveMember, _ io.Reader) error {
+		if entries++; entries > maxUnpackEntries {
+			return fmt.Errorf("%w: more than %d members", errUnpackLimit, maxUnpackEntries)
+		}
+		if m.kind != memberFile {
+			return nil
+		}
+		if m.size < 0 {
+			return fmt.Errorf("%w: negative size of %q", errUnpackLimit, m.name)
+		}
+		if m.packed >= 0 && m.size > unpackRatioFloor && m.size > maxUnpackRatio*m.packed {
+			return fmt.Errorf("%w: %q is compressed more than %d:1", errUnpackLimit, m.name, maxUnpackRatio)
+		}
+		total += m.size
+		if total > maxUnpackSize {
+			return fmt.Errorf("%w: more than %d bytes", errUnpackLimit, maxUnpackSize)
+		}
+		if total > unpackRatioFloor && total > maxUnpackRatio*size {
+			return fmt.Errorf("%w: compressed more than %d:1", errUnpackLimit, maxUnpackRatio)
+		}
+		return nil
+	})
+	return total, err
+}
+
+// unpackArchive unpacks a completed upload in the background if it was
+// uploaded with extract. The extraction is only claimed once a slot is free,
+// so waiting ones stay pending and can be resumed by another server.
+func (s *UploadService) unpackArchive(fileID string) {
+	go func() {
+		// Most uploads, extracted members among them, have nothing to unpack
+		if x, err := s.db.GetExtraction(fileID); err != nil || (x.Status != "pending" && x.Status != "running") {
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				log.Printf("Extraction error: file_id=%s, error=%v", fileID, err)
+			}
+			return
+		}
+		s.unpackSlots <- struct{}{}
+		defer func() { <-s.unpackSlots }()
+		prev, err := s.db.ClaimExtraction(fileID, unpackLease)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				log.Printf("Extraction error: file_id=%s, error=%v", fileID, err)
+			}
+			return
+		}
+
+		// Keep the lease while working; large archives take a while
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(unpackLease / 3)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					if err := s.db.RenewExtraction(fileID, unpackLease); err != nil {
+						log.Printf("Extraction lease error: file_id=%s, error=%v", fileID, err)
+					}
+				}
+			}
+		}()
+
+		ctx := context.Background()
+		x := &Extraction{FileID: fileID, Status: "completed"}
+		rec, err := s.db.GetUploadByID(fileID)
+		if err == nil && prev.FolderID != "" {
+			log.Printf("Extraction resumed: file_id=%s, undoing folder_id=%s", fileID, prev.FolderID)
+			err = s.undoUnpack(ctx, rec, prev.FolderID)
+		}
+		if err == nil {
+			err = s.unpack(ctx, rec, x)
+		}
+		result := "ok"
+		if err != nil {
+			x.Status, x.Error, x.FolderID, x.Files = "failed", err.Error(), "", 0
+			result = "failed"
+			log.Printf("Extraction failed: file_id=%s, error=%v", fileID, err)
+		} else {
+			log.Printf("Extraction success: file_id=%s, folder_id=%s, files=%d, skipped=%d", fileID, x.FolderID, x.Files, x.Skipped)
+		}
+		if err := s.db.FinishExtraction(x); err != nil {
+			log.Printf("Extraction error: file_id=%s, error=%v", fileID, err)
+		}
+		actor := retentionSystemActor
+		if rec != nil {
+			actor = rec.UserID
+		}
+		s.auditAs(ctx, actor, "extract", fileID, result, fmt.Sprintf("files=%d, skipped=%d", x.Files, x.Skipped))
+	}()
+}
+
+// ResumeExtractions restarts the extractions of every tenant that no server
+// is working on, e.g. after a restart
+func (s *UploadService) ResumeExtractions() {
+	s.eachTenant(func(ts *UploadService) {
+		ids, err := ts.db.UnfinishedExtractions()
+		if err != nil {
+			log.Printf("Extraction resume error: tenant=%s, error=%v", ts.tenant.ID, err)
+			return
+		}
+		for _, id := range ids {
+			ts.unpackArchive(id)
+		}
+	})
+}
+
+// undoUnpack removes the folder an extraction of rec created, with the
+// uploads it stored there
+func (s *UploadService) undoUnpack(ctx context.Context, rec *UploadRecord, folderID string) error {
+	ids, err := s.db.SubtreeFileIDs(folderID)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		r, err := s.db.GetUploadByID(id)
+		if err != nil {
+			continue
+		}
+		if err := s.removeUpload(ctx, r); err != nil {
+			log.Printf("Extraction cleanup error: file_id=%s, error=%v", id, err)
+		}
+	}
+	if err := s.db.DeleteFolder(rec.UserID, folderID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+// unpack extracts the archive rec into a new folder next to it, recording
+// the folder and counts in x. On failure it removes what it created.
+func (s *UploadService) unpack(ctx context.Context, rec *UploadRecord, x *Extraction) (err error) {
+	if rec.Status != "completed" {
+		return fmt.Errorf("upload is %s", rec.Status)
+	}
+	content, err := s.openUpload(rec)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	head := make([]byte, unpackSniffSize)
+	n, err := content.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	kind := archiveKind(head[:n])
+	if kind == "" {
+		return errors.New("not a zip, tar or tar.gz archive")
+	}
+	total, err := planUnpack(kind, content, content.Size())
+	if err != nil {
+		return err
+	}
+	if err := s.checkQuota(total); err != nil {
+		return err
+	}
+
+	root, err := s.createUnpackFolder(rec)
+	if err != nil {
+		return err
+	}
+	x.FolderID = root.ID
+	var created []*UploadRecord
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, r := range created {
+			if rerr := s.removeUpload(ctx, r); rerr != nil {
+				log.Printf("Extraction cleanup error: file_id=%s, error=%v", r.FileID, rerr)
+			}
+		}
+		if rerr := s.db.DeleteFolder(rec.UserID, root.ID); rerr != nil {
+			log.Printf("Extraction cleanup error: folder_id=%s, error=%v", root.ID, rerr)
+		}
+	}()
+	if err := s.db.SetExtractionFolder(rec.FileID, root.ID); err != nil {
+		return err
+	}
+
+	folders := map[string]string{"": root.ID}
+	folderFor := func(segs []string) (string, error) {
+		path := ""
+		for _, seg := range segs {
+			parentID := folders[path]
+			path += "/" + seg
+			if _, ok := folders[path]; ok {
+				continue
+			}
+			f := &Folder{ID: uuid.NewString(), OwnerID: rec.UserID, ParentID: parentID, Name: seg}
+			if err := s.db.CreateFolder(f); err != nil {
+				return "", err
+			}
+			folders[path] = f.ID
+		}
+		return folders[path], nil
+	}
+
+	err = walkArchive(kind, content, content.Size(), true, func(m *archiveMember, data io.Reader) error {
+		segs, ok := unpackPath(m.name)
+		if !ok || m.kind == memberLink || m.kind == memberOther {
+			if m.kind != memberDir {
+				x.Skipped++
+			}
+			log.Printf("Extraction skipped member: file_id=%s, name=%q", rec.FileID, m.name)
+			return nil
+		}
+		if m.kind == memberDir {
+			_, err := folderFor(segs)
+			return err
+		}
+		folderID, err := folderFor(segs[:len(segs)-1])
+		if err != nil {
+			return err
+		}
+		f, stored, err := s.unpackFile(ctx, rec, folderID, segs[len(segs)-1], m.size, data)
+		if f != nil {
+			created = append(created, f)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", m.name, err)
+		}
+		if stored {
+			x.Files++
+		} else {
+			x.Skipped++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// Members are announced like any completed upload, once the extraction
+	// can no longer be rolled back
+	for _, r := range created {
+		if r.Status == "completed" {
+			s.versionCompleted(ctx, r)
+		}
+	}
+	return nil
+}
+
+// createUnpackFolder creates the folder an archive is extracted into, named
+// after the archive and placed next to it
+func (s *UploadService) createUnpackFolder(rec *UploadRecord) (*Folder, error) {
+	base := rec.FileName
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip", ".tar"} {
+		if len(base) > len(ext) && strings.EqualFold(base[len(base)-len(ext):], ext) {
+			base = base[:len(base)-len(ext)]
+			break
+		}
+	}
+	if _, err := folderName(base); err != nil {
+		base = "archive"
+	}
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = base + " (" + strconv.Itoa(i) + ")"
+		}
+		f := &Folder{ID: uuid.NewString(), OwnerID: rec.UserID, ParentID: rec.FolderID, Name: name}
+		err := s.db.CreateFolder(f)
+		if err == nil {
+			return f, nil
+		}
+		if pgCode(err) != pgUniqueViolation || i >= 100 {
+			return nil, err
+		}
+	}
+}
+
+// unpackFile stores one member of an archive as a completed upload in
+// folderID. Members refused by the content policy are skipped and infected
+// ones are quarantined; both report false. unpack announces completed
+// members once the whole archive is extracted. The upload is returned whenever
+// one was created, so failed extractions can remove it.
+func (s *UploadService) unpackFile(ctx context.Context, archive *UploadRecord, folderID, name string, size int64, data io.Reader) (*UploadRecord, bool, error) {
+	rules := s.policy.rulesFor(archive.UserID)
+	if err := rules.checkName(name); err != nil {
+		return nil, false, nil
+	}
+	if err := s.checkFileSize(size); err != nil {
+		return nil, false, nil
+	}
+	br := bufio.NewReaderSize(data, unpackSniffSize)
+	head, _ := br.Peek(unpackSniffSize)
+	detected, err := rules.checkContent(name, head)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	rec := &UploadRecord{FileID: uuid.NewString(), UserID: archive.UserID, FileName: name, FolderID: folderID}
+	if s.keys != nil {
+		if rec.KeyID, rec.WrappedKey, err = newDataKey(s.keys); err != nil {
+			return nil, false, err
+		}
+	}
+	key, err := s.dataKey(rec)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.db.CreateUpload(rec, 1); err != nil {
+		return nil, false, err
+	}
+	if err := s.db.SetMimeType(rec.FileID, detected); err != nil {
+		return rec, false, err
+	}
+
+	// Members never yield more than their declared size; see walkArchive
+	finalPath, sum, n, err := s.writeContent(rec, br, key)
+	if err != nil {
+		return rec, false, err
+	}
+	if n != size {
+		os.Remove(finalPath)
+		return rec, false, fmt.Errorf("member holds %d bytes instead of %d", n, size)
+	}
+
+	result, err := s.scanUpload(ctx, rec.FileID, finalPath, key)
+	if err != nil {
+		return rec, false, fmt.Errorf("malware scan failed: %w", err)
+	}
+	if result != nil && !result.Clean {
+		quarantined, err := s.quarantine(finalPath)
+		if err != nil {
+			return rec, false, err
+		}
+		rec.StoredPath = quarantined
+		if err := s.db.QuarantineUpload(rec.FileID, quarantined); err != nil {
+			return rec, false, err
+		}
+		rec.Status = "quarantined"
+		s.uploadQuarantined(rec.FileID, result.Signature)
+		log.Printf("Extraction quarantined member: file_id=%s, signature=%s", rec.FileID, result.Signature)
+		return rec, false, nil
+	}
+
+	blob, err := s.db.CommitBlob(rec, finalPath, s.tempDir, sum, n)
+	if err != nil {
+		os.Remove(finalPath)
+		return rec, false, err
+	}
+	rec.StoredPath, rec.SHA256, rec.Status = blob.StoredPath, sum, "completed"
+	s.auditAs(ctx, archive.UserID, "complete", rec.FileID, "ok", "extracted_from="+archive.FileID)
+	return rec, true, nil
+}
+
+// extractionState returns the extraction of an upload for GetUploadMetadata,
+// or nil when it was not uploaded with extract
+func (s *UploadService) extractionState(fileID string) *pb.ArchiveExtraction {
+	x, err := s.db.GetExtraction(fileID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("GetUploadMetadata extraction error: file_id=%s, error=%v", fileID, err)
+		}
+		return nil
+	}
+	return extractionToPB(x)
+}
@@ -0,0 +1,309 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnpackPath(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+		ok   bool
+	}{
+		{"file.txt", []string{"file.txt"}, true},
+		{"docs/a/file.txt", []string{"docs", "a", "file.txt"}, true},
+		{"docs//./file.txt", []string{"docs", "file.txt"}, true},
+		{"docs/", []string{"docs"}, true},
+		{`docs\file.txt`, []string{"docs", "file.txt"}, true},
+		{"docs/a:b.txt", []string{"docs", "a_b.txt"}, true},
+		{"docs/..../file.txt", []string{"docs", "__", "file.txt"}, true},
+		{"docs/bad\x00name", []string{"docs", "bad_name"}, true},
+
+		// zip-slip and absolute names
+		{"../evil.sh", nil, false},
+		{"docs/../../evil.sh", nil, false},
+		{"docs/..", nil, false},
+		{`..\evil.exe`, nil, false},
+		{`docs\..\..\evil.exe`, nil, false},
+		{"/etc/passwd", nil, false},
+		{`\Windows\System32\evil.dll`, nil, false},
+		{"C:/Windows/evil.dll", nil, false},
+		{`C:\Windows\evil.dll`, nil, false},
+		{"c:evil.dll", nil, false},
+
+		// nothing left, or not content
+		{"", nil, false},
+		{".", nil, false},
+		{"./", nil, false},
+		{"__MACOSX/._file.txt", nil, false},
+
+		// too deep or too long
+		{strings.Repeat("d/", maxFolderDepth-1) + "f", splitRepeat("d", maxFolderDepth-1, "f"), true},
+		{strings.Repeat("d/", maxFolderDepth) + "f", nil, false},
+		{strings.Repeat("a", 255), []string{strings.Repeat("a", 255)}, true},
+		{"docs/" + strings.Repeat("a", 256), nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := unpackPath(tt.name)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("unpackPath(%.40q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// splitRepeat returns n copies of seg followed by last
+func splitRepeat(seg string, n int, last string) []string {
+	segs := make([]string, 0, n+1)
+	for range n {
+		segs = append(segs, seg)
+	}
+	return append(segs, last)
+}
+
+func TestArchiveKind(t *testing.T) {
+	ustar := make([]byte, 512)
+	copy(ustar[257:], "ustar\x0000")
+
+	tests := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"zip", []byte("PK\x03\x04rest"), "zip"},
+		{"empty zip", []byte("PK\x05\x06rest"), "zip"},
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00}, "tar.gz"},
+		{"tar", ustar, "tar"},
+		{"tar cut short", ustar[:261], ""},
+		{"spanned zip", []byte("PK\x07\x08"), ""},
+		{"text", []byte("hello world"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tt := range tests {
+		if got := archiveKind(tt.head); got != tt.want {
+			t.Errorf("%s: archiveKind = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// rawMember is a zip member written as is, so its declared sizes need not
+// match its data
+type rawMember struct {
+	name         string
+	size, packed uint64
+	data         []byte
+	dir          bool
+}
+
+func buildZip(t *testing.T, members ...rawMember) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, m := range members {
+		h := &zip.FileHeader{
+			Name:               m.name,
+			Method:             zip.Deflate,
+			UncompressedSize64: m.size,
+			CompressedSize64:   m.packed,
+		}
+		if m.dir {
+			h.Method = zip.Store
+		}
+		w, err := zw.CreateRaw(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(m.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildTar(t *testing.T, gz bool, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var zw *gzip.Writer
+	if gz {
+		zw = gzip.NewWriter(&buf)
+		w = zw
+	}
+	tw := tar.NewWriter(w)
+	for _, name := range []string{"a.txt", "dir/b.txt"} {
+		body, ok := files[name]
+		if !ok {
+			continue
+		}
+		h := &tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tw, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "dir/", Mode: 0755, Typeflag: tar.TypeDir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "link", Linkname: "/etc/passwd", Typeflag: tar.TypeSymlink}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// paddedArchive puts pad bytes in front of an archive, which zip readers
+// skip like the stub of a self-extracting archive. It makes a small archive
+// as large as the size caps need without allocating it. Zip64 archives are
+// not found behind a stub, so members must declare less than 4 GiB.
+type paddedArchive struct {
+	pad  int64
+	data []byte
+}
+
+func (p *paddedArchive) ReadAt(b []byte, off int64) (int, error) {
+	n := 0
+	for n < len(b) && off+int64(n) < p.pad {
+		b[n] = 0
+		n++
+	}
+	if n == len(b) {
+		return n, nil
+	}
+	pos := off + int64(n) - p.pad
+	if pos >= int64(len(p.data)) {
+		return n, io.EOF
+	}
+	m := copy(b[n:], p.data[pos:])
+	if n+m < len(b) {
+		return n + m, io.EOF
+	}
+	return n + m, nil
+}
+
+func (p *paddedArchive) Size() int64 { return p.pad + int64(len(p.data)) }
+
+func TestPlanUnpack(t *testing.T) {
+	const mib = 1 << 20
+	files := map[string]string{"a.txt": "hello", "dir/b.txt": "world!"}
+
+	manyDirs := make([]rawMember, maxUnpackEntries+1)
+	for i := range manyDirs {
+		manyDirs[i] = rawMember{name: strings.Repeat("d", i%50+1) + "/", dir: true}
+	}
+
+	tests := []struct {
+		name    string
+		kind    string
+		archive io.ReaderAt
+		want    int64
+		limit   bool // errUnpackLimit expected
+	}{
+		{
+			name:    "zip",
+			kind:    "zip",
+			archive: bytes.NewReader(buildZip(t, rawMember{name: "a.txt", size: 5, packed: 3, data: []byte("abc")}, rawMember{name: "dir/", dir: true})),
+			want:    5,
+		},
+		{name: "tar", kind: "tar", archive: bytes.NewReader(buildTar(t, false, files)), want: 11},
+		{name: "tar.gz", kind: "tar.gz", archive: bytes.NewReader(buildTar(t, true, files)), want: 11},
+		{
+			// Small members are not held to the ratio, however well they compress
+			name:    "small member compressed well",
+			kind:    "zip",
+			archive: bytes.NewReader(buildZip(t, rawMember{name: "zeros", size: unpackRatioFloor, packed: 1, data: []byte{0}})),
+			want:    unpackRatioFloor,
+		},
+		{
+			name:    "member over ratio",
+			kind:    "zip",
+			archive: bytes.NewReader(buildZip(t, rawMember{name: "bomb", size: unpackRatioFloor + 1, packed: 1, data: []byte{0}})),
+			limit:   true,
+		},
+		{
+			name:    "member over ratio behind a stub",
+			kind:    "zip",
+			archive: &paddedArchive{pad: 100 * mib, data: buildZip(t, rawMember{name: "bomb", size: 200 * mib, packed: 1, data: []byte{0}})},
+			limit:   true,
+		},
+		{
+			// Each member stays within the ratio, the archive as a whole does not
+			name: "archive over ratio",
+			kind: "zip",
+			archive: bytes.NewReader(buildZip(t,
+				rawMember{name: "a", size: 50 * mib, packed: mib, data: []byte{0}},
+				rawMember{name: "b", size: 50 * mib, packed: mib, data: []byte{0}},
+			)),
+			limit: true,
+		},
+		{
+			name: "over total size",
+			kind: "zip",
+			archive: &paddedArchive{pad: 200 * mib, data: buildZip(t,
+				rawMember{name: "a", size: maxUnpackSize / 4, packed: 100 * mib, data: []byte{0}},
+				rawMember{name: "b", size: maxUnpackSize / 4, packed: 100 * mib, data: []byte{0}},
+				rawMember{name: "c", size: maxUnpackSize / 4, packed: 100 * mib, data: []byte{0}},
+				rawMember{name: "d", size: maxUnpackSize/4 + 1, packed: 100 * mib, data: []byte{0}},
+			)},
+			limit: true,
+		},
+		{
+			name: "at total size",
+			kind: "zip",
+			archive: &paddedArchive{pad: 200 * mib, data: buildZip(t,
+				rawMember{name: "a", size: maxUnpackSize / 4, packed: 100 * mib, data: []byte{0}},
+				rawMember{name: "b", size: maxUnpackSize / 4, packed: 100 * mib, data: []byte{0}},
+				rawMember{name: "c", size: maxUnpackSize / 4, packed: 100 * mib, data: []byte{0}},
+				rawMember{name: "d", size: maxUnpackSize / 4, packed: 100 * mib, data: []byte{0}},
+			)},
+			want: maxUnpackSize,
+		},
+		{name: "too many members", kind: "zip", archive: bytes.NewReader(buildZip(t, manyDirs...)), limit: true},
+	}
+	for _, tt := range tests {
+		var size int64
+		switch a := tt.archive.(type) {
+		case *bytes.Reader:
+			size = a.Size()
+		case *paddedArchive:
+			size = a.Size()
+		}
+		got, err := planUnpack(tt.kind, tt.archive, size)
+		if tt.limit {
+			if !errors.Is(err, errUnpackLimit) {
+				t.Errorf("%s: planUnpack error = %v, want errUnpackLimit", tt.name, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: planUnpack = %d, %v; want %d, nil", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestPlanUnpackCorrupt(t *testing.T) {
+	for _, kind := range []string{"zip", "tar.gz"} {
+		_, err := planUnpack(kind, strings.NewReader("not an archive"), 14)
+		if err == nil || errors.Is(err, errUnpackLimit) {
+			t.Errorf("%s: planUnpack error = %v, want a format error", kind, err)
+		}
+	}
+}
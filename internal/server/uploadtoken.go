@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+const (
+	uploadTokenType       = "upload"
+	defaultUploadTokenTTL = 15 * time.Minute
+	maxUploadTokenTTL     = 24 * time.Hour
+)
+
+// uploadClaims scope a presigned upload token to a single file
+type uploadClaims struct {
+	Type      string   `json:"typ"`
+	FileID    string   `json:"file_id"`
+	MaxSize   int64    `json:"max_size,omitempty"`
+	MIMETypes []string `json:"mime_types,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// issueUploadToken signs an upload token for fileID
+func issueUploadToken(fileID, issuer string, maxSize int64, mimeTypes []string, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	claims := uploadClaims{
+		Type:      uploadTokenType,
+		FileID:    fileID,
+		MaxSize:   maxSize,
+		MIMETypes: mimeTypes,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   issuer,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+	return signed, expiresAt, err
+}
+
+// parseUploadToken validates an upload token and returns its claims
+func parseUploadToken(tokenString string) (*uploadClaims, error) {
+	var claims uploadClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid signing method")
+		}
+		return jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid upload token")
+	}
+	if claims.Type != uploadTokenType || claims.FileID == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid upload token")
+	}
+	return &claims, nil
+}
+
+func uploadTokenFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if v := md.Get("x-upload-token"); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// authorizeUpload accepts either a bearer JWT for the file owner or an upload
+// token scoped to the file. The returned claims are nil for JWT callers.
+func (s *UploadService) authorizeUpload(ctx context.Context, rec *UploadRecord) (*uploadClaims, error) {
+	if tok := uploadTokenFromContext(ctx); tok != "" {
+		claims, err := parseUploadToken(tok)
+		if err != nil {
+			return nil, err
+		}
+		if claims.FileID != rec.FileID {
+			return nil, status.Error(codes.PermissionDenied, "upload token is not valid for this file")
+		}
+		if rec.Status == "completed" {
+			return nil, status.Error(codes.FailedPrecondition, "upload already completed")
+		}
+		return claims, nil
+	}
+
+	userID, err := s.validateJWT(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rec.UserID != userID {
+		return nil, status.Error(codes.PermissionDenied, "not the owner of this upload")
+	}
+	return nil, nil
+}
+
+// maxSize returns the most bytes the token lets an upload receive, or 0
+func (c *uploadClaims) maxSize() int64 {
+	if c == nil {
+		return 0
+	}
+	return c.MaxSize
+}
+
+// checkChunk enforces the token's MIME restrictions. Its size limit is
+// enforced by saveChunk, see maxSize.
+func (c *uploadClaims) checkChunk(chunk *pb.FileChunk) error {
+	if c == nil {
+		return nil
+	}
+	if chunk.ChunkIndex == 0 && len(c.MIMETypes) > 0 {
+		detected := sniffContentType(chunk.Content)
+		if !mimeAllowed(detected, c.MIMETypes) {
+			return status.Errorf(codes.InvalidArgument, "content type %s is not allowed", detected)
+		}
+	}
+	return nil
+}
+
+// mimeAllowed matches a MIME type against entries like "image/png" or "image/*"
+func mimeAllowed(mimeType string, allowed []string) bool {
+	base, _, _ := strings.Cut(mimeType, ";")
+	base = strings.TrimSpace(strings.ToLower(base))
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == base || a == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(base, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
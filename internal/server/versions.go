@@ -0,0 +1,520 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// A file is its first upload (the root) plus any later uploads that name it
+// in version_of. The root's file_id identifies the file for grants, signed
+// URLs and downloads, and the newest completed version is the current one.
+// Restoring an old version adds a new version sharing its content.
+
+// VersionInfo describes one version of a file
+type VersionInfo struct {
+	FileID           string
+	Version          int64
+	FileName         string
+	Size             int64
+	Status           string
+	SHA256           string
+	UserID           string
+	CreatedAt        time.Time
+	RestoredFromVers int64
+}
+
+// ListVersions returns every version of a file except pruned ones, newest first
+func (db *UploadDB) ListVersions(rootID string) ([]*VersionInfo, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT u.file_id::text, u.version, u.file_name, COALESCE(u.size_bytes, 0), u.status, COALESCE(u.sha256, ''),
+		        COALESCE(u.user_id::text, ''), u.created_at, COALESCE(r.version, 0)
+		 FROM uploads u LEFT JOIN uploads r ON r.file_id = u.restored_from AND u.version_of IS NOT NULL
+		 WHERE (u.file_id = $1 OR u.version_of = $1) AND u.status <> 'pruned' AND u.deleted_at IS NULL
+		 ORDER BY u.version DESC`,
+		rootID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*VersionInfo
+	for rows.Next() {
+		var v VersionInfo
+		if err := rows.Scan(&v.FileID, &v.Version, &v.FileName, &v.Size, &v.Status, &v.SHA256, &v.UserID, &v.CreatedAt, &v.RestoredFromVers); err != nil {
+			return nil, err
+		}
+		versions = append(versions, &v)
+	}
+	return versions, rows.Err()
+}
+
+// VersionID returns the upload ID of a version of a file. Version 0 means
+// the newest completed version. It returns "" if there is no such version.
+func (db *UploadDB) VersionID(rootID string, version int64) (string, error) {
+	var id string
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT COALESCE((
+		   SELECT file_id::text FROM uploads
+		   WHERE (file_id = $1 OR version_of = $1)
+		     AND (($2 = 0 AND status = 'completed') OR ($2 <> 0 AND version = $2 AND status <> 'pruned'))
+		     AND deleted_at IS NULL
+		   ORDER BY version DESC LIMIT 1), '')`,
+		rootID, version,
+	).Scan(&id)
+	return id, err
+}
+
+// VersionIDsForDeletion returns the upload IDs of all versions of a file
+// other than the root
+func (db *UploadDB) VersionIDsForDeletion(rootID string) ([]string, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT file_id::text FROM uploads WHERE version_of = $1 ORDER BY version DESC`,
+		rootID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// PruneUpload releases the content of a version that has to keep its row,
+// i.e. the root of a file with newer versions, and marks it pruned
+func (db *UploadDB) PruneUpload(fileID string) error {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := releaseManifest(ctx, tx, fileID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM file_manifests WHERE file_id=$1`, fileID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM file_text WHERE file_id=$1`, fileID); err != nil {
+		return err
+	}
+	var sum *string
+	err = tx.QueryRow(ctx,
+		`UPDATE uploads u SET status='pruned', stored_path=NULL, sha256=NULL, manifest=false
+		 FROM (SELECT file_id, sha256 FROM uploads WHERE file_id=$1 FOR UPDATE) old
+		 WHERE u.file_id = old.file_id
+		 RETURNING old.sha256`,
+		fileID,
+	).Scan(&sum)
+	if err != nil {
+		return err
+	}
+	if sum != nil {
+		if err := releaseBlob(ctx, tx, *sum); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// CopyManifestUpload inserts a completed upload sharing the manifest of src,
+// taking another reference on each of its chunks, and sets rec.Version
+func (db *UploadDB) CopyManifestUpload(rec *UploadRecord, src *UploadRecord) error {
+	ctx := context.Background()
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO uploads(file_id, user_id, file_name, total_chunks, status, manifest, size_bytes, mime_type,
+		                     version_of, version, restored_from, folder_id, metadata, tags)
+		 SELECT $1, $2, $3, total_chunks, 'completed', true, size_bytes, mime_type,
+		        NULLIF($4::text, '')::uuid, `+fmt.Sprintf(nextVersionSQL, "$4")+`, NULLIF($5::text, '')::uuid,
+		        NULLIF($7::text, '')::uuid, `+metadataValuesSQL("$8", "$9")+`
+		 FROM uploads WHERE file_id = $6
+		 RETURNING version`,
+		rec.FileID, rec.UserID, rec.FileName, rec.VersionOf, rec.RestoredFrom, src.FileID, rec.FolderID,
+		rec.Metadata, rec.Tags,
+	).Scan(&rec.Version)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO file_manifests(file_id, seq, chunk_sha256, size_bytes)
+		 SELECT $1, seq, chunk_sha256, size_bytes FROM file_manifests WHERE file_id = $2`,
+		rec.FileID, src.FileID,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE content_chunks c SET ref_count = c.ref_count + m.n, unreferenced_at = NULL
+		 FROM (SELECT tenant_id, chunk_sha256, count(*) AS n FROM file_manifests WHERE file_id=$1 GROUP BY tenant_id, chunk_sha256) m
+		 WHERE c.tenant_id = m.tenant_id AND c.sha256 = m.chunk_sha256`,
+		rec.FileID,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// rootOf returns the first version of the file rec belongs to
+func (s *UploadService) rootOf(rec *UploadRecord) (*UploadRecord, error) {
+	if rec.VersionOf == "" {
+		return rec, nil
+	}
+	return s.db.GetUploadByID(rec.VersionOf)
+}
+
+// currentVersion returns the newest completed version of the file whose
+// root is rec, or rec itself when there is none
+func (s *UploadService) currentVersion(root *UploadRecord) (*UploadRecord, error) {
+	if root.VersionOf != "" {
+		return root, nil
+	}
+	id, err := s.db.VersionID(root.FileID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || id == root.FileID {
+		return root, nil
+	}
+	return s.db.GetUploadByID(id)
+}
+
+// resolveVersion returns a version of a file; version 0 means the current
+// one. A fileID naming a later version refers to that version directly.
+func (s *UploadService) resolveVersion(rec *UploadRecord, version int64) (*UploadRecord, error) {
+	if version == 0 {
+		return s.currentVersion(rec)
+	}
+	root, err := s.rootOf(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	id, err := s.db.VersionID(root.FileID, version)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	if id == "" {
+		return nil, status.Errorf(codes.NotFound, "version %d not found", version)
+	}
+	return s.db.GetUploadByID(id)
+}
+
+// contentID is the upload ID a version's content was first stored under,
+// which client-side encryption binds the content to
+func contentID(rec *UploadRecord) string {
+	if rec.RestoredFrom != "" {
+		return rec.RestoredFrom
+	}
+	return rec.FileID
+}
+
+// SetVersionRetention keeps at most n completed versions per file; 0 keeps all
+func (s *UploadService) SetVersionRetention(n int) {
+	s.versionRetention = n
+}
+
+// versionCompleted tells watchers and webhooks a file or version completed,
+// indexes its text, unpacks new files uploaded with extract and applies the retention rule
+func (s *UploadService) versionCompleted(ctx context.Context, rec *UploadRecord) {
+	s.publishEvent(&pb.UploadEvent{FileId: rec.FileID, Type: eventCompleted, Status: "completed"})
+	s.notifyWebhooks(webhookUploadCompleted, webhookData{FileID: rec.FileID})
+	s.indexText(rec.FileID)
+	if rec.VersionOf == "" {
+		s.unpackArchive(rec.FileID)
+		return
+	}
+	if s.versionRetention <= 0 {
+		return
+	}
+	versions, err := s.db.ListVersions(rec.VersionOf)
+	if err != nil {
+		log.Printf("Version retention error: file_id=%s, error=%v", rec.VersionOf, err)
+		return
+	}
+	kept := 0
+	for _, v := range versions {
+		if v.Status != "completed" {
+			continue
+		}
+		if kept < s.versionRetention {
+			kept++
+			continue
+		}
+		if err := s.pruneVersion(ctx, v.FileID); err != nil {
+			log.Printf("Version retention error: file_id=%s, version=%d, error=%v", rec.VersionOf, v.Version, err)
+			continue
+		}
+		log.Printf("Version pruned: file_id=%s, version=%d", rec.VersionOf, v.Version)
+	}
+}
+
+// pruneVersion drops one old version. The root keeps its row, since it
+// identifies the file, and only loses its content.
+func (s *UploadService) pruneVersion(ctx context.Context, fileID string) error {
+	rec, err := s.db.GetUploadByID(fileID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkRetention(ctx, retentionSystemActor, "purge", fileID); err != nil {
+		return err
+	}
+	if rec.VersionOf != "" {
+		return s.removeUpload(ctx, rec)
+	}
+	if rec.StoredPath != "" && rec.SHA256 == "" {
+		os.Remove(rec.StoredPath)
+	}
+	return s.db.PruneUpload(fileID)
+}
+
+// removeUpload deletes one upload row with its temp data and releases its content
+func (s *UploadService) removeUpload(ctx context.Context, rec *UploadRecord) error {
+	// Delete the physical file unless it is a shared blob, which is released below
+	if rec.StoredPath != "" && rec.SHA256 == "" {
+		if err := os.Remove(rec.StoredPath); err != nil {
+			log.Printf("DeleteFile remove error: file_id=%s, path=%s, error=%v", rec.FileID, rec.StoredPath, err)
+		}
+	}
+
+	// Delete temp directory if it exists
+	tmpDir, _, _ := paths(s.tempDir, rec.FileID, rec.FileName)
+	os.RemoveAll(tmpDir)
+
+	// Clean up Redis keys
+	cleanupChunks(ctx, s.rdb, rec.FileID)
+
+	// Delete from database and drop the blob reference
+	return s.db.DeleteUpload(rec.FileID)
+}
+
+// cloneContent creates the completed upload rec sharing the content of src,
+// for restored versions and copies
+func (s *UploadService) cloneContent(rec, src *UploadRecord) error {
+	if src.Manifest {
+		return s.db.CopyManifestUpload(rec, src)
+	}
+	if src.SHA256 != "" {
+		blob := &Blob{SHA256: src.SHA256, Size: src.Size, StoredPath: src.StoredPath, KeyID: src.KeyID, WrappedKey: src.WrappedKey}
+		ok, err := s.db.CreateBlobUpload(rec, blob, 1, src.MimeType)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+
+	// Files stored before content-addressed storage are copied into a blob
+	if s.keys != nil {
+		var err error
+		if rec.KeyID, rec.WrappedKey, err = newDataKey(s.keys); err != nil {
+			return err
+		}
+	}
+	key, err := s.dataKey(rec)
+	if err != nil {
+		return err
+	}
+	if err := s.db.CreateUpload(rec, 1); err != nil {
+		return err
+	}
+	if err := s.copyIntoBlob(rec, src, key); err != nil {
+		s.db.DeleteUpload(rec.FileID)
+		return err
+	}
+	return nil
+}
+
+// copyIntoBlob writes the content of src as the content of the new upload
+// rec and completes it
+func (s *UploadService) copyIntoBlob(rec, src *UploadRecord, key []byte) error {
+	content, err := s.openUpload(src)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	finalPath, sum, size, err := s.writeContent(rec, io.NewSectionReader(content, 0, content.Size()), key)
+	if err != nil {
+		return err
+	}
+	if src.MimeType != "" {
+		s.db.SetMimeType(rec.FileID, src.MimeType)
+	}
+	if _, err := s.db.CommitBlob(rec, finalPath, s.tempDir, sum, size); err != nil {
+		os.Remove(finalPath)
+		return err
+	}
+	return nil
+}
+
+// writeContent stores what r yields at the final path of the new upload rec,
+// encrypted when key is set. It returns the path and the SHA-256 and size
+// of the plaintext.
+func (s *UploadService) writeContent(rec *UploadRecord, r io.Reader, key []byte) (string, string, int64, error) {
+	_, finalPath, _ := paths(s.tempDir, rec.FileID, rec.FileName)
+	if err := os.MkdirAll(filepath.Dir(finalPath), 0755); err != nil {
+		return "", "", 0, err
+	}
+	f, err := os.Create(finalPath)
+	if err != nil {
+		return "", "", 0, err
+	}
+	var w io.Writer = f
+	var sw *segmentWriter
+	if key != nil {
+		if sw, err = newSegmentWriter(f, key, fileStreamID); err != nil {
+			f.Close()
+			os.Remove(finalPath)
+			return "", "", 0, err
+		}
+		w = sw
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, hash), r)
+	if sw != nil && err == nil {
+		err = sw.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(finalPath)
+		return "", "", 0, err
+	}
+	return finalPath, hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func versionToPB(rootID string, v *VersionInfo) *pb.FileVersion {
+	return &pb.FileVersion{
+		FileId:       rootID,
+		Version:      v.Version,
+		VersionId:    v.FileID,
+		FileName:     v.FileName,
+		Size:         v.Size,
+		Status:       v.Status,
+		Sha256:       v.SHA256,
+		UploadedBy:   v.UserID,
+		CreatedAt:    v.CreatedAt.Unix(),
+		RestoredFrom: v.RestoredFromVers,
+	}
+}
+
+// ListVersions lists the versions of a file
+func (s *UploadService) ListVersions(ctx context.Context, req *pb.ListVersionsRequest) (*pb.ListVersionsResponse, error) {
+	s = s.scoped(ctx)
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	if _, err := s.authorize(ctx, rec, permRead); err != nil {
+		return nil, err
+	}
+	root, err := s.rootOf(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+
+	versions, err := s.db.ListVersions(root.FileID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	resp := &pb.ListVersionsResponse{}
+	for _, v := range versions {
+		if resp.CurrentVersion == 0 && v.Status == "completed" {
+			resp.CurrentVersion = v.Version
+		}
+		resp.Versions = append(resp.Versions, versionToPB(root.FileID, v))
+	}
+	return resp, nil
+}
+
+// RestoreVersion makes an old version current again by adding it as a new version
+func (s *UploadService) RestoreVersion(ctx context.Context, req *pb.RestoreVersionRequest) (*pb.FileVersion, error) {
+	s = s.scoped(ctx)
+	rec, err := s.db.GetUploadByID(req.FileId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+	c, err := s.authorize(ctx, rec, permWrite)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, status.Error(codes.PermissionDenied, "restoring versions requires a signed-in user")
+	}
+	if req.Version <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "version must be positive")
+	}
+	src, err := s.resolveVersion(rec, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	if src.Status != "completed" {
+		return nil, status.Errorf(codes.FailedPrecondition, "version %d is %s", req.Version, src.Status)
+	}
+	if err := s.checkQuota(src.Size); err != nil {
+		return nil, err
+	}
+	root, err := s.rootOf(src)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "file not found: %v", err)
+	}
+
+	restored := &UploadRecord{
+		FileID:           uuid.NewString(),
+		UserID:           c.UserID,
+		FileName:         src.FileName,
+		VersionOf:        root.FileID,
+		RestoredFrom:     contentID(src),
+		ClientEncScheme:  src.ClientEncScheme,
+		ClientWrappedKey: src.ClientWrappedKey,
+		ClientKeyID:      src.ClientKeyID,
+	}
+	if err := s.cloneContent(restored, src); err != nil {
+		log.Printf("RestoreVersion error: file_id=%s, version=%d, error=%v", root.FileID, req.Version, err)
+		return nil, status.Errorf(codes.Internal, "failed to restore version: %v", err)
+	}
+	s.versionCompleted(ctx, restored)
+
+	log.Printf("RestoreVersion success: file_id=%s, restored=%d, new_version=%d", root.FileID, req.Version, restored.Version)
+	return &pb.FileVersion{
+		FileId:       root.FileID,
+		Version:      restored.Version,
+		VersionId:    restored.FileID,
+		FileName:     restored.FileName,
+		Size:         src.Size,
+		Status:       "completed",
+		Sha256:       src.SHA256,
+		UploadedBy:   c.UserID,
+		CreatedAt:    time.Now().Unix(),
+		RestoredFrom: src.Version,
+	}, nil
+}
@@ -0,0 +1,703 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"upload-backend/pb"
+)
+
+// Webhook events are queued as one delivery per subscribed webhook in the
+// same database as the files, then sent by RunWebhooks. A delivery that
+// fails is retried with exponential backoff until it becomes a dead letter.
+// Deliveries are at least once: receivers deduplicate by event ID.
+
+const (
+	webhookUploadCompleted = "upload.completed"
+	webhookUploadFailed    = "upload.failed"
+	webhookFileDeleted     = "file.deleted"
+
+	maxWebhooksPerUser     = 20
+	maxWebhookAttempts     = 10
+	webhookRetryBase       = 30 * time.Second
+	webhookMaxBackoff      = time.Hour
+	webhookTimeout         = 10 * time.Second
+	webhookLease           = time.Minute // longer than an attempt can take
+	webhookBatchSize       = 50
+	webhookConcurrency     = 8
+	webhookErrorLimit      = 1024 // bytes of a failed response kept as its error
+	defaultWebhookPageSize = 50
+	maxWebhookPageSize     = 500
+)
+
+var webhookEvents = []string{webhookUploadCompleted, webhookUploadFailed, webhookFileDeleted}
+
+// WebhookPolicy bounds where webhooks may be delivered
+type WebhookPolicy struct {
+	AllowedNets []*net.IPNet // blocked ranges, e.g. a local receiver, that may be used anyway
+}
+
+// webhookSender delivers webhook requests and wakes RunWebhooks when
+// events are queued
+type webhookSender struct {
+	client  *http.Client
+	allowed []*net.IPNet
+	wake    chan struct{}
+}
+
+func newWebhookSender(p WebhookPolicy) *webhookSender {
+	return &webhookSender{
+		client: &http.Client{
+			Transport: guardedTransport(p.AllowedNets),
+			Timeout:   webhookTimeout,
+			// A redirect could lead past the URL checked when the webhook was created
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		allowed: p.AllowedNets,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// SetWebhookPolicy configures where webhooks may be delivered
+func (s *UploadService) SetWebhookPolicy(p WebhookPolicy) {
+	wake := s.webhooks.wake
+	s.webhooks = newWebhookSender(p)
+	s.webhooks.wake = wake
+}
+
+// Webhook is a subscription of an HTTP endpoint to upload events
+type Webhook struct {
+	ID         string
+	OwnerID    string
+	URL        string
+	Secret     string
+	Events     []string
+	TenantWide bool
+	CreatedAt  time.Time
+}
+
+// WebhookDelivery is one event sent, or to be sent, to one webhook
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      string
+	EventID        string
+	EventType      string
+	FileID         string
+	Status         string // pending, delivered or dead
+	Attempts       int
+	ResponseStatus int
+	Error          string
+	Payload        string
+	CreatedAt      time.Time
+	NextAttemptAt  time.Time
+	DeliveredAt    *time.Time
+}
+
+// webhookJob is a claimed delivery with what is needed to send it
+type webhookJob struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// CreateWebhook stores a new webhook
+func (db *UploadDB) CreateWebhook(w *Webhook) error {
+	return db.pool.QueryRow(context.Background(),
+		`INSERT INTO webhooks (id, owner_id, url, secret, events, tenant_wide)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`,
+		w.ID, w.OwnerID, w.URL, w.Secret, w.Events, w.TenantWide,
+	).Scan(&w.CreatedAt)
+}
+
+// CountWebhooks returns how many webhooks a user has created
+func (db *UploadDB) CountWebhooks(ownerID string) (int, error) {
+	var n int
+	err := db.pool.QueryRow(context.Background(),
+		`SELECT count(*) FROM webhooks WHERE owner_id = $1`, ownerID,
+	).Scan(&n)
+	return n, err
+}
+
+const webhookColumns = `id::text, owner_id, url, secret, events, tenant_wide, created_at`
+
+func scanWebhook(row pgx.Row) (*Webhook, error) {
+	var w Webhook
+	if err := row.Scan(&w.ID, &w.OwnerID, &w.URL, &w.Secret, &w.Events, &w.TenantWide, &w.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// GetWebhook loads a webhook by ID
+func (db *UploadDB) GetWebhook(id string) (*Webhook, error) {
+	return scanWebhook(db.pool.QueryRow(context.Background(),
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+}
+
+// ListWebhooks returns the webhooks of a user, and with tenantWide also
+// every tenant-wide webhook
+func (db *UploadDB) ListWebhooks(ownerID string, tenantWide bool) ([]*Webhook, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT `+webhookColumns+` FROM webhooks
+		 WHERE owner_id = $1 OR (tenant_wide AND $2)
+		 ORDER BY created_at`,
+		ownerID, tenantWide,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hooks []*Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, w)
+	}
+	return hooks, rows.Err()
+}
+
+// DeleteWebhook removes a webhook with its deliveries
+func (db *UploadDB) DeleteWebhook(id string) error {
+	_, err := db.pool.Exec(context.Background(), `DELETE FROM webhooks WHERE id = $1`, id)
+	return err
+}
+
+// QueueWebhookEvent queues an event for every webhook of its tenant that
+// subscribes to it and covers files of ownerID, returning how many
+func (db *UploadDB) QueueWebhookEvent(tenantID, eventID, eventType, ownerID, fileID string, payload []byte) (int64, error) {
+	tag, err := db.pool.Exec(context.Background(),
+		`INSERT INTO webhook_deliveries (tenant_id, webhook_id, event_id, event_type, file_id, payload)
+		 SELECT tenant_id, id, $2, $3, NULLIF($5, '')::uuid, $6 FROM webhooks
+		 WHERE tenant_id = $1 AND $3 = ANY(events) AND (tenant_wide OR owner_id = $4)`,
+		tenantID, eventID, eventType, ownerID, fileID, payload,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ClaimWebhookDeliveries takes due deliveries and postpones them by lease,
+// so no other server sends them meanwhile
+func (db *UploadDB) ClaimWebhookDeliveries(limit int, lease time.Duration) ([]*webhookJob, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`UPDATE webhook_deliveries d SET next_attempt_at = now() + $2 * interval '1 second'
+		 FROM webhooks w
+		 WHERE w.id = d.webhook_id AND d.id IN (
+		     SELECT id FROM webhook_deliveries
+		     WHERE status = 'pending' AND next_attempt_at <= now()
+		     ORDER BY next_attempt_at LIMIT $1
+		     FOR UPDATE SKIP LOCKED)
+		 RETURNING d.id, d.webhook_id::text, d.event_id::text, d.event_type, d.payload::text, d.attempts, w.url, w.secret`,
+		limit, lease.Seconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var jobs []*webhookJob
+	for rows.Next() {
+		var j webhookJob
+		if err := rows.Scan(&j.ID, &j.WebhookID, &j.EventID, &j.EventType, &j.Payload, &j.Attempts, &j.URL, &j.Secret); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &j)
+	}
+	return jobs, rows.Err()
+}
+
+// RecordWebhookAttempt stores the outcome of sending a delivery. Pending
+// deliveries are tried again after retryIn.
+func (db *UploadDB) RecordWebhookAttempt(id int64, status string, responseStatus int, errMsg string, retryIn time.Duration) error {
+	_, err := db.pool.Exec(context.Background(),
+		`UPDATE webhook_deliveries
+		 SET attempts = attempts + 1, status = $2, response_status = $3, error = NULLIF($4, ''),
+		     next_attempt_at = now() + $5 * interval '1 second',
+		     delivered_at = CASE WHEN $2 = 'delivered' THEN now() END
+		 WHERE id = $1`,
+		id, status, responseStatus, errMsg, retryIn.Seconds(),
+	)
+	return err
+}
+
+const deliveryColumns = `id, webhook_id::text, event_id::text, event_type, COALESCE(file_id::text, ''), status, attempts,
+	response_status, COALESCE(error, ''), payload::text, created_at, next_attempt_at, delivered_at`
+
+func scanDelivery(row pgx.Row) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.FileID, &d.Status, &d.Attempts,
+		&d.ResponseStatus, &d.Error, &d.Payload, &d.CreatedAt, &d.NextAttemptAt, &d.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListWebhookDeliveries returns deliveries of a webhook older than cursor
+// (0 for the newest), optionally with one status, newest first
+func (db *UploadDB) ListWebhookDeliveries(webhookID, status string, cursor int64, limit int) ([]*WebhookDelivery, error) {
+	rows, err := db.pool.Query(context.Background(),
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries
+		 WHERE webhook_id = $1 AND ($2 = '' OR status = $2) AND ($3 = 0 OR id < $3)
+		 ORDER BY id DESC LIMIT $4`,
+		webhookID, status, cursor, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var deliveries []*WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RequeueWebhookDelivery makes a delivery of a webhook due now with a fresh
+// set of attempts
+func (db *UploadDB) RequeueWebhookDelivery(webhookID string, id int64) (*WebhookDelivery, error) {
+	return scanDelivery(db.pool.QueryRow(context.Background(),
+		`UPDATE webhook_deliveries SET status = 'pending', attempts = 0, error = NULL, next_attempt_at = now()
+		 WHERE id = $1 AND webhook_id = $2
+		 RETURNING `+deliveryColumns,
+		id, webhookID,
+	))
+}
+
+// webhookPayload is the JSON body of a webhook request
+type webhookPayload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Tenant    string      `json:"tenant"`
+	Data      webhookData `json:"data"`
+}
+
+type webhookData struct {
+	FileID    string `json:"file_id"`
+	FileName  string `json:"file_name"`
+	OwnerID   string `json:"owner_id"`
+	Version   int64  `json:"version"`
+	VersionOf string `json:"version_of,omitempty"` // the file's first version, for later versions
+	Status    string `json:"status"`
+	Size      int64  `json:"size,omitempty"`
+	SHA256    string `json:"sha256,omitempty"`
+	MimeType  string `json:"mime_type,omitempty"`
+	Error     string `json:"error,omitempty"`      // upload.failed
+	DeletedBy string `json:"deleted_by,omitempty"` // file.deleted
+}
+
+// notifyWebhooks queues an event about a file for the webhooks subscribed
+// to it. data names the file and carries the event's own fields; the rest
+// is filled in from the file. Failures are logged and never fail the caller.
+func (s *UploadService) notifyWebhooks(eventType string, data webhookData) {
+	rec, err := s.db.getUpload(data.FileID, "")
+	if err != nil {
+		log.Printf("Webhook event error: type=%s, file_id=%s, error=%v", eventType, data.FileID, err)
+		return
+	}
+	data.FileName, data.OwnerID, data.Status = rec.FileName, rec.UserID, rec.Status
+	data.Version, data.VersionOf = rec.Version, rec.VersionOf
+	data.Size, data.SHA256, data.MimeType = rec.Size, rec.SHA256, rec.MimeType
+
+	tenantID := defaultTenantID
+	if s.tenant != nil {
+		tenantID = s.tenant.ID
+	}
+	p := webhookPayload{ID: uuid.NewString(), Type: eventType, CreatedAt: time.Now().UTC(), Tenant: tenantID, Data: data}
+	body, err := json.Marshal(p)
+	if err != nil {
+		log.Printf("Webhook event error: type=%s, file_id=%s, error=%v", eventType, data.FileID, err)
+		return
+	}
+	n, err := s.db.QueueWebhookEvent(tenantID, p.ID, eventType, rec.UserID, rec.FileID, body)
+	if err != nil {
+		log.Printf("Webhook event error: type=%s, file_id=%s, error=%v", eventType, data.FileID, err)
+		return
+	}
+	if n > 0 {
+		select {
+		case s.webhooks.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>" under secret
+func signWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff returns the wait after the given failed attempt
+func webhookBackoff(attempt int) time.Duration {
+	if attempt > 20 {
+		return webhookMaxBackoff
+	}
+	return min(webhookRetryBase<<(attempt-1), webhookMaxBackoff)
+}
+
+// RunWebhooks delivers queued webhook events of every tenant, as soon as
+// they are queued on this server and otherwise every interval
+func (s *UploadService) RunWebhooks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.webhooks.wake:
+		}
+		s.eachTenant(func(ts *UploadService) { ts.deliverWebhooks(ctx) })
+	}
+}
+
+// deliverWebhooks sends the due deliveries of the service's tenant
+func (s *UploadService) deliverWebhooks(ctx context.Context) {
+	for ctx.Err() == nil {
+		jobs, err := s.db.ClaimWebhookDeliveries(webhookBatchSize, webhookLease)
+		if err != nil {
+			log.Printf("Webhook delivery error: tenant_id=%s, error=%v", s.tenant.ID, err)
+			return
+		}
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, webhookConcurrency)
+		for _, j := range jobs {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				s.deliverWebhook(ctx, j)
+			}()
+		}
+		wg.Wait()
+		if len(jobs) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// deliverWebhook makes one attempt at a delivery and records its outcome
+func (s *UploadService) deliverWebhook(ctx context.Context, j *webhookJob) {
+	code, err := s.sendWebhook(ctx, j)
+	attempt := j.Attempts + 1
+	switch {
+	case err == nil:
+		if err := s.db.RecordWebhookAttempt(j.ID, "delivered", code, "", 0); err != nil {
+			log.Printf("Webhook delivery error: delivery_id=%d, error=%v", j.ID, err)
+		}
+		return
+	case attempt >= maxWebhookAttempts:
+		log.Printf("Webhook dead letter: delivery_id=%d, webhook_id=%s, event=%s, attempts=%d, error=%v", j.ID, j.WebhookID, j.EventType, attempt, err)
+		err = s.db.RecordWebhookAttempt(j.ID, "dead", code, err.Error(), 0)
+	default:
+		backoff := webhookBackoff(attempt)
+		log.Printf("Webhook retry: delivery_id=%d, webhook_id=%s, attempt=%d, backoff=%s, error=%v", j.ID, j.WebhookID, attempt, backoff, err)
+		err = s.db.RecordWebhookAttempt(j.ID, "pending", code, err.Error(), backoff)
+	}
+	if err != nil {
+		log.Printf("Webhook delivery error: delivery_id=%d, error=%v", j.ID, err)
+	}
+}
+
+// sendWebhook posts a delivery, signed with the webhook's secret. Any
+// response but a 2xx is an error.
+func (s *UploadService) sendWebhook(ctx context.Context, j *webhookJob) (int, error) {
+	body := []byte(j.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "upload-backend-webhooks/1")
+	req.Header.Set("X-Webhook-Id", j.EventID)
+	req.Header.Set("X-Webhook-Event", j.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(j.ID, 10))
+	req.Header.Set("X-Webhook-Signature", fmt.Sprintf("t=%d,v1=%s", ts, signWebhook(j.Secret, ts, body)))
+
+	resp, err := s.webhooks.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, webhookErrorLimit))
+		return resp.StatusCode, nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorLimit))
+	return resp.StatusCode, fmt.Errorf("endpoint responded %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+}
+
+// parseWebhookURL validates the endpoint of a new webhook
+func (s *UploadService) parseWebhookURL(raw string) (*url.URL, error) {
+	if len(raw) > maxImportURLLen {
+		return nil, status.Errorf(codes.InvalidArgument, "url exceeds %d bytes", maxImportURLLen)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, status.Error(codes.InvalidArgument, "url must be an absolute http or https URL")
+	}
+	if u.User != nil {
+		return nil, status.Error(codes.InvalidArgument, "credentials in the url are not supported")
+	}
+	// Names are checked once they resolve; literal addresses can be refused now
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if err := checkOutboundIP(ip, s.webhooks.allowed); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	u.Fragment = ""
+	return u, nil
+}
+
+// ownedWebhook loads a webhook the caller manages: their own, or a
+// tenant-wide one for administrators
+func (s *UploadService) ownedWebhook(ctx context.Context, id string) (*Webhook, error) {
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook_id")
+	}
+	w, err := s.db.GetWebhook(id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "webhook not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	if w.OwnerID != c.UserID && !(w.TenantWide && c.Admin) {
+		return nil, status.Error(codes.NotFound, "webhook not found")
+	}
+	return w, nil
+}
+
+func webhookToPB(w *Webhook) *pb.Webhook {
+	return &pb.Webhook{
+		Id:         w.ID,
+		Url:        w.URL,
+		Events:     w.Events,
+		TenantWide: w.TenantWide,
+		OwnerId:    w.OwnerID,
+		CreatedAt:  w.CreatedAt.Unix(),
+	}
+}
+
+func deliveryToPB(d *WebhookDelivery) *pb.WebhookDelivery {
+	out := &pb.WebhookDelivery{
+		Id:             d.ID,
+		WebhookId:      d.WebhookID,
+		EventId:        d.EventID,
+		EventType:      d.EventType,
+		FileId:         d.FileID,
+		Status:         d.Status,
+		Attempts:       int32(d.Attempts),
+		ResponseStatus: int32(d.ResponseStatus),
+		Error:          d.Error,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt.Unix(),
+	}
+	if d.Status == "pending" {
+		out.NextAttemptAt = d.NextAttemptAt.Unix()
+	}
+	if d.DeliveredAt != nil {
+		out.DeliveredAt = d.DeliveredAt.Unix()
+	}
+	return out
+}
+
+// CreateWebhook subscribes an endpoint to events of the caller's files, or
+// of every file in the tenant for administrators
+func (s *UploadService) CreateWebhook(ctx context.Context, req *pb.CreateWebhookRequest) (*pb.Webhook, error) {
+	s = s.scoped(ctx)
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.TenantWide && !c.Admin {
+		return nil, status.Errorf(codes.PermissionDenied, "admin role required for tenant-wide webhooks")
+	}
+	u, err := s.parseWebhookURL(req.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	events := req.Events
+	if len(events) == 0 {
+		events = webhookEvents
+	}
+	seen := map[string]bool{}
+	var subscribed []string
+	for _, e := range events {
+		known := false
+		for _, k := range webhookEvents {
+			known = known || e == k
+		}
+		if !known {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown event %q, expected one of %s", e, strings.Join(webhookEvents, ", "))
+		}
+		if !seen[e] {
+			seen[e] = true
+			subscribed = append(subscribed, e)
+		}
+	}
+
+	n, err := s.db.CountWebhooks(c.UserID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	if n >= maxWebhooksPerUser {
+		return nil, status.Errorf(codes.ResourceExhausted, "at most %d webhooks per user", maxWebhooksPerUser)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create secret: %v", err)
+	}
+	w := &Webhook{
+		ID:         uuid.NewString(),
+		OwnerID:    c.UserID,
+		URL:        u.String(),
+		Secret:     "whsec_" + hex.EncodeToString(secret),
+		Events:     subscribed,
+		TenantWide: req.TenantWide,
+	}
+	if err := s.db.CreateWebhook(w); err != nil {
+		log.Printf("CreateWebhook error: user_id=%s, error=%v", c.UserID, err)
+		return nil, status.Errorf(codes.Internal, "db insert error: %v", err)
+	}
+
+	log.Printf("CreateWebhook success: user_id=%s, webhook_id=%s, host=%s, events=%s, tenant_wide=%t",
+		c.UserID, w.ID, u.Host, strings.Join(subscribed, ","), w.TenantWide)
+	out := webhookToPB(w)
+	out.Secret = w.Secret
+	return out, nil
+}
+
+// ListWebhooks returns the caller's webhooks, and for administrators the
+// tenant-wide ones as well
+func (s *UploadService) ListWebhooks(ctx context.Context, req *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	s = s.scoped(ctx)
+	c, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := s.db.ListWebhooks(c.UserID, c.Admin)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	resp := &pb.ListWebhooksResponse{}
+	for _, w := range hooks {
+		resp.Webhooks = append(resp.Webhooks, webhookToPB(w))
+	}
+	return resp, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery history
+func (s *UploadService) DeleteWebhook(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.DeleteResponse, error) {
+	s = s.scoped(ctx)
+	w, err := s.ownedWebhook(ctx, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.DeleteWebhook(w.ID); err != nil {
+		return nil, status.Errorf(codes.Internal, "db delete error: %v", err)
+	}
+	log.Printf("DeleteWebhook success: webhook_id=%s", w.ID)
+	return &pb.DeleteResponse{Success: true, Message: "Webhook deleted"}, nil
+}
+
+// ListWebhookDeliveries returns the delivery history of a webhook
+func (s *UploadService) ListWebhookDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	s = s.scoped(ctx)
+	w, err := s.ownedWebhook(ctx, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Status {
+	case "", "pending", "delivered", "dead":
+	default:
+		return nil, status.Error(codes.InvalidArgument, "status must be pending, delivered or dead")
+	}
+	limit := int(req.PageSize)
+	if limit <= 0 {
+		limit = defaultWebhookPageSize
+	}
+	if limit > maxWebhookPageSize {
+		limit = maxWebhookPageSize
+	}
+	var cursor int64
+	if req.PageToken != "" {
+		if cursor, err = strconv.ParseInt(req.PageToken, 10, 64); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	deliveries, err := s.db.ListWebhookDeliveries(w.ID, req.Status, cursor, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db query error: %v", err)
+	}
+	resp := &pb.ListWebhookDeliveriesResponse{}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, deliveryToPB(d))
+	}
+	if len(deliveries) == limit {
+		resp.NextPageToken = strconv.FormatInt(deliveries[len(deliveries)-1].ID, 10)
+	}
+	return resp, nil
+}
+
+// RedeliverWebhook queues a delivery to be sent again with a fresh set of
+// retries, e.g. a dead letter once the endpoint is fixed
+func (s *UploadService) RedeliverWebhook(ctx context.Context, req *pb.RedeliverWebhookRequest) (*pb.WebhookDelivery, error) {
+	s = s.scoped(ctx)
+	w, err := s.ownedWebhook(ctx, req.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.db.RequeueWebhookDelivery(w.ID, req.DeliveryId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "delivery not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "db update error: %v", err)
+	}
+	select {
+	case s.webhooks.wake <- struct{}{}:
+	default:
+	}
+	log.Printf("RedeliverWebhook success: webhook_id=%s, delivery_id=%d", w.ID, d.ID)
+	return deliveryToPB(d), nil
+}
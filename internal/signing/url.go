@@ -0,0 +1,94 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrMissingParams = errors.New("missing signed url parameters")
+	ErrBadSignature  = errors.New("invalid signature")
+	ErrExpired       = errors.New("signed url expired")
+	ErrIPNotAllowed  = errors.New("client ip not allowed")
+)
+
+// URLParams are the values bound into a signed download URL
+type URLParams struct {
+	FileID       string
+	URLID        string
+	ExpiresAt    int64
+	MaxDownloads int64
+	AllowedIP    string
+}
+
+func (p URLParams) payload() string {
+	return fmt.Sprintf("download\n%s\n%s\n%d\n%d\n%s", p.FileID, p.URLID, p.ExpiresAt, p.MaxDownloads, p.AllowedIP)
+}
+
+// SignURL returns the hex HMAC-SHA256 signature for the given parameters
+func SignURL(secret []byte, p URLParams) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(p.payload()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyURL checks the signature, expiry and IP restriction of a signed URL.
+// The download limit is stateful and is enforced by the server.
+func VerifyURL(secret []byte, p URLParams, sig string, now time.Time, clientIP string) error {
+	if !hmac.Equal([]byte(SignURL(secret, p)), []byte(sig)) {
+		return ErrBadSignature
+	}
+	if now.Unix() > p.ExpiresAt {
+		return ErrExpired
+	}
+	if p.AllowedIP != "" {
+		allowed := net.ParseIP(p.AllowedIP)
+		client := net.ParseIP(clientIP)
+		if allowed == nil || client == nil || !allowed.Equal(client) {
+			return ErrIPNotAllowed
+		}
+	}
+	return nil
+}
+
+// Query encodes the parameters and signature as URL query values
+func (p URLParams) Query(sig string) url.Values {
+	q := url.Values{}
+	q.Set("id", p.URLID)
+	q.Set("exp", strconv.FormatInt(p.ExpiresAt, 10))
+	if p.MaxDownloads > 0 {
+		q.Set("max", strconv.FormatInt(p.MaxDownloads, 10))
+	}
+	if p.AllowedIP != "" {
+		q.Set("ip", p.AllowedIP)
+	}
+	q.Set("sig", sig)
+	return q
+}
+
+// ParseURL reads signed URL parameters back out of a request query
+func ParseURL(fileID string, q url.Values) (URLParams, string, error) {
+	p := URLParams{FileID: fileID, URLID: q.Get("id"), AllowedIP: q.Get("ip")}
+	sig := q.Get("sig")
+	if fileID == "" || p.URLID == "" || sig == "" || q.Get("exp") == "" {
+		return p, "", ErrMissingParams
+	}
+	exp, err := strconv.ParseInt(q.Get("exp"), 10, 64)
+	if err != nil {
+		return p, "", ErrMissingParams
+	}
+	p.ExpiresAt = exp
+	if m := q.Get("max"); m != "" {
+		if p.MaxDownloads, err = strconv.ParseInt(m, 10, 64); err != nil {
+			return p, "", ErrMissingParams
+		}
+	}
+	return p, sig, nil
+}
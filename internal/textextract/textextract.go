@@ -0,0 +1,351 @@
+// Package textextract pulls searchable plain text out of stored files: text
+// formats as they are, PDFs from their content streams and office documents
+// from the XML inside their zip containers. Extraction is best effort; it
+// aims to find the words of a document, not to reproduce its layout.
+package textextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupported is returned for formats text cannot be extracted from
+var ErrUnsupported = errors.New("textextract: unsupported format")
+
+// Bounds on the work done for one file, so hostile input cannot make
+// extraction use unbounded memory
+const (
+	maxDocumentSize = 64 << 20 // PDFs are read whole
+	maxInflatedSize = 16 << 20 // per PDF stream or zip entry
+	sniffSize       = 8 << 10
+)
+
+var textMIMETypes = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/javascript": true,
+	"application/x-yaml":     true,
+	"application/yaml":       true,
+	"application/x-sh":       true,
+	"application/sql":        true,
+}
+
+var textExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".rst": true, ".csv": true, ".tsv": true,
+	".json": true, ".xml": true, ".yaml": true, ".yml": true, ".toml": true, ".ini": true,
+	".log": true, ".html": true, ".htm": true, ".sql": true, ".sh": true, ".go": true,
+	".py": true, ".js": true, ".ts": true, ".java": true, ".c": true, ".h": true, ".rs": true,
+}
+
+// officeParts lists the zip entries holding the text of each office format
+var officeParts = map[string][]string{
+	".docx": {"word/document.xml", "word/header*.xml", "word/footer*.xml", "word/footnotes.xml"},
+	".xlsx": {"xl/sharedStrings.xml"},
+	".pptx": {"ppt/slides/slide*.xml"},
+	".odt":  {"content.xml"},
+	".ods":  {"content.xml"},
+	".odp":  {"content.xml"},
+}
+
+// Extract returns up to limit bytes of the text of a file, choosing the
+// format from its name, its detected MIME type and its first bytes.
+func Extract(name, mimeType string, r io.ReaderAt, size int64, limit int) (string, error) {
+	ext := strings.ToLower(path.Ext(name))
+	head := make([]byte, sniffSize)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	head = head[:n]
+
+	switch {
+	case ext == ".pdf" || bytes.HasPrefix(head, []byte("%PDF-")):
+		return extractPDF(r, size, limit)
+	case officeParts[ext] != nil:
+		return extractOffice(r, size, officeParts[ext], limit)
+	case isText(ext, mimeType, head):
+		return extractPlain(r, size, limit)
+	}
+	return "", ErrUnsupported
+}
+
+func isText(ext, mimeType string, head []byte) bool {
+	if bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	if strings.HasPrefix(mimeType, "text/") || textMIMETypes[mimeType] || textExtensions[ext] {
+		return true
+	}
+	// A cut multi-byte rune at the end of the sniffed bytes is still text
+	for i := 0; i < utf8.UTFMax && len(head) > 0; i++ {
+		if utf8.Valid(head) {
+			return mimeType == "" || mimeType == "application/octet-stream"
+		}
+		head = head[:len(head)-1]
+	}
+	return false
+}
+
+func extractPlain(r io.ReaderAt, size int64, limit int) (string, error) {
+	buf, err := io.ReadAll(io.NewSectionReader(r, 0, min(size, int64(limit))))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToValidUTF8(string(buf), ""), nil
+}
+
+// textWriter collects text up to a limit, collapsing runs of whitespace
+type textWriter struct {
+	b     strings.Builder
+	limit int
+	space bool
+}
+
+func (w *textWriter) full() bool { return w.b.Len() >= w.limit }
+
+func (w *textWriter) write(s string) {
+	for _, c := range s {
+		if w.full() {
+			return
+		}
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' {
+			w.space = w.b.Len() > 0
+			continue
+		}
+		if c == utf8.RuneError || c < ' ' {
+			continue
+		}
+		if w.space {
+			w.b.WriteByte(' ')
+			w.space = false
+		}
+		w.b.WriteRune(c)
+	}
+}
+
+func (w *textWriter) separate() { w.space = w.b.Len() > 0 }
+
+var (
+	pdfStream = regexp.MustCompile(`(?s)<<(.*?)>>\s*stream\r?\n`)
+	pdfText   = regexp.MustCompile(`(?s)BT(.*?)ET`)
+)
+
+// extractPDF inflates the content streams of a PDF and collects the
+// literal strings shown by text operators. Strings in hex form, which
+// mostly use font-specific encodings, are skipped.
+func extractPDF(r io.ReaderAt, size int64, limit int) (string, error) {
+	if size > maxDocumentSize {
+		return "", ErrUnsupported
+	}
+	data, err := io.ReadAll(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return "", err
+	}
+
+	w := &textWriter{limit: limit}
+	for _, loc := range pdfStream.FindAllSubmatchIndex(data, -1) {
+		if w.full() {
+			break
+		}
+		dict := data[loc[2]:loc[3]]
+		start := loc[1]
+		end := bytes.Index(data[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		body := data[start : start+end]
+
+		var content []byte
+		switch {
+		case bytes.Contains(dict, []byte("/FlateDecode")):
+			zr, err := zlib.NewReader(bytes.NewReader(body))
+			if err != nil {
+				continue
+			}
+			// Truncated streams still yield the text inflated so far
+			content, _ = io.ReadAll(io.LimitReader(zr, maxInflatedSize))
+			zr.Close()
+		case bytes.Contains(dict, []byte("/Filter")):
+			continue
+		default:
+			content = body
+		}
+		for _, block := range pdfText.FindAllSubmatch(content, -1) {
+			pdfShowText(w, block[1])
+			w.separate()
+		}
+	}
+	return w.b.String(), nil
+}
+
+// pdfShowText writes the literal strings of a text object. Strings in one
+// TJ array form one run of text; separate operators are separate runs.
+func pdfShowText(w *textWriter, ops []byte) {
+	inArray := false
+	for i := 0; i < len(ops) && !w.full(); i++ {
+		switch ops[i] {
+		case '[':
+			inArray = true
+		case ']':
+			inArray = false
+		case '(':
+			s, next := pdfString(ops, i+1)
+			w.write(s)
+			if !inArray {
+				w.separate()
+			}
+			i = next
+		case 'T':
+			// Moving to another line separates words
+			if i+1 < len(ops) && strings.IndexByte("dD*", ops[i+1]) >= 0 {
+				w.separate()
+			}
+		}
+	}
+	w.separate()
+}
+
+// pdfString decodes a literal string starting after its opening
+// parenthesis, returning it and the index of its closing parenthesis
+func pdfString(b []byte, i int) (string, int) {
+	var out []byte
+	depth := 0
+	for ; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == '\\' && i+1 < len(b):
+			i++
+			switch e := b[i]; e {
+			case 'n', 'r', 't', 'f':
+				out = append(out, ' ')
+			case 'b':
+			case '\r', '\n':
+				// Line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for k := 0; k < 2 && i+1 < len(b) && b[i+1] >= '0' && b[i+1] <= '7'; k++ {
+						i++
+						v = v*8 + int(b[i]-'0')
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		case c == '(':
+			depth++
+			out = append(out, c)
+		case c == ')':
+			if depth == 0 {
+				return latin1(out), i
+			}
+			depth--
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return latin1(out), i
+}
+
+// latin1 decodes PDFDocEncoding approximately; UTF-16 strings with a byte
+// order mark are decoded as such
+func latin1(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		var sb strings.Builder
+		for i := 2; i+1 < len(b); i += 2 {
+			sb.WriteRune(rune(b[i])<<8 | rune(b[i+1]))
+		}
+		return sb.String()
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// paragraphElements end a run of text in the XML of office documents
+var paragraphElements = map[string]bool{
+	"p": true, "h": true, "br": true, "tab": true, "tc": true, "si": true,
+	"table-cell": true, "line-break": true, "s": true,
+}
+
+// extractOffice reads the character data of the XML parts of an office
+// document
+func extractOffice(r io.ReaderAt, size int64, parts []string, limit int) (string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", ErrUnsupported
+	}
+	var files []*zip.File
+	for _, f := range zr.File {
+		for _, pattern := range parts {
+			if ok, _ := path.Match(pattern, f.Name); ok {
+				files = append(files, f)
+				break
+			}
+		}
+	}
+	// Slides and headers are numbered; keep them in document order
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i].Name, files[j].Name
+		if len(a) != len(b) && path.Dir(a) == path.Dir(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+
+	w := &textWriter{limit: limit}
+	for _, f := range files {
+		if w.full() {
+			break
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		err = xmlText(w, io.LimitReader(rc, maxInflatedSize))
+		rc.Close()
+		if err != nil {
+			return "", err
+		}
+		w.separate()
+	}
+	return w.b.String(), nil
+}
+
+func xmlText(w *textWriter, r io.Reader) error {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	for !w.full() {
+		tok, err := d.Token()
+		if err != nil {
+			// A malformed or truncated part keeps the text read before it
+			return nil
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			w.write(string(t))
+		case xml.StartElement:
+			if paragraphElements[t.Name.Local] {
+				w.separate()
+			}
+		case xml.EndElement:
+			if paragraphElements[t.Name.Local] {
+				w.separate()
+			}
+		}
+	}
+	return nil
+}
@@ -7,4 +7,347 @@ CREATE INDEX IF NOT EXISTS idx_uploads_user_created ON uploads (user_id, created
 -- Add additional columns for better tracking
 ALTER TABLE uploads ADD COLUMN IF NOT EXISTS size_bytes BIGINT DEFAULT 0;
 ALTER TABLE uploads ADD COLUMN IF NOT EXISTS mime_type TEXT;
-ALTER TABLE uploads ADD COLUMN IF NOT EXISTS sha256 TEXT;
\ No newline at end of file
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS sha256 TEXT;
+
+-- Signed download URLs
+CREATE TABLE IF NOT EXISTS signed_urls (
+    id UUID PRIMARY KEY,
+    file_id UUID NOT NULL REFERENCES uploads(file_id) ON DELETE CASCADE,
+    created_by TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    max_downloads BIGINT NOT NULL DEFAULT 0,    -- 0 means unlimited
+    download_count BIGINT NOT NULL DEFAULT 0,
+    allowed_ip TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_signed_urls_file ON signed_urls (file_id);
+
+-- Per-file access grants (users, groups and public link tokens)
+CREATE TABLE IF NOT EXISTS file_grants (
+    grant_id UUID PRIMARY KEY,
+    file_id UUID NOT NULL REFERENCES uploads(file_id) ON DELETE CASCADE,
+    grantee_type TEXT NOT NULL CHECK (grantee_type IN ('user','group','link')),
+    grantee_id TEXT NOT NULL,                   -- user id, group name or sha256 of link token
+    permissions TEXT[] NOT NULL,                -- any of read, write, delete
+    expires_at TIMESTAMPTZ,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_grants_file ON file_grants (file_id);
+CREATE INDEX IF NOT EXISTS idx_file_grants_grantee ON file_grants (grantee_type, grantee_id);
+
+-- Malware scanning: quarantined status and recorded scan results
+ALTER TABLE uploads DROP CONSTRAINT IF EXISTS status_check;
+ALTER TABLE uploads ADD CONSTRAINT status_check CHECK (status IN ('in_progress','completed','failed','quarantined'));
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS scan_result TEXT;          -- clean, infected, error, too_large or skipped
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS scan_signature TEXT;       -- threat name or scanner error
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS scan_engine TEXT;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS scan_engine_version TEXT;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ;
+
+-- Encryption at rest: per-file data keys wrapped by a master key
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS enc_key_id TEXT;          -- master key id
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS enc_wrapped_key BYTEA;    -- NULL for plaintext files
+CREATE INDEX IF NOT EXISTS idx_uploads_enc_key ON uploads (enc_key_id) WHERE enc_wrapped_key IS NOT NULL;
+
+-- Client-side (end-to-end) encryption metadata, opaque to the server
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS client_enc_scheme TEXT;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS client_wrapped_key BYTEA;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS client_key_id TEXT;
+
+-- Append-only audit log of security-relevant actions
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    actor TEXT NOT NULL,                        -- user id, upload-token, share-link, signed-url or anonymous
+    action TEXT NOT NULL,                       -- init, chunk, complete, download, delete, share, unshare
+    file_id TEXT,
+    peer_ip TEXT,
+    user_agent TEXT,
+    result TEXT NOT NULL,                       -- ok, failed or a lowercase gRPC code
+    detail TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred ON audit_events (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events (actor, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_file ON audit_events (file_id, id);
+
+-- Content-addressed blob store: one stored file per distinct SHA-256
+CREATE TABLE IF NOT EXISTS blobs (
+    sha256 TEXT PRIMARY KEY,                    -- hex SHA-256 of the plaintext
+    size_bytes BIGINT NOT NULL,
+    stored_path TEXT NOT NULL,
+    ref_count BIGINT NOT NULL DEFAULT 0 CHECK (ref_count >= 0),
+    enc_key_id TEXT,                            -- data key shared by all referencing uploads
+    enc_wrapped_key BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    unreferenced_at TIMESTAMPTZ                 -- set when ref_count drops to zero
+);
+
+CREATE INDEX IF NOT EXISTS idx_blobs_unreferenced ON blobs (unreferenced_at) WHERE ref_count = 0;
+CREATE INDEX IF NOT EXISTS idx_uploads_sha256 ON uploads (sha256);
+CREATE INDEX IF NOT EXISTS idx_blobs_enc_key ON blobs (enc_key_id) WHERE enc_wrapped_key IS NOT NULL;
+
+-- Chunk-level deduplication: content-defined chunks and per-file manifests
+CREATE TABLE IF NOT EXISTS content_chunks (
+    sha256 TEXT PRIMARY KEY,
+    size_bytes BIGINT NOT NULL,
+    stored_path TEXT NOT NULL,
+    ref_count BIGINT NOT NULL DEFAULT 0 CHECK (ref_count >= 0),   -- manifest entries using the chunk
+    enc_key_id TEXT,
+    enc_wrapped_key BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    unreferenced_at TIMESTAMPTZ
+);
+
+-- Users who have uploaded each chunk; only they may reference it
+CREATE TABLE IF NOT EXISTS content_chunk_users (
+    sha256 TEXT NOT NULL REFERENCES content_chunks(sha256) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, sha256)
+);
+
+CREATE TABLE IF NOT EXISTS file_manifests (
+    file_id UUID NOT NULL REFERENCES uploads(file_id) ON DELETE CASCADE,
+    seq INTEGER NOT NULL,
+    chunk_sha256 TEXT NOT NULL REFERENCES content_chunks(sha256),
+    size_bytes BIGINT NOT NULL,
+    PRIMARY KEY (file_id, seq)
+);
+
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS manifest BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_content_chunks_unreferenced ON content_chunks (unreferenced_at) WHERE ref_count = 0;
+CREATE INDEX IF NOT EXISTS idx_content_chunk_users_sha ON content_chunk_users (sha256);
+CREATE INDEX IF NOT EXISTS idx_file_manifests_chunk ON file_manifests (chunk_sha256);
+
+-- File versions: later uploads of a file point at its first upload
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS version_of UUID REFERENCES uploads(file_id);
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS restored_from UUID;
+CREATE INDEX IF NOT EXISTS idx_uploads_version_of ON uploads (version_of);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_uploads_file_version ON uploads ((COALESCE(version_of, file_id)), version);
+ALTER TABLE uploads DROP CONSTRAINT IF EXISTS status_check;
+ALTER TABLE uploads ADD CONSTRAINT status_check CHECK (status IN ('in_progress','completed','failed','quarantined','pruned'));
+
+-- Trash: deleted uploads keep their content until purged
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+CREATE INDEX IF NOT EXISTS idx_uploads_deleted ON uploads (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Retention policies and legal holds
+CREATE TABLE IF NOT EXISTS retention_policies (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    min_retention_seconds BIGINT NOT NULL DEFAULT 0 CHECK (min_retention_seconds >= 0),   -- deletion refused before this age
+    max_retention_seconds BIGINT NOT NULL DEFAULT 0 CHECK (max_retention_seconds >= 0),   -- deleted automatically at this age
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS user_retention_policies (
+    user_id TEXT PRIMARY KEY,
+    policy_id UUID NOT NULL REFERENCES retention_policies(id),
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS retention_policy_id UUID REFERENCES retention_policies(id);
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS legal_hold_reason TEXT;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS legal_hold_by TEXT;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS legal_hold_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_uploads_retention_policy ON uploads (retention_policy_id) WHERE retention_policy_id IS NOT NULL;
+
+-- Folders: a virtual tree per user, independent of the storage layout
+CREATE TABLE IF NOT EXISTS folders (
+    id UUID PRIMARY KEY,
+    owner_id TEXT NOT NULL,
+    parent_id UUID REFERENCES folders(id) ON DELETE CASCADE,   -- NULL at the top level
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_name
+    ON folders (owner_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), name);
+CREATE INDEX IF NOT EXISTS idx_folders_parent ON folders (parent_id);
+
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_uploads_folder ON uploads (user_id, folder_id, file_name) WHERE version_of IS NULL;
+
+-- User metadata and tags, kept on a file's first version
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS idx_uploads_metadata ON uploads USING GIN (metadata jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_uploads_tags ON uploads USING GIN (tags);
+
+-- Full-text search over names, tags and metadata of files, and over the text
+-- extracted from their content
+ALTER TABLE uploads ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+CREATE OR REPLACE FUNCTION uploads_search_vector() RETURNS trigger AS $$
+BEGIN
+    -- Names also match by their parts, e.g. "q3" in "report_q3.pdf"
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', NEW.file_name || ' ' || regexp_replace(NEW.file_name, '[._-]+', ' ', 'g')), 'A') ||
+        setweight(to_tsvector('simple', array_to_string(NEW.tags, ' ')), 'B') ||
+        setweight(jsonb_to_tsvector('simple', NEW.metadata, '["key", "string"]'), 'C');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS uploads_search_vector ON uploads;
+CREATE TRIGGER uploads_search_vector BEFORE INSERT OR UPDATE OF file_name, tags, metadata ON uploads
+    FOR EACH ROW EXECUTE FUNCTION uploads_search_vector();
+UPDATE uploads SET file_name = file_name WHERE search_vector IS NULL;
+CREATE INDEX IF NOT EXISTS idx_uploads_search ON uploads USING GIN (search_vector) WHERE version_of IS NULL;
+
+CREATE TABLE IF NOT EXISTS file_text (
+    file_id UUID PRIMARY KEY REFERENCES uploads(file_id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    tsv TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', content)) STORED,
+    extracted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_file_text_tsv ON file_text USING GIN (tsv);
+
+-- Tenants: every row below belongs to one, and queries only see the rows of
+-- the tenant set in app.tenant_id. Connections without it (background jobs
+-- and tools) see every tenant. The application role must not be a superuser
+-- or have BYPASSRLS, or the policies are not applied.
+CREATE TABLE IF NOT EXISTS tenants (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    quota_bytes BIGINT NOT NULL DEFAULT 0 CHECK (quota_bytes >= 0),         -- 0 for unlimited
+    max_file_size BIGINT NOT NULL DEFAULT 0 CHECK (max_file_size >= 0),     -- 0 for unlimited
+    content_policy JSONB,                       -- layered over the deployment content policy
+    disabled BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+INSERT INTO tenants (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['uploads', 'folders', 'file_grants', 'signed_urls', 'audit_events', 'blobs',
+                             'content_chunks', 'content_chunk_users', 'file_manifests', 'retention_policies',
+                             'user_retention_policies', 'file_text']
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT ''default'' REFERENCES tenants(id)', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN tenant_id SET DEFAULT COALESCE(NULLIF(current_setting(''app.tenant_id'', true), ''''), ''default'')', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I USING (COALESCE(current_setting(''app.tenant_id'', true), '''') IN ('''', tenant_id))', t);
+    END LOOP;
+END
+$$;
+
+-- Content-addressed stores, user assignments and folder names are unique per tenant
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.key_column_usage
+                   WHERE table_name = 'blobs' AND constraint_name = 'blobs_pkey' AND column_name = 'tenant_id') THEN
+        ALTER TABLE file_manifests DROP CONSTRAINT IF EXISTS file_manifests_chunk_sha256_fkey;
+        ALTER TABLE content_chunk_users DROP CONSTRAINT IF EXISTS content_chunk_users_sha256_fkey;
+        ALTER TABLE blobs DROP CONSTRAINT blobs_pkey, ADD PRIMARY KEY (tenant_id, sha256);
+        ALTER TABLE content_chunks DROP CONSTRAINT content_chunks_pkey, ADD PRIMARY KEY (tenant_id, sha256);
+        ALTER TABLE content_chunk_users DROP CONSTRAINT content_chunk_users_pkey,
+            ADD PRIMARY KEY (tenant_id, user_id, sha256),
+            ADD FOREIGN KEY (tenant_id, sha256) REFERENCES content_chunks (tenant_id, sha256) ON DELETE CASCADE;
+        ALTER TABLE file_manifests ADD FOREIGN KEY (tenant_id, chunk_sha256) REFERENCES content_chunks (tenant_id, sha256);
+        ALTER TABLE user_retention_policies DROP CONSTRAINT user_retention_policies_pkey, ADD PRIMARY KEY (tenant_id, user_id);
+    END IF;
+END
+$$;
+DROP INDEX IF EXISTS idx_folders_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_tenant_name
+    ON folders (tenant_id, owner_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), name);
+CREATE INDEX IF NOT EXISTS idx_uploads_tenant ON uploads (tenant_id);
+
+-- Archives uploaded with extract are unpacked into a new folder once they complete
+CREATE TABLE IF NOT EXISTS archive_extractions (
+    file_id UUID PRIMARY KEY REFERENCES uploads(file_id) ON DELETE CASCADE,
+    tenant_id TEXT NOT NULL DEFAULT COALESCE(NULLIF(current_setting('app.tenant_id', true), ''), 'default') REFERENCES tenants(id),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','running','completed','failed')),
+    folder_id UUID REFERENCES folders(id) ON DELETE SET NULL,
+    files INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+ALTER TABLE archive_extractions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE archive_extractions FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON archive_extractions;
+CREATE POLICY tenant_isolation ON archive_extractions
+    USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+-- Uploads created by ImportFromURL have their content fetched by the server
+CREATE TABLE IF NOT EXISTS url_imports (
+    file_id UUID PRIMARY KEY REFERENCES uploads(file_id) ON DELETE CASCADE,
+    tenant_id TEXT NOT NULL DEFAULT COALESCE(NULLIF(current_setting('app.tenant_id', true), ''), 'default') REFERENCES tenants(id),
+    source_url TEXT NOT NULL,
+    expected_sha256 TEXT,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','downloading','completed','failed')),
+    bytes_received BIGINT NOT NULL DEFAULT 0,
+    total_bytes BIGINT NOT NULL DEFAULT 0,
+    validator TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    lease_until TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_url_imports_active ON url_imports (status) WHERE status IN ('pending','downloading');
+ALTER TABLE url_imports ENABLE ROW LEVEL SECURITY;
+ALTER TABLE url_imports FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON url_imports;
+CREATE POLICY tenant_isolation ON url_imports
+    USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+-- Webhook subscriptions and the deliveries of their events
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT COALESCE(NULLIF(current_setting('app.tenant_id', true), ''), 'default') REFERENCES tenants(id),
+    owner_id TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT[] NOT NULL,
+    tenant_wide BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_owner ON webhooks (tenant_id, owner_id);
+ALTER TABLE webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhooks FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON webhooks;
+CREATE POLICY tenant_isolation ON webhooks
+    USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT COALESCE(NULLIF(current_setting('app.tenant_id', true), ''), 'default') REFERENCES tenants(id),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    file_id UUID,                               -- no foreign key: history outlives purged files
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending','delivered','dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, id DESC);
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS tenant_isolation ON webhook_deliveries;
+CREATE POLICY tenant_isolation ON webhook_deliveries
+    USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+-- Running extractions hold a lease so those of a server that went away are resumed
+ALTER TABLE archive_extractions ADD COLUMN IF NOT EXISTS lease_until TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_archive_extractions_active ON archive_extractions (status) WHERE status IN ('pending','running');
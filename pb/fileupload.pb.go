@@ -109,6 +109,7 @@ func (x *FileChunk) GetContent() []byte {
 type DownloadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // 0 means the current version
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -150,10 +151,24 @@ func (x *DownloadRequest) GetFileId() string {
 	return ""
 }
 
+func (x *DownloadRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type DownloadResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Content  []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	FileName string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// Set for client-side encrypted uploads; content is then opaque ciphertext
+	EncryptionScheme string `protobuf:"bytes,3,opt,name=encryption_scheme,json=encryptionScheme,proto3" json:"encryption_scheme,omitempty"`
+	WrappedKey       []byte `protobuf:"bytes,4,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	KeyId            string `protobuf:"bytes,5,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Version          int64  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	// Upload ID the content was encrypted under, for client-side decryption
+	VersionId     string `protobuf:"bytes,7,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -202,6 +217,41 @@ func (x *DownloadResponse) GetFileName() string {
 	return ""
 }
 
+func (x *DownloadResponse) GetEncryptionScheme() string {
+	if x != nil {
+		return x.EncryptionScheme
+	}
+	return ""
+}
+
+func (x *DownloadResponse) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *DownloadResponse) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *DownloadResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DownloadResponse) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
 type UploadStatus struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -395,14 +445,24 @@ func (x *GetMetadataRequest) GetFileId() string {
 }
 
 type UploadMetadata struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	FileId         string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
-	FileName       string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
-	Size           int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
-	UploadedChunks []int64                `protobuf:"varint,4,rep,packed,name=uploaded_chunks,json=uploadedChunks,proto3" json:"uploaded_chunks,omitempty"`
-	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FileId           string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FileName         string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Size             int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	UploadedChunks   []int64                `protobuf:"varint,4,rep,packed,name=uploaded_chunks,json=uploadedChunks,proto3" json:"uploaded_chunks,omitempty"`
+	Status           string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	EncryptionScheme string                 `protobuf:"bytes,6,opt,name=encryption_scheme,json=encryptionScheme,proto3" json:"encryption_scheme,omitempty"`
+	WrappedKey       []byte                 `protobuf:"bytes,7,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	KeyId            string                 `protobuf:"bytes,8,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Version          int64                  `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	LegalHold        bool                   `protobuf:"varint,10,opt,name=legal_hold,json=legalHold,proto3" json:"legal_hold,omitempty"`
+	RetainUntil      int64                  `protobuf:"varint,11,opt,name=retain_until,json=retainUntil,proto3" json:"retain_until,omitempty"` // earliest time the file may be deleted, 0 if unrestricted
+	Metadata         map[string]string      `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags             []string               `protobuf:"bytes,13,rep,name=tags,proto3" json:"tags,omitempty"`
+	Extraction       *ArchiveExtraction     `protobuf:"bytes,14,opt,name=extraction,proto3" json:"extraction,omitempty"`                // set for uploads with extract
+	UrlImport        *URLImport             `protobuf:"bytes,15,opt,name=url_import,json=urlImport,proto3" json:"url_import,omitempty"` // set for uploads created by ImportFromURL
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *UploadMetadata) Reset() {
@@ -470,29 +530,103 @@ func (x *UploadMetadata) GetStatus() string {
 	return ""
 }
 
-type InitRequest struct {
+func (x *UploadMetadata) GetEncryptionScheme() string {
+	if x != nil {
+		return x.EncryptionScheme
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetWrappedKey() []byte {
+	if x != nil {
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *UploadMetadata) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UploadMetadata) GetLegalHold() bool {
+	if x != nil {
+		return x.LegalHold
+	}
+	return false
+}
+
+func (x *UploadMetadata) GetRetainUntil() int64 {
+	if x != nil {
+		return x.RetainUntil
+	}
+	return 0
+}
+
+func (x *UploadMetadata) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UploadMetadata) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UploadMetadata) GetExtraction() *ArchiveExtraction {
+	if x != nil {
+		return x.Extraction
+	}
+	return nil
+}
+
+func (x *UploadMetadata) GetUrlImport() *URLImport {
+	if x != nil {
+		return x.UrlImport
+	}
+	return nil
+}
+
+// Progress of fetching the content of an upload from a URL
+type URLImport struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileName      string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
-	TotalChunks   int64                  `protobuf:"varint,2,opt,name=total_chunks,json=totalChunks,proto3" json:"total_chunks,omitempty"`
-	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SourceUrl     string                 `protobuf:"bytes,1,opt,name=source_url,json=sourceUrl,proto3" json:"source_url,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // pending, downloading, completed or failed
+	BytesReceived int64                  `protobuf:"varint,3,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	TotalBytes    int64                  `protobuf:"varint,4,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"` // 0 while unknown
+	Attempts      int32                  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"` // last error; retried while the status is downloading
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InitRequest) Reset() {
-	*x = InitRequest{}
+func (x *URLImport) Reset() {
+	*x = URLImport{}
 	mi := &file_fileupload_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InitRequest) String() string {
+func (x *URLImport) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InitRequest) ProtoMessage() {}
+func (*URLImport) ProtoMessage() {}
 
-func (x *InitRequest) ProtoReflect() protoreflect.Message {
+func (x *URLImport) ProtoReflect() protoreflect.Message {
 	mi := &file_fileupload_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -504,53 +638,79 @@ func (x *InitRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InitRequest.ProtoReflect.Descriptor instead.
-func (*InitRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use URLImport.ProtoReflect.Descriptor instead.
+func (*URLImport) Descriptor() ([]byte, []int) {
 	return file_fileupload_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *InitRequest) GetFileName() string {
+func (x *URLImport) GetSourceUrl() string {
 	if x != nil {
-		return x.FileName
+		return x.SourceUrl
 	}
 	return ""
 }
 
-func (x *InitRequest) GetTotalChunks() int64 {
+func (x *URLImport) GetStatus() string {
 	if x != nil {
-		return x.TotalChunks
+		return x.Status
+	}
+	return ""
+}
+
+func (x *URLImport) GetBytesReceived() int64 {
+	if x != nil {
+		return x.BytesReceived
 	}
 	return 0
 }
 
-func (x *InitRequest) GetUserId() string {
+func (x *URLImport) GetTotalBytes() int64 {
 	if x != nil {
-		return x.UserId
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *URLImport) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *URLImport) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-type InitResponse struct {
+// Progress of unpacking an archive uploaded with extract
+type ArchiveExtraction struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                     // pending, running, completed or failed
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"` // folder holding the extracted files
+	Files         int32                  `protobuf:"varint,3,opt,name=files,proto3" json:"files,omitempty"`
+	Skipped       int32                  `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"` // unsafe names, links, disallowed or infected members
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InitResponse) Reset() {
-	*x = InitResponse{}
+func (x *ArchiveExtraction) Reset() {
+	*x = ArchiveExtraction{}
 	mi := &file_fileupload_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InitResponse) String() string {
+func (x *ArchiveExtraction) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InitResponse) ProtoMessage() {}
+func (*ArchiveExtraction) ProtoMessage() {}
 
-func (x *InitResponse) ProtoReflect() protoreflect.Message {
+func (x *ArchiveExtraction) ProtoReflect() protoreflect.Message {
 	mi := &file_fileupload_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -562,39 +722,94 @@ func (x *InitResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InitResponse.ProtoReflect.Descriptor instead.
-func (*InitResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ArchiveExtraction.ProtoReflect.Descriptor instead.
+func (*ArchiveExtraction) Descriptor() ([]byte, []int) {
 	return file_fileupload_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *InitResponse) GetFileId() string {
+func (x *ArchiveExtraction) GetStatus() string {
 	if x != nil {
-		return x.FileId
+		return x.Status
 	}
 	return ""
 }
 
-type DeleteRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+func (x *ArchiveExtraction) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *ArchiveExtraction) GetFiles() int32 {
+	if x != nil {
+		return x.Files
+	}
+	return 0
+}
+
+func (x *ArchiveExtraction) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ArchiveExtraction) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type InitRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	FileName    string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	TotalChunks int64                  `protobuf:"varint,2,opt,name=total_chunks,json=totalChunks,proto3" json:"total_chunks,omitempty"`
+	UserId      string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Optional presigned upload token for unauthenticated uploaders
+	IssueUploadToken      bool     `protobuf:"varint,4,opt,name=issue_upload_token,json=issueUploadToken,proto3" json:"issue_upload_token,omitempty"`
+	MaxSize               int64    `protobuf:"varint,5,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	AllowedMimeTypes      []string `protobuf:"bytes,6,rep,name=allowed_mime_types,json=allowedMimeTypes,proto3" json:"allowed_mime_types,omitempty"`
+	UploadTokenTtlSeconds int64    `protobuf:"varint,7,opt,name=upload_token_ttl_seconds,json=uploadTokenTtlSeconds,proto3" json:"upload_token_ttl_seconds,omitempty"`
+	// Optional client-side encryption: the server stores these opaquely
+	EncryptionScheme string `protobuf:"bytes,8,opt,name=encryption_scheme,json=encryptionScheme,proto3" json:"encryption_scheme,omitempty"`
+	WrappedKey       []byte `protobuf:"bytes,9,opt,name=wrapped_key,json=wrappedKey,proto3" json:"wrapped_key,omitempty"`
+	KeyId            string `protobuf:"bytes,10,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// Optional hex SHA-256 and size of the plaintext; lets the server skip
+	// the upload when it already stores identical content the caller can read
+	Sha256 string `protobuf:"bytes,11,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Size   int64  `protobuf:"varint,12,opt,name=size,proto3" json:"size,omitempty"`
+	// Upload a new version of an existing file instead of a new file
+	TargetFileId string `protobuf:"bytes,13,opt,name=target_file_id,json=targetFileId,proto3" json:"target_file_id,omitempty"`
+	// Optional folder to place the new file in, by ID or by path such as
+	// "/projects/q3"; the top level when both are empty
+	FolderId   string `protobuf:"bytes,14,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	FolderPath string `protobuf:"bytes,15,opt,name=folder_path,json=folderPath,proto3" json:"folder_path,omitempty"`
+	// Optional user metadata and tags of the new file
+	Metadata map[string]string `protobuf:"bytes,16,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags     []string          `protobuf:"bytes,17,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Unpack a zip, tar or tar.gz archive into a new folder next to it once
+	// the upload completes
+	Extract       bool `protobuf:"varint,18,opt,name=extract,proto3" json:"extract,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRequest) Reset() {
-	*x = DeleteRequest{}
+func (x *InitRequest) Reset() {
+	*x = InitRequest{}
 	mi := &file_fileupload_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRequest) String() string {
+func (x *InitRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequest) ProtoMessage() {}
+func (*InitRequest) ProtoMessage() {}
 
-func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+func (x *InitRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_fileupload_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -606,116 +821,5606 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use InitRequest.ProtoReflect.Descriptor instead.
+func (*InitRequest) Descriptor() ([]byte, []int) {
 	return file_fileupload_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *DeleteRequest) GetFileId() string {
+func (x *InitRequest) GetFileName() string {
 	if x != nil {
-		return x.FileId
+		return x.FileName
 	}
 	return ""
 }
 
-type DeleteResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *InitRequest) GetTotalChunks() int64 {
+	if x != nil {
+		return x.TotalChunks
+	}
+	return 0
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
-	mi := &file_fileupload_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *InitRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (x *DeleteResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *InitRequest) GetIssueUploadToken() bool {
+	if x != nil {
+		return x.IssueUploadToken
+	}
+	return false
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (x *InitRequest) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_fileupload_proto_msgTypes[11]
+func (x *InitRequest) GetAllowedMimeTypes() []string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.AllowedMimeTypes
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return file_fileupload_proto_rawDescGZIP(), []int{11}
+func (x *InitRequest) GetUploadTokenTtlSeconds() int64 {
+	if x != nil {
+		return x.UploadTokenTtlSeconds
+	}
+	return 0
 }
 
-func (x *DeleteResponse) GetSuccess() bool {
+func (x *InitRequest) GetEncryptionScheme() string {
 	if x != nil {
-		return x.Success
+		return x.EncryptionScheme
 	}
-	return false
+	return ""
 }
 
-func (x *DeleteResponse) GetMessage() string {
+func (x *InitRequest) GetWrappedKey() []byte {
 	if x != nil {
-		return x.Message
+		return x.WrappedKey
+	}
+	return nil
+}
+
+func (x *InitRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
 	}
 	return ""
 }
 
-var File_fileupload_proto protoreflect.FileDescriptor
+func (x *InitRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
 
-const file_fileupload_proto_rawDesc = "" +
-	"\n" +
-	"\x10fileupload.proto\x12\x02pb\x1a\x1cgoogle/api/annotations.proto\"\xb8\x01\n" +
-	"\tFileChunk\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
-	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1f\n" +
-	"\vchunk_index\x18\x04 \x01(\x03R\n" +
-	"chunkIndex\x12!\n" +
-	"\ftotal_chunks\x18\x05 \x01(\x03R\vtotalChunks\x12\x18\n" +
-	"\acontent\x18\x06 \x01(\fR\acontent\"*\n" +
-	"\x0fDownloadRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\"I\n" +
-	"\x10DownloadResponse\x12\x18\n" +
-	"\acontent\x18\x01 \x01(\fR\acontent\x12\x1b\n" +
-	"\tfile_name\x18\x02 \x01(\tR\bfileName\"c\n" +
-	"\fUploadStatus\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
-	"\vstored_path\x18\x03 \x01(\tR\n" +
-	"storedPath\"+\n" +
-	"\x10GetChunksRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\"<\n" +
-	"\x11GetChunksResponse\x12'\n" +
-	"\x0fuploaded_chunks\x18\x01 \x03(\x03R\x0euploadedChunks\"-\n" +
+func (x *InitRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *InitRequest) GetTargetFileId() string {
+	if x != nil {
+		return x.TargetFileId
+	}
+	return ""
+}
+
+func (x *InitRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *InitRequest) GetFolderPath() string {
+	if x != nil {
+		return x.FolderPath
+	}
+	return ""
+}
+
+func (x *InitRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *InitRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *InitRequest) GetExtract() bool {
+	if x != nil {
+		return x.Extract
+	}
+	return false
+}
+
+type InitResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	FileId               string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UploadToken          string                 `protobuf:"bytes,2,opt,name=upload_token,json=uploadToken,proto3" json:"upload_token,omitempty"`
+	UploadTokenExpiresAt int64                  `protobuf:"varint,3,opt,name=upload_token_expires_at,json=uploadTokenExpiresAt,proto3" json:"upload_token_expires_at,omitempty"`
+	// The upload is already completed from existing content; do not send chunks
+	AlreadyUploaded bool  `protobuf:"varint,4,opt,name=already_uploaded,json=alreadyUploaded,proto3" json:"already_uploaded,omitempty"`
+	Version         int64 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InitResponse) Reset() {
+	*x = InitResponse{}
+	mi := &file_fileupload_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InitResponse) ProtoMessage() {}
+
+func (x *InitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InitResponse.ProtoReflect.Descriptor instead.
+func (*InitResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *InitResponse) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *InitResponse) GetUploadToken() string {
+	if x != nil {
+		return x.UploadToken
+	}
+	return ""
+}
+
+func (x *InitResponse) GetUploadTokenExpiresAt() int64 {
+	if x != nil {
+		return x.UploadTokenExpiresAt
+	}
+	return 0
+}
+
+func (x *InitResponse) GetAlreadyUploaded() bool {
+	if x != nil {
+		return x.AlreadyUploaded
+	}
+	return false
+}
+
+func (x *InitResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	mi := &file_fileupload_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_fileupload_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CreateSignedURLRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FileId           string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	ExpiresInSeconds int64                  `protobuf:"varint,2,opt,name=expires_in_seconds,json=expiresInSeconds,proto3" json:"expires_in_seconds,omitempty"`
+	MaxDownloads     int64                  `protobuf:"varint,3,opt,name=max_downloads,json=maxDownloads,proto3" json:"max_downloads,omitempty"`
+	AllowedIp        string                 `protobuf:"bytes,4,opt,name=allowed_ip,json=allowedIp,proto3" json:"allowed_ip,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateSignedURLRequest) Reset() {
+	*x = CreateSignedURLRequest{}
+	mi := &file_fileupload_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSignedURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSignedURLRequest) ProtoMessage() {}
+
+func (x *CreateSignedURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSignedURLRequest.ProtoReflect.Descriptor instead.
+func (*CreateSignedURLRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateSignedURLRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *CreateSignedURLRequest) GetExpiresInSeconds() int64 {
+	if x != nil {
+		return x.ExpiresInSeconds
+	}
+	return 0
+}
+
+func (x *CreateSignedURLRequest) GetMaxDownloads() int64 {
+	if x != nil {
+		return x.MaxDownloads
+	}
+	return 0
+}
+
+func (x *CreateSignedURLRequest) GetAllowedIp() string {
+	if x != nil {
+		return x.AllowedIp
+	}
+	return ""
+}
+
+type CreateSignedURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	UrlId         string                 `protobuf:"bytes,2,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSignedURLResponse) Reset() {
+	*x = CreateSignedURLResponse{}
+	mi := &file_fileupload_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSignedURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSignedURLResponse) ProtoMessage() {}
+
+func (x *CreateSignedURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSignedURLResponse.ProtoReflect.Descriptor instead.
+func (*CreateSignedURLResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateSignedURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateSignedURLResponse) GetUrlId() string {
+	if x != nil {
+		return x.UrlId
+	}
+	return ""
+}
+
+func (x *CreateSignedURLResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type SignedDownloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	UrlId         string                 `protobuf:"bytes,2,opt,name=url_id,json=urlId,proto3" json:"url_id,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	MaxDownloads  int64                  `protobuf:"varint,4,opt,name=max_downloads,json=maxDownloads,proto3" json:"max_downloads,omitempty"`
+	AllowedIp     string                 `protobuf:"bytes,5,opt,name=allowed_ip,json=allowedIp,proto3" json:"allowed_ip,omitempty"`
+	Signature     string                 `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignedDownloadRequest) Reset() {
+	*x = SignedDownloadRequest{}
+	mi := &file_fileupload_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedDownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedDownloadRequest) ProtoMessage() {}
+
+func (x *SignedDownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedDownloadRequest.ProtoReflect.Descriptor instead.
+func (*SignedDownloadRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SignedDownloadRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *SignedDownloadRequest) GetUrlId() string {
+	if x != nil {
+		return x.UrlId
+	}
+	return ""
+}
+
+func (x *SignedDownloadRequest) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *SignedDownloadRequest) GetMaxDownloads() int64 {
+	if x != nil {
+		return x.MaxDownloads
+	}
+	return 0
+}
+
+func (x *SignedDownloadRequest) GetAllowedIp() string {
+	if x != nil {
+		return x.AllowedIp
+	}
+	return ""
+}
+
+func (x *SignedDownloadRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+// grantee_type is one of "user", "group" or "link"; permissions are any of
+// "read", "write" and "delete".
+type ShareFileRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	FileId           string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	GranteeType      string                 `protobuf:"bytes,2,opt,name=grantee_type,json=granteeType,proto3" json:"grantee_type,omitempty"`
+	GranteeId        string                 `protobuf:"bytes,3,opt,name=grantee_id,json=granteeId,proto3" json:"grantee_id,omitempty"`
+	Permissions      []string               `protobuf:"bytes,4,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	ExpiresInSeconds int64                  `protobuf:"varint,5,opt,name=expires_in_seconds,json=expiresInSeconds,proto3" json:"expires_in_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ShareFileRequest) Reset() {
+	*x = ShareFileRequest{}
+	mi := &file_fileupload_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareFileRequest) ProtoMessage() {}
+
+func (x *ShareFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareFileRequest.ProtoReflect.Descriptor instead.
+func (*ShareFileRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ShareFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *ShareFileRequest) GetGranteeType() string {
+	if x != nil {
+		return x.GranteeType
+	}
+	return ""
+}
+
+func (x *ShareFileRequest) GetGranteeId() string {
+	if x != nil {
+		return x.GranteeId
+	}
+	return ""
+}
+
+func (x *ShareFileRequest) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *ShareFileRequest) GetExpiresInSeconds() int64 {
+	if x != nil {
+		return x.ExpiresInSeconds
+	}
+	return 0
+}
+
+type ShareGrant struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	GrantId     string                 `protobuf:"bytes,1,opt,name=grant_id,json=grantId,proto3" json:"grant_id,omitempty"`
+	FileId      string                 `protobuf:"bytes,2,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	GranteeType string                 `protobuf:"bytes,3,opt,name=grantee_type,json=granteeType,proto3" json:"grantee_type,omitempty"`
+	GranteeId   string                 `protobuf:"bytes,4,opt,name=grantee_id,json=granteeId,proto3" json:"grantee_id,omitempty"`
+	Permissions []string               `protobuf:"bytes,5,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	ExpiresAt   int64                  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt   int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Only returned once, when a public link grant is created
+	LinkToken     string `protobuf:"bytes,8,opt,name=link_token,json=linkToken,proto3" json:"link_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShareGrant) Reset() {
+	*x = ShareGrant{}
+	mi := &file_fileupload_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShareGrant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShareGrant) ProtoMessage() {}
+
+func (x *ShareGrant) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShareGrant.ProtoReflect.Descriptor instead.
+func (*ShareGrant) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ShareGrant) GetGrantId() string {
+	if x != nil {
+		return x.GrantId
+	}
+	return ""
+}
+
+func (x *ShareGrant) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *ShareGrant) GetGranteeType() string {
+	if x != nil {
+		return x.GranteeType
+	}
+	return ""
+}
+
+func (x *ShareGrant) GetGranteeId() string {
+	if x != nil {
+		return x.GranteeId
+	}
+	return ""
+}
+
+func (x *ShareGrant) GetPermissions() []string {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+func (x *ShareGrant) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ShareGrant) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ShareGrant) GetLinkToken() string {
+	if x != nil {
+		return x.LinkToken
+	}
+	return ""
+}
+
+type UnshareFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	GrantId       string                 `protobuf:"bytes,2,opt,name=grant_id,json=grantId,proto3" json:"grant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareFileRequest) Reset() {
+	*x = UnshareFileRequest{}
+	mi := &file_fileupload_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareFileRequest) ProtoMessage() {}
+
+func (x *UnshareFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareFileRequest.ProtoReflect.Descriptor instead.
+func (*UnshareFileRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UnshareFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *UnshareFileRequest) GetGrantId() string {
+	if x != nil {
+		return x.GrantId
+	}
+	return ""
+}
+
+type UnshareFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnshareFileResponse) Reset() {
+	*x = UnshareFileResponse{}
+	mi := &file_fileupload_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnshareFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnshareFileResponse) ProtoMessage() {}
+
+func (x *UnshareFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnshareFileResponse.ProtoReflect.Descriptor instead.
+func (*UnshareFileResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UnshareFileResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ListGrantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGrantsRequest) Reset() {
+	*x = ListGrantsRequest{}
+	mi := &file_fileupload_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGrantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGrantsRequest) ProtoMessage() {}
+
+func (x *ListGrantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGrantsRequest.ProtoReflect.Descriptor instead.
+func (*ListGrantsRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListGrantsRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+type ListGrantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grants        []*ShareGrant          `protobuf:"bytes,1,rep,name=grants,proto3" json:"grants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGrantsResponse) Reset() {
+	*x = ListGrantsResponse{}
+	mi := &file_fileupload_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGrantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGrantsResponse) ProtoMessage() {}
+
+func (x *ListGrantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGrantsResponse.ProtoReflect.Descriptor instead.
+func (*ListGrantsResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListGrantsResponse) GetGrants() []*ShareGrant {
+	if x != nil {
+		return x.Grants
+	}
+	return nil
+}
+
+// Filters are optional; since/until are unix seconds
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Actor         string                 `protobuf:"bytes,1,opt,name=actor,proto3" json:"actor,omitempty"`
+	Action        string                 `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	FileId        string                 `protobuf:"bytes,3,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Result        string                 `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	Since         int64                  `protobuf:"varint,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until         int64                  `protobuf:"varint,6,opt,name=until,proto3" json:"until,omitempty"`
+	PageSize      int32                  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_fileupload_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsRequest) ProtoMessage() {}
+
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListAuditEventsRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type AuditEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OccurredAt    int64                  `protobuf:"varint,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	FileId        string                 `protobuf:"bytes,5,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	PeerIp        string                 `protobuf:"bytes,6,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`
+	UserAgent     string                 `protobuf:"bytes,7,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	Result        string                 `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	Detail        string                 `protobuf:"bytes,9,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_fileupload_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AuditEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetPeerIp() string {
+	if x != nil {
+		return x.PeerIp
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_fileupload_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type FindMissingChunksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hashes        []string               `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"` // hex SHA-256 of each chunk
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMissingChunksRequest) Reset() {
+	*x = FindMissingChunksRequest{}
+	mi := &file_fileupload_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMissingChunksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMissingChunksRequest) ProtoMessage() {}
+
+func (x *FindMissingChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMissingChunksRequest.ProtoReflect.Descriptor instead.
+func (*FindMissingChunksRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *FindMissingChunksRequest) GetHashes() []string {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+type FindMissingChunksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Missing       []string               `protobuf:"bytes,1,rep,name=missing,proto3" json:"missing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMissingChunksResponse) Reset() {
+	*x = FindMissingChunksResponse{}
+	mi := &file_fileupload_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMissingChunksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMissingChunksResponse) ProtoMessage() {}
+
+func (x *FindMissingChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMissingChunksResponse.ProtoReflect.Descriptor instead.
+func (*FindMissingChunksResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *FindMissingChunksResponse) GetMissing() []string {
+	if x != nil {
+		return x.Missing
+	}
+	return nil
+}
+
+type ContentChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"` // hex SHA-256 of content, verified by the server
+	Content       []byte                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContentChunk) Reset() {
+	*x = ContentChunk{}
+	mi := &file_fileupload_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContentChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContentChunk) ProtoMessage() {}
+
+func (x *ContentChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContentChunk.ProtoReflect.Descriptor instead.
+func (*ContentChunk) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ContentChunk) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *ContentChunk) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type PutChunksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Received      int64                  `protobuf:"varint,1,opt,name=received,proto3" json:"received,omitempty"` // chunks accepted
+	Stored        int64                  `protobuf:"varint,2,opt,name=stored,proto3" json:"stored,omitempty"`     // of which were new to the server
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutChunksResponse) Reset() {
+	*x = PutChunksResponse{}
+	mi := &file_fileupload_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutChunksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutChunksResponse) ProtoMessage() {}
+
+func (x *PutChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutChunksResponse.ProtoReflect.Descriptor instead.
+func (*PutChunksResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PutChunksResponse) GetReceived() int64 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *PutChunksResponse) GetStored() int64 {
+	if x != nil {
+		return x.Stored
+	}
+	return 0
+}
+
+type ManifestEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ManifestEntry) Reset() {
+	*x = ManifestEntry{}
+	mi := &file_fileupload_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ManifestEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ManifestEntry) ProtoMessage() {}
+
+func (x *ManifestEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ManifestEntry.ProtoReflect.Descriptor instead.
+func (*ManifestEntry) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ManifestEntry) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *ManifestEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type CommitManifestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Chunks        []*ManifestEntry       `protobuf:"bytes,2,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitManifestRequest) Reset() {
+	*x = CommitManifestRequest{}
+	mi := &file_fileupload_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitManifestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitManifestRequest) ProtoMessage() {}
+
+func (x *CommitManifestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitManifestRequest.ProtoReflect.Descriptor instead.
+func (*CommitManifestRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CommitManifestRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *CommitManifestRequest) GetChunks() []*ManifestEntry {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+type FileVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"` // the logical file
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	VersionId     string                 `protobuf:"bytes,3,opt,name=version_id,json=versionId,proto3" json:"version_id,omitempty"` // upload ID of this version
+	FileName      string                 `protobuf:"bytes,4,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Sha256        string                 `protobuf:"bytes,7,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	UploadedBy    string                 `protobuf:"bytes,8,opt,name=uploaded_by,json=uploadedBy,proto3" json:"uploaded_by,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	RestoredFrom  int64                  `protobuf:"varint,10,opt,name=restored_from,json=restoredFrom,proto3" json:"restored_from,omitempty"` // version this one restores, 0 if none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileVersion) Reset() {
+	*x = FileVersion{}
+	mi := &file_fileupload_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileVersion) ProtoMessage() {}
+
+func (x *FileVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileVersion.ProtoReflect.Descriptor instead.
+func (*FileVersion) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *FileVersion) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileVersion) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *FileVersion) GetVersionId() string {
+	if x != nil {
+		return x.VersionId
+	}
+	return ""
+}
+
+func (x *FileVersion) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *FileVersion) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileVersion) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FileVersion) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *FileVersion) GetUploadedBy() string {
+	if x != nil {
+		return x.UploadedBy
+	}
+	return ""
+}
+
+func (x *FileVersion) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *FileVersion) GetRestoredFrom() int64 {
+	if x != nil {
+		return x.RestoredFrom
+	}
+	return 0
+}
+
+type ListVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsRequest) Reset() {
+	*x = ListVersionsRequest{}
+	mi := &file_fileupload_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsRequest) ProtoMessage() {}
+
+func (x *ListVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListVersionsRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+type ListVersionsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Versions       []*FileVersion         `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"` // newest first
+	CurrentVersion int64                  `protobuf:"varint,2,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListVersionsResponse) Reset() {
+	*x = ListVersionsResponse{}
+	mi := &file_fileupload_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsResponse) ProtoMessage() {}
+
+func (x *ListVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListVersionsResponse) GetVersions() []*FileVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *ListVersionsResponse) GetCurrentVersion() int64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+type RestoreVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreVersionRequest) Reset() {
+	*x = RestoreVersionRequest{}
+	mi := &file_fileupload_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersionRequest) ProtoMessage() {}
+
+func (x *RestoreVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreVersionRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RestoreVersionRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *RestoreVersionRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type TrashItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	VersionOf     string                 `protobuf:"bytes,3,opt,name=version_of,json=versionOf,proto3" json:"version_of,omitempty"` // set when a single version was deleted
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Size          int64                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	DeletedBy     string                 `protobuf:"bytes,7,opt,name=deleted_by,json=deletedBy,proto3" json:"deleted_by,omitempty"`
+	DeletedAt     int64                  `protobuf:"varint,8,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	PurgeAt       int64                  `protobuf:"varint,9,opt,name=purge_at,json=purgeAt,proto3" json:"purge_at,omitempty"` // when the background purge removes it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashItem) Reset() {
+	*x = TrashItem{}
+	mi := &file_fileupload_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashItem) ProtoMessage() {}
+
+func (x *TrashItem) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashItem.ProtoReflect.Descriptor instead.
+func (*TrashItem) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *TrashItem) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *TrashItem) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *TrashItem) GetVersionOf() string {
+	if x != nil {
+		return x.VersionOf
+	}
+	return ""
+}
+
+func (x *TrashItem) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TrashItem) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TrashItem) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TrashItem) GetDeletedBy() string {
+	if x != nil {
+		return x.DeletedBy
+	}
+	return ""
+}
+
+func (x *TrashItem) GetDeletedAt() int64 {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return 0
+}
+
+func (x *TrashItem) GetPurgeAt() int64 {
+	if x != nil {
+		return x.PurgeAt
+	}
+	return 0
+}
+
+type ListTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashRequest) Reset() {
+	*x = ListTrashRequest{}
+	mi := &file_fileupload_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashRequest) ProtoMessage() {}
+
+func (x *ListTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashRequest.ProtoReflect.Descriptor instead.
+func (*ListTrashRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListTrashRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTrashRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListTrashResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*TrashItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"` // most recently deleted first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_fileupload_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListTrashResponse) GetItems() []*TrashItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListTrashResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type RestoreFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreFileRequest) Reset() {
+	*x = RestoreFileRequest{}
+	mi := &file_fileupload_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreFileRequest) ProtoMessage() {}
+
+func (x *RestoreFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreFileRequest.ProtoReflect.Descriptor instead.
+func (*RestoreFileRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RestoreFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+type PurgeFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeFileRequest) Reset() {
+	*x = PurgeFileRequest{}
+	mi := &file_fileupload_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeFileRequest) ProtoMessage() {}
+
+func (x *PurgeFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeFileRequest.ProtoReflect.Descriptor instead.
+func (*PurgeFileRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *PurgeFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+type RetentionPolicy struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	PolicyId            string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	Name                string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MinRetentionSeconds int64                  `protobuf:"varint,3,opt,name=min_retention_seconds,json=minRetentionSeconds,proto3" json:"min_retention_seconds,omitempty"` // deletion is refused before this age; 0 for none
+	MaxRetentionSeconds int64                  `protobuf:"varint,4,opt,name=max_retention_seconds,json=maxRetentionSeconds,proto3" json:"max_retention_seconds,omitempty"` // files are deleted automatically at this age; 0 for none
+	CreatedBy           string                 `protobuf:"bytes,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt           int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RetentionPolicy) Reset() {
+	*x = RetentionPolicy{}
+	mi := &file_fileupload_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionPolicy) ProtoMessage() {}
+
+func (x *RetentionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionPolicy.ProtoReflect.Descriptor instead.
+func (*RetentionPolicy) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RetentionPolicy) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetMinRetentionSeconds() int64 {
+	if x != nil {
+		return x.MinRetentionSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetMaxRetentionSeconds() int64 {
+	if x != nil {
+		return x.MaxRetentionSeconds
+	}
+	return 0
+}
+
+func (x *RetentionPolicy) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *RetentionPolicy) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type CreateRetentionPolicyRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Name                string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MinRetentionSeconds int64                  `protobuf:"varint,2,opt,name=min_retention_seconds,json=minRetentionSeconds,proto3" json:"min_retention_seconds,omitempty"`
+	MaxRetentionSeconds int64                  `protobuf:"varint,3,opt,name=max_retention_seconds,json=maxRetentionSeconds,proto3" json:"max_retention_seconds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CreateRetentionPolicyRequest) Reset() {
+	*x = CreateRetentionPolicyRequest{}
+	mi := &file_fileupload_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateRetentionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRetentionPolicyRequest) ProtoMessage() {}
+
+func (x *CreateRetentionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRetentionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*CreateRetentionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateRetentionPolicyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateRetentionPolicyRequest) GetMinRetentionSeconds() int64 {
+	if x != nil {
+		return x.MinRetentionSeconds
+	}
+	return 0
+}
+
+func (x *CreateRetentionPolicyRequest) GetMaxRetentionSeconds() int64 {
+	if x != nil {
+		return x.MaxRetentionSeconds
+	}
+	return 0
+}
+
+type ListRetentionPoliciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRetentionPoliciesRequest) Reset() {
+	*x = ListRetentionPoliciesRequest{}
+	mi := &file_fileupload_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRetentionPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRetentionPoliciesRequest) ProtoMessage() {}
+
+func (x *ListRetentionPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRetentionPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListRetentionPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{43}
+}
+
+type ListRetentionPoliciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policies      []*RetentionPolicy     `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRetentionPoliciesResponse) Reset() {
+	*x = ListRetentionPoliciesResponse{}
+	mi := &file_fileupload_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRetentionPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRetentionPoliciesResponse) ProtoMessage() {}
+
+func (x *ListRetentionPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRetentionPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListRetentionPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListRetentionPoliciesResponse) GetPolicies() []*RetentionPolicy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+// Assigns a policy to one file or to every file of a user. A file's own
+// policy takes precedence over its owner's. An empty policy_id clears it.
+type AssignRetentionPolicyRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	PolicyId string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*AssignRetentionPolicyRequest_FileId
+	//	*AssignRetentionPolicyRequest_UserId
+	Target        isAssignRetentionPolicyRequest_Target `protobuf_oneof:"target"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRetentionPolicyRequest) Reset() {
+	*x = AssignRetentionPolicyRequest{}
+	mi := &file_fileupload_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRetentionPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRetentionPolicyRequest) ProtoMessage() {}
+
+func (x *AssignRetentionPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRetentionPolicyRequest.ProtoReflect.Descriptor instead.
+func (*AssignRetentionPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *AssignRetentionPolicyRequest) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+func (x *AssignRetentionPolicyRequest) GetTarget() isAssignRetentionPolicyRequest_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *AssignRetentionPolicyRequest) GetFileId() string {
+	if x != nil {
+		if x, ok := x.Target.(*AssignRetentionPolicyRequest_FileId); ok {
+			return x.FileId
+		}
+	}
+	return ""
+}
+
+func (x *AssignRetentionPolicyRequest) GetUserId() string {
+	if x != nil {
+		if x, ok := x.Target.(*AssignRetentionPolicyRequest_UserId); ok {
+			return x.UserId
+		}
+	}
+	return ""
+}
+
+type isAssignRetentionPolicyRequest_Target interface {
+	isAssignRetentionPolicyRequest_Target()
+}
+
+type AssignRetentionPolicyRequest_FileId struct {
+	FileId string `protobuf:"bytes,2,opt,name=file_id,json=fileId,proto3,oneof"`
+}
+
+type AssignRetentionPolicyRequest_UserId struct {
+	UserId string `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3,oneof"`
+}
+
+func (*AssignRetentionPolicyRequest_FileId) isAssignRetentionPolicyRequest_Target() {}
+
+func (*AssignRetentionPolicyRequest_UserId) isAssignRetentionPolicyRequest_Target() {}
+
+type AssignRetentionPolicyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PolicyId      string                 `protobuf:"bytes,1,opt,name=policy_id,json=policyId,proto3" json:"policy_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignRetentionPolicyResponse) Reset() {
+	*x = AssignRetentionPolicyResponse{}
+	mi := &file_fileupload_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignRetentionPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignRetentionPolicyResponse) ProtoMessage() {}
+
+func (x *AssignRetentionPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignRetentionPolicyResponse.ProtoReflect.Descriptor instead.
+func (*AssignRetentionPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *AssignRetentionPolicyResponse) GetPolicyId() string {
+	if x != nil {
+		return x.PolicyId
+	}
+	return ""
+}
+
+type SetLegalHoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Hold          bool                   `protobuf:"varint,2,opt,name=hold,proto3" json:"hold,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLegalHoldRequest) Reset() {
+	*x = SetLegalHoldRequest{}
+	mi := &file_fileupload_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLegalHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLegalHoldRequest) ProtoMessage() {}
+
+func (x *SetLegalHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLegalHoldRequest.ProtoReflect.Descriptor instead.
+func (*SetLegalHoldRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SetLegalHoldRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *SetLegalHoldRequest) GetHold() bool {
+	if x != nil {
+		return x.Hold
+	}
+	return false
+}
+
+func (x *SetLegalHoldRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type LegalHold struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Hold          bool                   `protobuf:"varint,2,opt,name=hold,proto3" json:"hold,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	SetBy         string                 `protobuf:"bytes,4,opt,name=set_by,json=setBy,proto3" json:"set_by,omitempty"`
+	SetAt         int64                  `protobuf:"varint,5,opt,name=set_at,json=setAt,proto3" json:"set_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LegalHold) Reset() {
+	*x = LegalHold{}
+	mi := &file_fileupload_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LegalHold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LegalHold) ProtoMessage() {}
+
+func (x *LegalHold) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LegalHold.ProtoReflect.Descriptor instead.
+func (*LegalHold) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *LegalHold) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *LegalHold) GetHold() bool {
+	if x != nil {
+		return x.Hold
+	}
+	return false
+}
+
+func (x *LegalHold) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *LegalHold) GetSetBy() string {
+	if x != nil {
+		return x.SetBy
+	}
+	return ""
+}
+
+func (x *LegalHold) GetSetAt() int64 {
+	if x != nil {
+		return x.SetAt
+	}
+	return 0
+}
+
+type Folder struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // empty at the top level
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Folder) Reset() {
+	*x = Folder{}
+	mi := &file_fileupload_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Folder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Folder) ProtoMessage() {}
+
+func (x *Folder) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Folder.ProtoReflect.Descriptor instead.
+func (*Folder) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Folder) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *Folder) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Folder) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Folder) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Folder) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// A file as it appears in listings, described by its current version
+type FileEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	FolderId      string                 `protobuf:"bytes,3,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags          []string               `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileEntry) Reset() {
+	*x = FileEntry{}
+	mi := &file_fileupload_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileEntry) ProtoMessage() {}
+
+func (x *FileEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileEntry.ProtoReflect.Descriptor instead.
+func (*FileEntry) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *FileEntry) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *FileEntry) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *FileEntry) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *FileEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileEntry) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *FileEntry) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *FileEntry) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *FileEntry) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *FileEntry) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type CreateFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ParentId      string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // empty for the top level
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFolderRequest) Reset() {
+	*x = CreateFolderRequest{}
+	mi := &file_fileupload_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFolderRequest) ProtoMessage() {}
+
+func (x *CreateFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFolderRequest.ProtoReflect.Descriptor instead.
+func (*CreateFolderRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CreateFolderRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateFolderRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+type RenameFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFolderRequest) Reset() {
+	*x = RenameFolderRequest{}
+	mi := &file_fileupload_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFolderRequest) ProtoMessage() {}
+
+func (x *RenameFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFolderRequest.ProtoReflect.Descriptor instead.
+func (*RenameFolderRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RenameFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *RenameFolderRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type MoveFolderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FolderId      string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	ParentId      string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // empty moves the folder to the top level
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFolderRequest) Reset() {
+	*x = MoveFolderRequest{}
+	mi := &file_fileupload_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFolderRequest) ProtoMessage() {}
+
+func (x *MoveFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFolderRequest.ProtoReflect.Descriptor instead.
+func (*MoveFolderRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *MoveFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *MoveFolderRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+type DeleteFolderRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	FolderId string                 `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	// Also delete subfolders and move contained files to the trash;
+	// without it only empty folders can be deleted
+	Recursive     bool `protobuf:"varint,2,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteFolderRequest) Reset() {
+	*x = DeleteFolderRequest{}
+	mi := &file_fileupload_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteFolderRequest) ProtoMessage() {}
+
+func (x *DeleteFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteFolderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteFolderRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DeleteFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *DeleteFolderRequest) GetRecursive() bool {
+	if x != nil {
+		return x.Recursive
+	}
+	return false
+}
+
+type ListFolderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Folder by ID or path; the top level when both are empty
+	FolderId  string `protobuf:"bytes,1,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Path      string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	PageSize  int32  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only list files having all of these metadata entries and tags
+	MetadataFilter map[string]string `protobuf:"bytes,5,rep,name=metadata_filter,json=metadataFilter,proto3" json:"metadata_filter,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags           []string          `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListFolderRequest) Reset() {
+	*x = ListFolderRequest{}
+	mi := &file_fileupload_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFolderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFolderRequest) ProtoMessage() {}
+
+func (x *ListFolderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFolderRequest.ProtoReflect.Descriptor instead.
+func (*ListFolderRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListFolderRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *ListFolderRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ListFolderRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListFolderRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListFolderRequest) GetMetadataFilter() map[string]string {
+	if x != nil {
+		return x.MetadataFilter
+	}
+	return nil
+}
+
+func (x *ListFolderRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type ListFolderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Folder        *Folder                `protobuf:"bytes,1,opt,name=folder,proto3" json:"folder,omitempty"` // unset at the top level
+	Folders       []*Folder              `protobuf:"bytes,2,rep,name=folders,proto3" json:"folders,omitempty"`
+	Files         []*FileEntry           `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,4,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFolderResponse) Reset() {
+	*x = ListFolderResponse{}
+	mi := &file_fileupload_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFolderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFolderResponse) ProtoMessage() {}
+
+func (x *ListFolderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFolderResponse.ProtoReflect.Descriptor instead.
+func (*ListFolderResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListFolderResponse) GetFolder() *Folder {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+func (x *ListFolderResponse) GetFolders() []*Folder {
+	if x != nil {
+		return x.Folders
+	}
+	return nil
+}
+
+func (x *ListFolderResponse) GetFiles() []*FileEntry {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ListFolderResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type LookupPathRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"` // e.g. "/projects/q3/report.pdf"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupPathRequest) Reset() {
+	*x = LookupPathRequest{}
+	mi := &file_fileupload_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupPathRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupPathRequest) ProtoMessage() {}
+
+func (x *LookupPathRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupPathRequest.ProtoReflect.Descriptor instead.
+func (*LookupPathRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *LookupPathRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type LookupPathResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Entry:
+	//
+	//	*LookupPathResponse_Folder
+	//	*LookupPathResponse_File
+	Entry         isLookupPathResponse_Entry `protobuf_oneof:"entry"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupPathResponse) Reset() {
+	*x = LookupPathResponse{}
+	mi := &file_fileupload_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupPathResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupPathResponse) ProtoMessage() {}
+
+func (x *LookupPathResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupPathResponse.ProtoReflect.Descriptor instead.
+func (*LookupPathResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *LookupPathResponse) GetEntry() isLookupPathResponse_Entry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *LookupPathResponse) GetFolder() *Folder {
+	if x != nil {
+		if x, ok := x.Entry.(*LookupPathResponse_Folder); ok {
+			return x.Folder
+		}
+	}
+	return nil
+}
+
+func (x *LookupPathResponse) GetFile() *FileEntry {
+	if x != nil {
+		if x, ok := x.Entry.(*LookupPathResponse_File); ok {
+			return x.File
+		}
+	}
+	return nil
+}
+
+type isLookupPathResponse_Entry interface {
+	isLookupPathResponse_Entry()
+}
+
+type LookupPathResponse_Folder struct {
+	Folder *Folder `protobuf:"bytes,1,opt,name=folder,proto3,oneof"`
+}
+
+type LookupPathResponse_File struct {
+	File *FileEntry `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+func (*LookupPathResponse_Folder) isLookupPathResponse_Entry() {}
+
+func (*LookupPathResponse_File) isLookupPathResponse_Entry() {}
+
+type RenameFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameFileRequest) Reset() {
+	*x = RenameFileRequest{}
+	mi := &file_fileupload_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameFileRequest) ProtoMessage() {}
+
+func (x *RenameFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameFileRequest.ProtoReflect.Descriptor instead.
+func (*RenameFileRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *RenameFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *RenameFileRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+type MoveFileRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	FileId string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	// Destination folder by ID or path; the top level when both are empty
+	FolderId      string `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	FolderPath    string `protobuf:"bytes,3,opt,name=folder_path,json=folderPath,proto3" json:"folder_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveFileRequest) Reset() {
+	*x = MoveFileRequest{}
+	mi := &file_fileupload_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveFileRequest) ProtoMessage() {}
+
+func (x *MoveFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveFileRequest.ProtoReflect.Descriptor instead.
+func (*MoveFileRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *MoveFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *MoveFileRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *MoveFileRequest) GetFolderPath() string {
+	if x != nil {
+		return x.FolderPath
+	}
+	return ""
+}
+
+// Copies the current version of a file into a new file owned by the caller.
+// Content is shared with the source, not duplicated.
+type CopyFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	FolderPath    string                 `protobuf:"bytes,3,opt,name=folder_path,json=folderPath,proto3" json:"folder_path,omitempty"`
+	FileName      string                 `protobuf:"bytes,4,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"` // defaults to the source's name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyFileRequest) Reset() {
+	*x = CopyFileRequest{}
+	mi := &file_fileupload_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyFileRequest) ProtoMessage() {}
+
+func (x *CopyFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyFileRequest.ProtoReflect.Descriptor instead.
+func (*CopyFileRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CopyFileRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *CopyFileRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *CopyFileRequest) GetFolderPath() string {
+	if x != nil {
+		return x.FolderPath
+	}
+	return ""
+}
+
+func (x *CopyFileRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+// Changes the user metadata and tags of a file. Entries in metadata are set,
+// overwriting existing values; removals are applied after additions.
+type UpdateFileMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RemoveKeys    []string               `protobuf:"bytes,3,rep,name=remove_keys,json=removeKeys,proto3" json:"remove_keys,omitempty"`
+	AddTags       []string               `protobuf:"bytes,4,rep,name=add_tags,json=addTags,proto3" json:"add_tags,omitempty"`
+	RemoveTags    []string               `protobuf:"bytes,5,rep,name=remove_tags,json=removeTags,proto3" json:"remove_tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFileMetadataRequest) Reset() {
+	*x = UpdateFileMetadataRequest{}
+	mi := &file_fileupload_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFileMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFileMetadataRequest) ProtoMessage() {}
+
+func (x *UpdateFileMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFileMetadataRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFileMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *UpdateFileMetadataRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *UpdateFileMetadataRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UpdateFileMetadataRequest) GetRemoveKeys() []string {
+	if x != nil {
+		return x.RemoveKeys
+	}
+	return nil
+}
+
+func (x *UpdateFileMetadataRequest) GetAddTags() []string {
+	if x != nil {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *UpdateFileMetadataRequest) GetRemoveTags() []string {
+	if x != nil {
+		return x.RemoveTags
+	}
+	return nil
+}
+
+type SearchFilesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Web search syntax: words, "quoted phrases", OR, and -excluded words
+	Query     string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize  int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only return files having all of these metadata entries and tags
+	MetadataFilter map[string]string `protobuf:"bytes,4,rep,name=metadata_filter,json=metadataFilter,proto3" json:"metadata_filter,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags           []string          `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Only return files the caller owns
+	OwnedOnly     bool `protobuf:"varint,6,opt,name=owned_only,json=ownedOnly,proto3" json:"owned_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchFilesRequest) Reset() {
+	*x = SearchFilesRequest{}
+	mi := &file_fileupload_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFilesRequest) ProtoMessage() {}
+
+func (x *SearchFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFilesRequest.ProtoReflect.Descriptor instead.
+func (*SearchFilesRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *SearchFilesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchFilesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchFilesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchFilesRequest) GetMetadataFilter() map[string]string {
+	if x != nil {
+		return x.MetadataFilter
+	}
+	return nil
+}
+
+func (x *SearchFilesRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *SearchFilesRequest) GetOwnedOnly() bool {
+	if x != nil {
+		return x.OwnedOnly
+	}
+	return false
+}
+
+type SearchResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	File  *FileEntry             `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Rank  float32                `protobuf:"fixed32,2,opt,name=rank,proto3" json:"rank,omitempty"`
+	// Matching excerpt with matches wrapped in <mark></mark>
+	Snippet       string `protobuf:"bytes,3,opt,name=snippet,proto3" json:"snippet,omitempty"`
+	Shared        bool   `protobuf:"varint,4,opt,name=shared,proto3" json:"shared,omitempty"` // owned by another user and readable through a grant
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_fileupload_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *SearchResult) GetFile() *FileEntry {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+func (x *SearchResult) GetRank() float32 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *SearchResult) GetSnippet() string {
+	if x != nil {
+		return x.Snippet
+	}
+	return ""
+}
+
+func (x *SearchResult) GetShared() bool {
+	if x != nil {
+		return x.Shared
+	}
+	return false
+}
+
+type SearchFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchFilesResponse) Reset() {
+	*x = SearchFilesResponse{}
+	mi := &file_fileupload_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchFilesResponse) ProtoMessage() {}
+
+func (x *SearchFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchFilesResponse.ProtoReflect.Descriptor instead.
+func (*SearchFilesResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *SearchFilesResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SearchFilesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Names either files or one folder of the caller; a folder is archived
+// with its subfolders
+type DownloadArchiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileIds       []string               `protobuf:"bytes,1,rep,name=file_ids,json=fileIds,proto3" json:"file_ids,omitempty"`
+	FolderId      string                 `protobuf:"bytes,2,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	Path          string                 `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Format        string                 `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"` // "zip" (default) or "tar.gz"
+	Zip64         bool                   `protobuf:"varint,5,opt,name=zip64,proto3" json:"zip64,omitempty"`  // allow ZIP64 for archives of 4 GiB or more, or 65535 entries or more
+	Name          string                 `protobuf:"bytes,6,opt,name=name,proto3" json:"name,omitempty"`     // archive file name without extension
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadArchiveRequest) Reset() {
+	*x = DownloadArchiveRequest{}
+	mi := &file_fileupload_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadArchiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadArchiveRequest) ProtoMessage() {}
+
+func (x *DownloadArchiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadArchiveRequest.ProtoReflect.Descriptor instead.
+func (*DownloadArchiveRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *DownloadArchiveRequest) GetFileIds() []string {
+	if x != nil {
+		return x.FileIds
+	}
+	return nil
+}
+
+func (x *DownloadArchiveRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *DownloadArchiveRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DownloadArchiveRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *DownloadArchiveRequest) GetZip64() bool {
+	if x != nil {
+		return x.Zip64
+	}
+	return false
+}
+
+func (x *DownloadArchiveRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// The first message carries the archive's name and content type; every
+// message carries the next bytes of the archive
+type ArchiveChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       []byte                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveChunk) Reset() {
+	*x = ArchiveChunk{}
+	mi := &file_fileupload_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveChunk) ProtoMessage() {}
+
+func (x *ArchiveChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveChunk.ProtoReflect.Descriptor instead.
+func (*ArchiveChunk) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ArchiveChunk) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ArchiveChunk) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *ArchiveChunk) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type Tenant struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TenantId    string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	QuotaBytes  int64                  `protobuf:"varint,3,opt,name=quota_bytes,json=quotaBytes,proto3" json:"quota_bytes,omitempty"`      // total size of the tenant's files; 0 for unlimited
+	MaxFileSize int64                  `protobuf:"varint,4,opt,name=max_file_size,json=maxFileSize,proto3" json:"max_file_size,omitempty"` // 0 for unlimited
+	// JSON in the format of CONTENT_POLICY_FILE, layered over the deployment
+	// policy; empty for none
+	ContentPolicy string `protobuf:"bytes,5,opt,name=content_policy,json=contentPolicy,proto3" json:"content_policy,omitempty"`
+	Disabled      bool   `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"` // requests for a disabled tenant are refused
+	UsedBytes     int64  `protobuf:"varint,7,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	FileCount     int64  `protobuf:"varint,8,opt,name=file_count,json=fileCount,proto3" json:"file_count,omitempty"`
+	CreatedAt     int64  `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_fileupload_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *Tenant) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Tenant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tenant) GetQuotaBytes() int64 {
+	if x != nil {
+		return x.QuotaBytes
+	}
+	return 0
+}
+
+func (x *Tenant) GetMaxFileSize() int64 {
+	if x != nil {
+		return x.MaxFileSize
+	}
+	return 0
+}
+
+func (x *Tenant) GetContentPolicy() string {
+	if x != nil {
+		return x.ContentPolicy
+	}
+	return ""
+}
+
+func (x *Tenant) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+func (x *Tenant) GetUsedBytes() int64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *Tenant) GetFileCount() int64 {
+	if x != nil {
+		return x.FileCount
+	}
+	return 0
+}
+
+func (x *Tenant) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type CreateTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"` // lowercase letters, digits and dashes
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	QuotaBytes    int64                  `protobuf:"varint,3,opt,name=quota_bytes,json=quotaBytes,proto3" json:"quota_bytes,omitempty"`
+	MaxFileSize   int64                  `protobuf:"varint,4,opt,name=max_file_size,json=maxFileSize,proto3" json:"max_file_size,omitempty"`
+	ContentPolicy string                 `protobuf:"bytes,5,opt,name=content_policy,json=contentPolicy,proto3" json:"content_policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTenantRequest) Reset() {
+	*x = CreateTenantRequest{}
+	mi := &file_fileupload_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTenantRequest) ProtoMessage() {}
+
+func (x *CreateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTenantRequest.ProtoReflect.Descriptor instead.
+func (*CreateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *CreateTenantRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CreateTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTenantRequest) GetQuotaBytes() int64 {
+	if x != nil {
+		return x.QuotaBytes
+	}
+	return 0
+}
+
+func (x *CreateTenantRequest) GetMaxFileSize() int64 {
+	if x != nil {
+		return x.MaxFileSize
+	}
+	return 0
+}
+
+func (x *CreateTenantRequest) GetContentPolicy() string {
+	if x != nil {
+		return x.ContentPolicy
+	}
+	return ""
+}
+
+type ListTenantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsRequest) Reset() {
+	*x = ListTenantsRequest{}
+	mi := &file_fileupload_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsRequest) ProtoMessage() {}
+
+func (x *ListTenantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{70}
+}
+
+type ListTenantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenants       []*Tenant              `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsResponse) Reset() {
+	*x = ListTenantsResponse{}
+	mi := &file_fileupload_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsResponse) ProtoMessage() {}
+
+func (x *ListTenantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantsResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ListTenantsResponse) GetTenants() []*Tenant {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+type GetTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTenantRequest) Reset() {
+	*x = GetTenantRequest{}
+	mi := &file_fileupload_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantRequest) ProtoMessage() {}
+
+func (x *GetTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantRequest.ProtoReflect.Descriptor instead.
+func (*GetTenantRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetTenantRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// Replaces every setting of a tenant
+type UpdateTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	QuotaBytes    int64                  `protobuf:"varint,3,opt,name=quota_bytes,json=quotaBytes,proto3" json:"quota_bytes,omitempty"`
+	MaxFileSize   int64                  `protobuf:"varint,4,opt,name=max_file_size,json=maxFileSize,proto3" json:"max_file_size,omitempty"`
+	ContentPolicy string                 `protobuf:"bytes,5,opt,name=content_policy,json=contentPolicy,proto3" json:"content_policy,omitempty"`
+	Disabled      bool                   `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTenantRequest) Reset() {
+	*x = UpdateTenantRequest{}
+	mi := &file_fileupload_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTenantRequest) ProtoMessage() {}
+
+func (x *UpdateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTenantRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *UpdateTenantRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetQuotaBytes() int64 {
+	if x != nil {
+		return x.QuotaBytes
+	}
+	return 0
+}
+
+func (x *UpdateTenantRequest) GetMaxFileSize() int64 {
+	if x != nil {
+		return x.MaxFileSize
+	}
+	return 0
+}
+
+func (x *UpdateTenantRequest) GetContentPolicy() string {
+	if x != nil {
+		return x.ContentPolicy
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type ImportFromURLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`                           // http or https, without credentials
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"` // defaults to the last segment of the URL path
+	FolderId      string                 `protobuf:"bytes,3,opt,name=folder_id,json=folderId,proto3" json:"folder_id,omitempty"`
+	FolderPath    string                 `protobuf:"bytes,4,opt,name=folder_path,json=folderPath,proto3" json:"folder_path,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Sha256        string                 `protobuf:"bytes,7,opt,name=sha256,proto3" json:"sha256,omitempty"` // optional hex SHA-256 the fetched content must match
+	Extract       bool                   `protobuf:"varint,8,opt,name=extract,proto3" json:"extract,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportFromURLRequest) Reset() {
+	*x = ImportFromURLRequest{}
+	mi := &file_fileupload_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportFromURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportFromURLRequest) ProtoMessage() {}
+
+func (x *ImportFromURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportFromURLRequest.ProtoReflect.Descriptor instead.
+func (*ImportFromURLRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ImportFromURLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImportFromURLRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *ImportFromURLRequest) GetFolderId() string {
+	if x != nil {
+		return x.FolderId
+	}
+	return ""
+}
+
+func (x *ImportFromURLRequest) GetFolderPath() string {
+	if x != nil {
+		return x.FolderPath
+	}
+	return ""
+}
+
+func (x *ImportFromURLRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ImportFromURLRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ImportFromURLRequest) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ImportFromURLRequest) GetExtract() bool {
+	if x != nil {
+		return x.Extract
+	}
+	return false
+}
+
+type ImportFromURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportFromURLResponse) Reset() {
+	*x = ImportFromURLResponse{}
+	mi := &file_fileupload_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportFromURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportFromURLResponse) ProtoMessage() {}
+
+func (x *ImportFromURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportFromURLResponse.ProtoReflect.Descriptor instead.
+func (*ImportFromURLResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ImportFromURLResponse) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+type WatchUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileId        string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchUploadRequest) Reset() {
+	*x = WatchUploadRequest{}
+	mi := &file_fileupload_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchUploadRequest) ProtoMessage() {}
+
+func (x *WatchUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchUploadRequest.ProtoReflect.Descriptor instead.
+func (*WatchUploadRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *WatchUploadRequest) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+// An event in the life of an upload. type is one of chunk_received,
+// bytes_received, merge_started, completed or failed.
+type UploadEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	FileId         string                 `protobuf:"bytes,1,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	ChunkIndex     int64                  `protobuf:"varint,3,opt,name=chunk_index,json=chunkIndex,proto3" json:"chunk_index,omitempty"` // chunk_received
+	ChunksReceived int64                  `protobuf:"varint,4,opt,name=chunks_received,json=chunksReceived,proto3" json:"chunks_received,omitempty"`
+	TotalChunks    int64                  `protobuf:"varint,5,opt,name=total_chunks,json=totalChunks,proto3" json:"total_chunks,omitempty"` // 0 while unknown
+	BytesReceived  int64                  `protobuf:"varint,6,opt,name=bytes_received,json=bytesReceived,proto3" json:"bytes_received,omitempty"`
+	TotalBytes     int64                  `protobuf:"varint,7,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`  // 0 while unknown
+	Status         string                 `protobuf:"bytes,8,opt,name=status,proto3" json:"status,omitempty"`                             // upload status: in_progress, completed, failed or quarantined
+	Error          string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`                               // failed
+	OccurredAt     int64                  `protobuf:"varint,10,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"` // unix milliseconds
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UploadEvent) Reset() {
+	*x = UploadEvent{}
+	mi := &file_fileupload_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadEvent) ProtoMessage() {}
+
+func (x *UploadEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadEvent.ProtoReflect.Descriptor instead.
+func (*UploadEvent) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *UploadEvent) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *UploadEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *UploadEvent) GetChunkIndex() int64 {
+	if x != nil {
+		return x.ChunkIndex
+	}
+	return 0
+}
+
+func (x *UploadEvent) GetChunksReceived() int64 {
+	if x != nil {
+		return x.ChunksReceived
+	}
+	return 0
+}
+
+func (x *UploadEvent) GetTotalChunks() int64 {
+	if x != nil {
+		return x.TotalChunks
+	}
+	return 0
+}
+
+func (x *UploadEvent) GetBytesReceived() int64 {
+	if x != nil {
+		return x.BytesReceived
+	}
+	return 0
+}
+
+func (x *UploadEvent) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *UploadEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UploadEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *UploadEvent) GetOccurredAt() int64 {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return 0
+}
+
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	TenantWide    bool                   `protobuf:"varint,4,opt,name=tenant_wide,json=tenantWide,proto3" json:"tenant_wide,omitempty"` // all files of the tenant rather than the owner's
+	OwnerId       string                 `protobuf:"bytes,5,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Secret        string                 `protobuf:"bytes,6,opt,name=secret,proto3" json:"secret,omitempty"` // only set by CreateWebhook
+	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_fileupload_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *Webhook) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Webhook) GetTenantWide() bool {
+	if x != nil {
+		return x.TenantWide
+	}
+	return false
+}
+
+func (x *Webhook) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Events        []string               `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`                            // empty subscribes to every event
+	TenantWide    bool                   `protobuf:"varint,3,opt,name=tenant_wide,json=tenantWide,proto3" json:"tenant_wide,omitempty"` // administrators only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_fileupload_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEvents() []string {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *CreateWebhookRequest) GetTenantWide() bool {
+	if x != nil {
+		return x.TenantWide
+	}
+	return false
+}
+
+type ListWebhooksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksRequest) Reset() {
+	*x = ListWebhooksRequest{}
+	mi := &file_fileupload_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksRequest) ProtoMessage() {}
+
+func (x *ListWebhooksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{80}
+}
+
+type ListWebhooksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhooksResponse) Reset() {
+	*x = ListWebhooksResponse{}
+	mi := &file_fileupload_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhooksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhooksResponse) ProtoMessage() {}
+
+func (x *ListWebhooksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhooksResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWebhookRequest) Reset() {
+	*x = DeleteWebhookRequest{}
+	mi := &file_fileupload_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWebhookRequest) ProtoMessage() {}
+
+func (x *DeleteWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWebhookRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *DeleteWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+// One event sent to one webhook, with the outcome of its latest attempt
+type WebhookDelivery struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId      string                 `protobuf:"bytes,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventId        string                 `protobuf:"bytes,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType      string                 `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	FileId         string                 `protobuf:"bytes,5,opt,name=file_id,json=fileId,proto3" json:"file_id,omitempty"`
+	Status         string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // pending, delivered or dead
+	Attempts       int32                  `protobuf:"varint,7,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	ResponseStatus int32                  `protobuf:"varint,8,opt,name=response_status,json=responseStatus,proto3" json:"response_status,omitempty"` // HTTP status of the latest attempt; 0 if none was received
+	Error          string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	Payload        string                 `protobuf:"bytes,10,opt,name=payload,proto3" json:"payload,omitempty"` // the JSON body sent
+	CreatedAt      int64                  `protobuf:"varint,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	NextAttemptAt  int64                  `protobuf:"varint,12,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"` // while pending
+	DeliveredAt    int64                  `protobuf:"varint,13,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_fileupload_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *WebhookDelivery) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetFileId() string {
+	if x != nil {
+		return x.FileId
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetResponseStatus() int32 {
+	if x != nil {
+		return x.ResponseStatus
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() int64 {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() int64 {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	PageSize      int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_fileupload_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *ListWebhookDeliveriesRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListWebhookDeliveriesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_fileupload_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+func (x *ListWebhookDeliveriesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type RedeliverWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     string                 `protobuf:"bytes,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	DeliveryId    int64                  `protobuf:"varint,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeliverWebhookRequest) Reset() {
+	*x = RedeliverWebhookRequest{}
+	mi := &file_fileupload_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeliverWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeliverWebhookRequest) ProtoMessage() {}
+
+func (x *RedeliverWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_fileupload_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeliverWebhookRequest.ProtoReflect.Descriptor instead.
+func (*RedeliverWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_fileupload_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *RedeliverWebhookRequest) GetWebhookId() string {
+	if x != nil {
+		return x.WebhookId
+	}
+	return ""
+}
+
+func (x *RedeliverWebhookRequest) GetDeliveryId() int64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+var File_fileupload_proto protoreflect.FileDescriptor
+
+const file_fileupload_proto_rawDesc = "" +
+	"\n" +
+	"\x10fileupload.proto\x12\x02pb\x1a\x1cgoogle/api/annotations.proto\"\xb8\x01\n" +
+	"\tFileChunk\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vchunk_index\x18\x04 \x01(\x03R\n" +
+	"chunkIndex\x12!\n" +
+	"\ftotal_chunks\x18\x05 \x01(\x03R\vtotalChunks\x12\x18\n" +
+	"\acontent\x18\x06 \x01(\fR\acontent\"D\n" +
+	"\x0fDownloadRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"\xe7\x01\n" +
+	"\x10DownloadResponse\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12+\n" +
+	"\x11encryption_scheme\x18\x03 \x01(\tR\x10encryptionScheme\x12\x1f\n" +
+	"\vwrapped_key\x18\x04 \x01(\fR\n" +
+	"wrappedKey\x12\x15\n" +
+	"\x06key_id\x18\x05 \x01(\tR\x05keyId\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\a \x01(\tR\tversionId\"c\n" +
+	"\fUploadStatus\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vstored_path\x18\x03 \x01(\tR\n" +
+	"storedPath\"+\n" +
+	"\x10GetChunksRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"<\n" +
+	"\x11GetChunksResponse\x12'\n" +
+	"\x0fuploaded_chunks\x18\x01 \x03(\x03R\x0euploadedChunks\"-\n" +
 	"\x12GetMetadataRequest\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\"\x9b\x01\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"\xd0\x04\n" +
 	"\x0eUploadMetadata\x12\x17\n" +
 	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
 	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x12\n" +
 	"\x04size\x18\x03 \x01(\x03R\x04size\x12'\n" +
 	"\x0fuploaded_chunks\x18\x04 \x03(\x03R\x0euploadedChunks\x12\x16\n" +
-	"\x06status\x18\x05 \x01(\tR\x06status\"f\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12+\n" +
+	"\x11encryption_scheme\x18\x06 \x01(\tR\x10encryptionScheme\x12\x1f\n" +
+	"\vwrapped_key\x18\a \x01(\fR\n" +
+	"wrappedKey\x12\x15\n" +
+	"\x06key_id\x18\b \x01(\tR\x05keyId\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"legal_hold\x18\n" +
+	" \x01(\bR\tlegalHold\x12!\n" +
+	"\fretain_until\x18\v \x01(\x03R\vretainUntil\x12<\n" +
+	"\bmetadata\x18\f \x03(\v2 .pb.UploadMetadata.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\r \x03(\tR\x04tags\x125\n" +
+	"\n" +
+	"extraction\x18\x0e \x01(\v2\x15.pb.ArchiveExtractionR\n" +
+	"extraction\x12,\n" +
+	"\n" +
+	"url_import\x18\x0f \x01(\v2\r.pb.URLImportR\turlImport\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbc\x01\n" +
+	"\tURLImport\x12\x1d\n" +
+	"\n" +
+	"source_url\x18\x01 \x01(\tR\tsourceUrl\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12%\n" +
+	"\x0ebytes_received\x18\x03 \x01(\x03R\rbytesReceived\x12\x1f\n" +
+	"\vtotal_bytes\x18\x04 \x01(\x03R\n" +
+	"totalBytes\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"\x8e\x01\n" +
+	"\x11ArchiveExtraction\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x14\n" +
+	"\x05files\x18\x03 \x01(\x05R\x05files\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\x05R\askipped\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"\xb1\x05\n" +
 	"\vInitRequest\x12\x1b\n" +
 	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12!\n" +
 	"\ftotal_chunks\x18\x02 \x01(\x03R\vtotalChunks\x12\x17\n" +
-	"\auser_id\x18\x03 \x01(\tR\x06userId\"'\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12,\n" +
+	"\x12issue_upload_token\x18\x04 \x01(\bR\x10issueUploadToken\x12\x19\n" +
+	"\bmax_size\x18\x05 \x01(\x03R\amaxSize\x12,\n" +
+	"\x12allowed_mime_types\x18\x06 \x03(\tR\x10allowedMimeTypes\x127\n" +
+	"\x18upload_token_ttl_seconds\x18\a \x01(\x03R\x15uploadTokenTtlSeconds\x12+\n" +
+	"\x11encryption_scheme\x18\b \x01(\tR\x10encryptionScheme\x12\x1f\n" +
+	"\vwrapped_key\x18\t \x01(\fR\n" +
+	"wrappedKey\x12\x15\n" +
+	"\x06key_id\x18\n" +
+	" \x01(\tR\x05keyId\x12\x16\n" +
+	"\x06sha256\x18\v \x01(\tR\x06sha256\x12\x12\n" +
+	"\x04size\x18\f \x01(\x03R\x04size\x12$\n" +
+	"\x0etarget_file_id\x18\r \x01(\tR\ftargetFileId\x12\x1b\n" +
+	"\tfolder_id\x18\x0e \x01(\tR\bfolderId\x12\x1f\n" +
+	"\vfolder_path\x18\x0f \x01(\tR\n" +
+	"folderPath\x129\n" +
+	"\bmetadata\x18\x10 \x03(\v2\x1d.pb.InitRequest.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\x11 \x03(\tR\x04tags\x12\x18\n" +
+	"\aextract\x18\x12 \x01(\bR\aextract\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc6\x01\n" +
 	"\fInitResponse\x12\x17\n" +
-	"\afile_id\x18\x01 \x01(\tR\x06fileId\"(\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12!\n" +
+	"\fupload_token\x18\x02 \x01(\tR\vuploadToken\x125\n" +
+	"\x17upload_token_expires_at\x18\x03 \x01(\x03R\x14uploadTokenExpiresAt\x12)\n" +
+	"\x10already_uploaded\x18\x04 \x01(\bR\x0falreadyUploaded\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\"(\n" +
 	"\rDeleteRequest\x12\x17\n" +
 	"\afile_id\x18\x01 \x01(\tR\x06fileId\"D\n" +
 	"\x0eDeleteResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\xca\x03\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xa3\x01\n" +
+	"\x16CreateSignedURLRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12,\n" +
+	"\x12expires_in_seconds\x18\x02 \x01(\x03R\x10expiresInSeconds\x12#\n" +
+	"\rmax_downloads\x18\x03 \x01(\x03R\fmaxDownloads\x12\x1d\n" +
+	"\n" +
+	"allowed_ip\x18\x04 \x01(\tR\tallowedIp\"a\n" +
+	"\x17CreateSignedURLResponse\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x15\n" +
+	"\x06url_id\x18\x02 \x01(\tR\x05urlId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\"\xc8\x01\n" +
+	"\x15SignedDownloadRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x15\n" +
+	"\x06url_id\x18\x02 \x01(\tR\x05urlId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\x12#\n" +
+	"\rmax_downloads\x18\x04 \x01(\x03R\fmaxDownloads\x12\x1d\n" +
+	"\n" +
+	"allowed_ip\x18\x05 \x01(\tR\tallowedIp\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\tR\tsignature\"\xbd\x01\n" +
+	"\x10ShareFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12!\n" +
+	"\fgrantee_type\x18\x02 \x01(\tR\vgranteeType\x12\x1d\n" +
+	"\n" +
+	"grantee_id\x18\x03 \x01(\tR\tgranteeId\x12 \n" +
+	"\vpermissions\x18\x04 \x03(\tR\vpermissions\x12,\n" +
+	"\x12expires_in_seconds\x18\x05 \x01(\x03R\x10expiresInSeconds\"\x81\x02\n" +
+	"\n" +
+	"ShareGrant\x12\x19\n" +
+	"\bgrant_id\x18\x01 \x01(\tR\agrantId\x12\x17\n" +
+	"\afile_id\x18\x02 \x01(\tR\x06fileId\x12!\n" +
+	"\fgrantee_type\x18\x03 \x01(\tR\vgranteeType\x12\x1d\n" +
+	"\n" +
+	"grantee_id\x18\x04 \x01(\tR\tgranteeId\x12 \n" +
+	"\vpermissions\x18\x05 \x03(\tR\vpermissions\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"link_token\x18\b \x01(\tR\tlinkToken\"H\n" +
+	"\x12UnshareFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x19\n" +
+	"\bgrant_id\x18\x02 \x01(\tR\agrantId\"/\n" +
+	"\x13UnshareFileResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\",\n" +
+	"\x11ListGrantsRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"<\n" +
+	"\x12ListGrantsResponse\x12&\n" +
+	"\x06grants\x18\x01 \x03(\v2\x0e.pb.ShareGrantR\x06grants\"\xdf\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x14\n" +
+	"\x05actor\x18\x01 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x17\n" +
+	"\afile_id\x18\x03 \x01(\tR\x06fileId\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\tR\x06result\x12\x14\n" +
+	"\x05since\x18\x05 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05until\x18\x06 \x01(\x03R\x05until\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\"\xec\x01\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1f\n" +
+	"\voccurred_at\x18\x02 \x01(\x03R\n" +
+	"occurredAt\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x17\n" +
+	"\afile_id\x18\x05 \x01(\tR\x06fileId\x12\x17\n" +
+	"\apeer_ip\x18\x06 \x01(\tR\x06peerIp\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\a \x01(\tR\tuserAgent\x12\x16\n" +
+	"\x06result\x18\b \x01(\tR\x06result\x12\x16\n" +
+	"\x06detail\x18\t \x01(\tR\x06detail\"i\n" +
+	"\x17ListAuditEventsResponse\x12&\n" +
+	"\x06events\x18\x01 \x03(\v2\x0e.pb.AuditEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"2\n" +
+	"\x18FindMissingChunksRequest\x12\x16\n" +
+	"\x06hashes\x18\x01 \x03(\tR\x06hashes\"5\n" +
+	"\x19FindMissingChunksResponse\x12\x18\n" +
+	"\amissing\x18\x01 \x03(\tR\amissing\"<\n" +
+	"\fContentChunk\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\"G\n" +
+	"\x11PutChunksResponse\x12\x1a\n" +
+	"\breceived\x18\x01 \x01(\x03R\breceived\x12\x16\n" +
+	"\x06stored\x18\x02 \x01(\x03R\x06stored\"7\n" +
+	"\rManifestEntry\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"[\n" +
+	"\x15CommitManifestRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12)\n" +
+	"\x06chunks\x18\x02 \x03(\v2\x11.pb.ManifestEntryR\x06chunks\"\xa5\x02\n" +
+	"\vFileVersion\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"version_id\x18\x03 \x01(\tR\tversionId\x12\x1b\n" +
+	"\tfile_name\x18\x04 \x01(\tR\bfileName\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x16\n" +
+	"\x06sha256\x18\a \x01(\tR\x06sha256\x12\x1f\n" +
+	"\vuploaded_by\x18\b \x01(\tR\n" +
+	"uploadedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\x03R\tcreatedAt\x12#\n" +
+	"\rrestored_from\x18\n" +
+	" \x01(\x03R\frestoredFrom\".\n" +
+	"\x13ListVersionsRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"l\n" +
+	"\x14ListVersionsResponse\x12+\n" +
+	"\bversions\x18\x01 \x03(\v2\x0f.pb.FileVersionR\bversions\x12'\n" +
+	"\x0fcurrent_version\x18\x02 \x01(\x03R\x0ecurrentVersion\"J\n" +
+	"\x15RestoreVersionRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"\xff\x01\n" +
+	"\tTrashItem\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x1d\n" +
+	"\n" +
+	"version_of\x18\x03 \x01(\tR\tversionOf\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"deleted_by\x18\a \x01(\tR\tdeletedBy\x12\x1d\n" +
+	"\n" +
+	"deleted_at\x18\b \x01(\x03R\tdeletedAt\x12\x19\n" +
+	"\bpurge_at\x18\t \x01(\x03R\apurgeAt\"N\n" +
+	"\x10ListTrashRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"`\n" +
+	"\x11ListTrashResponse\x12#\n" +
+	"\x05items\x18\x01 \x03(\v2\r.pb.TrashItemR\x05items\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"-\n" +
+	"\x12RestoreFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"+\n" +
+	"\x10PurgeFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"\xe8\x01\n" +
+	"\x0fRetentionPolicy\x12\x1b\n" +
+	"\tpolicy_id\x18\x01 \x01(\tR\bpolicyId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x122\n" +
+	"\x15min_retention_seconds\x18\x03 \x01(\x03R\x13minRetentionSeconds\x122\n" +
+	"\x15max_retention_seconds\x18\x04 \x01(\x03R\x13maxRetentionSeconds\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\"\x9a\x01\n" +
+	"\x1cCreateRetentionPolicyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x122\n" +
+	"\x15min_retention_seconds\x18\x02 \x01(\x03R\x13minRetentionSeconds\x122\n" +
+	"\x15max_retention_seconds\x18\x03 \x01(\x03R\x13maxRetentionSeconds\"\x1e\n" +
+	"\x1cListRetentionPoliciesRequest\"P\n" +
+	"\x1dListRetentionPoliciesResponse\x12/\n" +
+	"\bpolicies\x18\x01 \x03(\v2\x13.pb.RetentionPolicyR\bpolicies\"{\n" +
+	"\x1cAssignRetentionPolicyRequest\x12\x1b\n" +
+	"\tpolicy_id\x18\x01 \x01(\tR\bpolicyId\x12\x19\n" +
+	"\afile_id\x18\x02 \x01(\tH\x00R\x06fileId\x12\x19\n" +
+	"\auser_id\x18\x03 \x01(\tH\x00R\x06userIdB\b\n" +
+	"\x06target\"<\n" +
+	"\x1dAssignRetentionPolicyResponse\x12\x1b\n" +
+	"\tpolicy_id\x18\x01 \x01(\tR\bpolicyId\"Z\n" +
+	"\x13SetLegalHoldRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04hold\x18\x02 \x01(\bR\x04hold\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"~\n" +
+	"\tLegalHold\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04hold\x18\x02 \x01(\bR\x04hold\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x15\n" +
+	"\x06set_by\x18\x04 \x01(\tR\x05setBy\x12\x15\n" +
+	"\x06set_at\x18\x05 \x01(\x03R\x05setAt\"\x89\x01\n" +
+	"\x06Folder\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\x03R\tcreatedAt\"\xcd\x02\n" +
+	"\tFileEntry\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x1b\n" +
+	"\tfolder_id\x18\x03 \x01(\tR\bfolderId\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\x127\n" +
+	"\bmetadata\x18\b \x03(\v2\x1b.pb.FileEntry.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"F\n" +
+	"\x13CreateFolderRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\"F\n" +
+	"\x13RenameFolderRequest\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"M\n" +
+	"\x11MoveFolderRequest\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\"P\n" +
+	"\x13DeleteFolderRequest\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x1c\n" +
+	"\trecursive\x18\x02 \x01(\bR\trecursive\"\xab\x02\n" +
+	"\x11ListFolderRequest\x12\x1b\n" +
+	"\tfolder_id\x18\x01 \x01(\tR\bfolderId\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12R\n" +
+	"\x0fmetadata_filter\x18\x05 \x03(\v2).pb.ListFolderRequest.MetadataFilterEntryR\x0emetadataFilter\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x1aA\n" +
+	"\x13MetadataFilterEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xab\x01\n" +
+	"\x12ListFolderResponse\x12\"\n" +
+	"\x06folder\x18\x01 \x01(\v2\n" +
+	".pb.FolderR\x06folder\x12$\n" +
+	"\afolders\x18\x02 \x03(\v2\n" +
+	".pb.FolderR\afolders\x12#\n" +
+	"\x05files\x18\x03 \x03(\v2\r.pb.FileEntryR\x05files\x12&\n" +
+	"\x0fnext_page_token\x18\x04 \x01(\tR\rnextPageToken\"'\n" +
+	"\x11LookupPathRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"h\n" +
+	"\x12LookupPathResponse\x12$\n" +
+	"\x06folder\x18\x01 \x01(\v2\n" +
+	".pb.FolderH\x00R\x06folder\x12#\n" +
+	"\x04file\x18\x02 \x01(\v2\r.pb.FileEntryH\x00R\x04fileB\a\n" +
+	"\x05entry\"I\n" +
+	"\x11RenameFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\"h\n" +
+	"\x0fMoveFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x1f\n" +
+	"\vfolder_path\x18\x03 \x01(\tR\n" +
+	"folderPath\"\x85\x01\n" +
+	"\x0fCopyFileRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x1f\n" +
+	"\vfolder_path\x18\x03 \x01(\tR\n" +
+	"folderPath\x12\x1b\n" +
+	"\tfile_name\x18\x04 \x01(\tR\bfileName\"\x97\x02\n" +
+	"\x19UpdateFileMetadataRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12G\n" +
+	"\bmetadata\x18\x02 \x03(\v2+.pb.UpdateFileMetadataRequest.MetadataEntryR\bmetadata\x12\x1f\n" +
+	"\vremove_keys\x18\x03 \x03(\tR\n" +
+	"removeKeys\x12\x19\n" +
+	"\badd_tags\x18\x04 \x03(\tR\aaddTags\x12\x1f\n" +
+	"\vremove_tags\x18\x05 \x03(\tR\n" +
+	"removeTags\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb1\x02\n" +
+	"\x12SearchFilesRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12S\n" +
+	"\x0fmetadata_filter\x18\x04 \x03(\v2*.pb.SearchFilesRequest.MetadataFilterEntryR\x0emetadataFilter\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x1d\n" +
+	"\n" +
+	"owned_only\x18\x06 \x01(\bR\townedOnly\x1aA\n" +
+	"\x13MetadataFilterEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"w\n" +
+	"\fSearchResult\x12!\n" +
+	"\x04file\x18\x01 \x01(\v2\r.pb.FileEntryR\x04file\x12\x12\n" +
+	"\x04rank\x18\x02 \x01(\x02R\x04rank\x12\x18\n" +
+	"\asnippet\x18\x03 \x01(\tR\asnippet\x12\x16\n" +
+	"\x06shared\x18\x04 \x01(\bR\x06shared\"i\n" +
+	"\x13SearchFilesResponse\x12*\n" +
+	"\aresults\x18\x01 \x03(\v2\x10.pb.SearchResultR\aresults\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa6\x01\n" +
+	"\x16DownloadArchiveRequest\x12\x19\n" +
+	"\bfile_ids\x18\x01 \x03(\tR\afileIds\x12\x1b\n" +
+	"\tfolder_id\x18\x02 \x01(\tR\bfolderId\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\x12\x14\n" +
+	"\x05zip64\x18\x05 \x01(\bR\x05zip64\x12\x12\n" +
+	"\x04name\x18\x06 \x01(\tR\x04name\"h\n" +
+	"\fArchiveChunk\x12\x18\n" +
+	"\acontent\x18\x01 \x01(\fR\acontent\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"\x9e\x02\n" +
+	"\x06Tenant\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vquota_bytes\x18\x03 \x01(\x03R\n" +
+	"quotaBytes\x12\"\n" +
+	"\rmax_file_size\x18\x04 \x01(\x03R\vmaxFileSize\x12%\n" +
+	"\x0econtent_policy\x18\x05 \x01(\tR\rcontentPolicy\x12\x1a\n" +
+	"\bdisabled\x18\x06 \x01(\bR\bdisabled\x12\x1d\n" +
+	"\n" +
+	"used_bytes\x18\a \x01(\x03R\tusedBytes\x12\x1d\n" +
+	"\n" +
+	"file_count\x18\b \x01(\x03R\tfileCount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\x03R\tcreatedAt\"\xb2\x01\n" +
+	"\x13CreateTenantRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vquota_bytes\x18\x03 \x01(\x03R\n" +
+	"quotaBytes\x12\"\n" +
+	"\rmax_file_size\x18\x04 \x01(\x03R\vmaxFileSize\x12%\n" +
+	"\x0econtent_policy\x18\x05 \x01(\tR\rcontentPolicy\"\x14\n" +
+	"\x12ListTenantsRequest\";\n" +
+	"\x13ListTenantsResponse\x12$\n" +
+	"\atenants\x18\x01 \x03(\v2\n" +
+	".pb.TenantR\atenants\"/\n" +
+	"\x10GetTenantRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"\xce\x01\n" +
+	"\x13UpdateTenantRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1f\n" +
+	"\vquota_bytes\x18\x03 \x01(\x03R\n" +
+	"quotaBytes\x12\"\n" +
+	"\rmax_file_size\x18\x04 \x01(\x03R\vmaxFileSize\x12%\n" +
+	"\x0econtent_policy\x18\x05 \x01(\tR\rcontentPolicy\x12\x1a\n" +
+	"\bdisabled\x18\x06 \x01(\bR\bdisabled\"\xca\x02\n" +
+	"\x14ImportFromURLRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x1b\n" +
+	"\tfolder_id\x18\x03 \x01(\tR\bfolderId\x12\x1f\n" +
+	"\vfolder_path\x18\x04 \x01(\tR\n" +
+	"folderPath\x12B\n" +
+	"\bmetadata\x18\x05 \x03(\v2&.pb.ImportFromURLRequest.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06sha256\x18\a \x01(\tR\x06sha256\x12\x18\n" +
+	"\aextract\x18\b \x01(\bR\aextract\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"0\n" +
+	"\x15ImportFromURLResponse\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"-\n" +
+	"\x12WatchUploadRequest\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\"\xbe\x02\n" +
+	"\vUploadEvent\x12\x17\n" +
+	"\afile_id\x18\x01 \x01(\tR\x06fileId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1f\n" +
+	"\vchunk_index\x18\x03 \x01(\x03R\n" +
+	"chunkIndex\x12'\n" +
+	"\x0fchunks_received\x18\x04 \x01(\x03R\x0echunksReceived\x12!\n" +
+	"\ftotal_chunks\x18\x05 \x01(\x03R\vtotalChunks\x12%\n" +
+	"\x0ebytes_received\x18\x06 \x01(\x03R\rbytesReceived\x12\x1f\n" +
+	"\vtotal_bytes\x18\a \x01(\x03R\n" +
+	"totalBytes\x12\x16\n" +
+	"\x06status\x18\b \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12\x1f\n" +
+	"\voccurred_at\x18\n" +
+	" \x01(\x03R\n" +
+	"occurredAt\"\xb6\x01\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x03 \x03(\tR\x06events\x12\x1f\n" +
+	"\vtenant_wide\x18\x04 \x01(\bR\n" +
+	"tenantWide\x12\x19\n" +
+	"\bowner_id\x18\x05 \x01(\tR\aownerId\x12\x16\n" +
+	"\x06secret\x18\x06 \x01(\tR\x06secret\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\x03R\tcreatedAt\"a\n" +
+	"\x14CreateWebhookRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06events\x18\x02 \x03(\tR\x06events\x12\x1f\n" +
+	"\vtenant_wide\x18\x03 \x01(\bR\n" +
+	"tenantWide\"\x15\n" +
+	"\x13ListWebhooksRequest\"?\n" +
+	"\x14ListWebhooksResponse\x12'\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\v.pb.WebhookR\bwebhooks\"5\n" +
+	"\x14DeleteWebhookRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\"\x8a\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\tR\twebhookId\x12\x19\n" +
+	"\bevent_id\x18\x03 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tR\teventType\x12\x17\n" +
+	"\afile_id\x18\x05 \x01(\tR\x06fileId\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\a \x01(\x05R\battempts\x12'\n" +
+	"\x0fresponse_status\x18\b \x01(\x05R\x0eresponseStatus\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12\x18\n" +
+	"\apayload\x18\n" +
+	" \x01(\tR\apayload\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\x03R\tcreatedAt\x12&\n" +
+	"\x0fnext_attempt_at\x18\f \x01(\x03R\rnextAttemptAt\x12!\n" +
+	"\fdelivered_at\x18\r \x01(\x03R\vdeliveredAt\"\x91\x01\n" +
+	"\x1cListWebhookDeliveriesRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"|\n" +
+	"\x1dListWebhookDeliveriesResponse\x123\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x13.pb.WebhookDeliveryR\n" +
+	"deliveries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"Y\n" +
+	"\x17RedeliverWebhookRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\tR\twebhookId\x12\x1f\n" +
+	"\vdelivery_id\x18\x02 \x01(\x03R\n" +
+	"deliveryId2\xb5\"\n" +
 	"\x11FileUploadService\x12/\n" +
 	"\n" +
 	"InitUpload\x12\x0f.pb.InitRequest\x1a\x10.pb.InitResponse\x12/\n" +
@@ -725,7 +6430,60 @@ const file_fileupload_proto_rawDesc = "" +
 	"\fDownloadFile\x12\x13.pb.DownloadRequest\x1a\x14.pb.DownloadResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/v1/files/{file_id}\x12g\n" +
 	"\x11GetUploadMetadata\x12\x16.pb.GetMetadataRequest\x1a\x12.pb.UploadMetadata\"&\x82\xd3\xe4\x93\x02 \x12\x1e/v1/uploads/{file_id}/metadata\x12P\n" +
 	"\n" +
-	"DeleteFile\x12\x11.pb.DeleteRequest\x1a\x12.pb.DeleteResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/v1/files/{file_id}B8Z6github.com/siddheshRajendraNimbalkar/upload-backend/pbb\x06proto3"
+	"DeleteFile\x12\x11.pb.DeleteRequest\x1a\x12.pb.DeleteResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/v1/files/{file_id}\x12v\n" +
+	"\x0fCreateSignedURL\x12\x1a.pb.CreateSignedURLRequest\x1a\x1b.pb.CreateSignedURLResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/files/{file_id}/signed-urls\x12A\n" +
+	"\x0eDownloadSigned\x12\x19.pb.SignedDownloadRequest\x1a\x14.pb.DownloadResponse\x12X\n" +
+	"\tShareFile\x12\x14.pb.ShareFileRequest\x1a\x0e.pb.ShareGrant\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/files/{file_id}/grants\x12m\n" +
+	"\vUnshareFile\x12\x16.pb.UnshareFileRequest\x1a\x17.pb.UnshareFileResponse\"-\x82\xd3\xe4\x93\x02'*%/v1/files/{file_id}/grants/{grant_id}\x12_\n" +
+	"\n" +
+	"ListGrants\x12\x15.pb.ListGrantsRequest\x1a\x16.pb.ListGrantsResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/files/{file_id}/grants\x12j\n" +
+	"\x0fListAuditEvents\x12\x1a.pb.ListAuditEventsRequest\x1a\x1b.pb.ListAuditEventsResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/v1/admin/audit-events\x12o\n" +
+	"\x11FindMissingChunks\x12\x1c.pb.FindMissingChunksRequest\x1a\x1d.pb.FindMissingChunksResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/chunks/missing\x126\n" +
+	"\tPutChunks\x12\x10.pb.ContentChunk\x1a\x15.pb.PutChunksResponse(\x01\x12g\n" +
+	"\fListVersions\x12\x17.pb.ListVersionsRequest\x1a\x18.pb.ListVersionsResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/files/{file_id}/versions\x12w\n" +
+	"\x0eRestoreVersion\x12\x19.pb.RestoreVersionRequest\x1a\x0f.pb.FileVersion\"9\x82\xd3\xe4\x93\x023:\x01*\"./v1/files/{file_id}/versions/{version}/restore\x12h\n" +
+	"\x0eCommitManifest\x12\x19.pb.CommitManifestRequest\x1a\x10.pb.UploadStatus\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/uploads/{file_id}/manifest\x12K\n" +
+	"\tListTrash\x12\x14.pb.ListTrashRequest\x1a\x15.pb.ListTrashResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/trash\x12\\\n" +
+	"\vRestoreFile\x12\x16.pb.RestoreFileRequest\x1a\r.pb.TrashItem\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/trash/{file_id}/restore\x12R\n" +
+	"\tPurgeFile\x12\x14.pb.PurgeFileRequest\x1a\x12.pb.DeleteResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/v1/trash/{file_id}\x12w\n" +
+	"\x15CreateRetentionPolicy\x12 .pb.CreateRetentionPolicyRequest\x1a\x13.pb.RetentionPolicy\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/admin/retention-policies\x12\x82\x01\n" +
+	"\x15ListRetentionPolicies\x12 .pb.ListRetentionPoliciesRequest\x1a!.pb.ListRetentionPoliciesResponse\"$\x82\xd3\xe4\x93\x02\x1e\x12\x1c/v1/admin/retention-policies\x12\x88\x01\n" +
+	"\x15AssignRetentionPolicy\x12 .pb.AssignRetentionPolicyRequest\x1a!.pb.AssignRetentionPolicyResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\x1a\x1f/v1/admin/retention-assignments\x12g\n" +
+	"\fSetLegalHold\x12\x17.pb.SetLegalHoldRequest\x1a\r.pb.LegalHold\"/\x82\xd3\xe4\x93\x02):\x01*\x1a$/v1/admin/files/{file_id}/legal-hold\x12K\n" +
+	"\fCreateFolder\x12\x17.pb.CreateFolderRequest\x1a\n" +
+	".pb.Folder\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/folders\x12^\n" +
+	"\fRenameFolder\x12\x17.pb.RenameFolderRequest\x1a\n" +
+	".pb.Folder\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/folders/{folder_id}/rename\x12X\n" +
+	"\n" +
+	"MoveFolder\x12\x15.pb.MoveFolderRequest\x1a\n" +
+	".pb.Folder\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/folders/{folder_id}/move\x12\\\n" +
+	"\fDeleteFolder\x12\x17.pb.DeleteFolderRequest\x1a\x12.pb.DeleteResponse\"\x1f\x82\xd3\xe4\x93\x02\x19*\x17/v1/folders/{folder_id}\x12P\n" +
+	"\n" +
+	"ListFolder\x12\x15.pb.ListFolderRequest\x1a\x16.pb.ListFolderResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/v1/folders\x12N\n" +
+	"\n" +
+	"LookupPath\x12\x15.pb.LookupPathRequest\x1a\x16.pb.LookupPathResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/paths\x12Y\n" +
+	"\n" +
+	"RenameFile\x12\x15.pb.RenameFileRequest\x1a\r.pb.FileEntry\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/files/{file_id}/rename\x12S\n" +
+	"\bMoveFile\x12\x13.pb.MoveFileRequest\x1a\r.pb.FileEntry\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/files/{file_id}/move\x12S\n" +
+	"\bCopyFile\x12\x13.pb.CopyFileRequest\x1a\r.pb.FileEntry\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/files/{file_id}/copy\x12k\n" +
+	"\x12UpdateFileMetadata\x12\x1d.pb.UpdateFileMetadataRequest\x1a\r.pb.FileEntry\"'\x82\xd3\xe4\x93\x02!:\x01*2\x1c/v1/files/{file_id}/metadata\x12Q\n" +
+	"\fCreateTenant\x12\x17.pb.CreateTenantRequest\x1a\n" +
+	".pb.Tenant\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/admin/tenants\x12Y\n" +
+	"\vListTenants\x12\x16.pb.ListTenantsRequest\x1a\x17.pb.ListTenantsResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/admin/tenants\x12T\n" +
+	"\tGetTenant\x12\x14.pb.GetTenantRequest\x1a\n" +
+	".pb.Tenant\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/admin/tenants/{tenant_id}\x12]\n" +
+	"\fUpdateTenant\x12\x17.pb.UpdateTenantRequest\x1a\n" +
+	".pb.Tenant\"(\x82\xd3\xe4\x93\x02\":\x01*\x1a\x1d/v1/admin/tenants/{tenant_id}\x12R\n" +
+	"\vSearchFiles\x12\x16.pb.SearchFilesRequest\x1a\x17.pb.SearchFilesResponse\"\x12\x82\xd3\xe4\x93\x02\f\x12\n" +
+	"/v1/search\x12A\n" +
+	"\x0fDownloadArchive\x12\x1a.pb.DownloadArchiveRequest\x1a\x10.pb.ArchiveChunk0\x01\x12\\\n" +
+	"\rImportFromURL\x12\x18.pb.ImportFromURLRequest\x1a\x19.pb.ImportFromURLResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/v1/imports\x128\n" +
+	"\vWatchUpload\x12\x16.pb.WatchUploadRequest\x1a\x0f.pb.UploadEvent0\x01\x12O\n" +
+	"\rCreateWebhook\x12\x18.pb.CreateWebhookRequest\x1a\v.pb.Webhook\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/webhooks\x12W\n" +
+	"\fListWebhooks\x12\x17.pb.ListWebhooksRequest\x1a\x18.pb.ListWebhooksResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/v1/webhooks\x12`\n" +
+	"\rDeleteWebhook\x12\x18.pb.DeleteWebhookRequest\x1a\x12.pb.DeleteResponse\"!\x82\xd3\xe4\x93\x02\x1b*\x19/v1/webhooks/{webhook_id}\x12\x8a\x01\n" +
+	"\x15ListWebhookDeliveries\x12 .pb.ListWebhookDeliveriesRequest\x1a!.pb.ListWebhookDeliveriesResponse\",\x82\xd3\xe4\x93\x02&\x12$/v1/webhooks/{webhook_id}/deliveries\x12\x8d\x01\n" +
+	"\x10RedeliverWebhook\x12\x1b.pb.RedeliverWebhookRequest\x1a\x13.pb.WebhookDelivery\"G\x82\xd3\xe4\x93\x02A:\x01*\"</v1/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliverB8Z6github.com/siddheshRajendraNimbalkar/upload-backend/pbb\x06proto3"
 
 var (
 	file_fileupload_proto_rawDescOnce sync.Once
@@ -739,39 +6497,228 @@ func file_fileupload_proto_rawDescGZIP() []byte {
 	return file_fileupload_proto_rawDescData
 }
 
-var file_fileupload_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_fileupload_proto_msgTypes = make([]protoimpl.MessageInfo, 94)
 var file_fileupload_proto_goTypes = []any{
-	(*FileChunk)(nil),          // 0: pb.FileChunk
-	(*DownloadRequest)(nil),    // 1: pb.DownloadRequest
-	(*DownloadResponse)(nil),   // 2: pb.DownloadResponse
-	(*UploadStatus)(nil),       // 3: pb.UploadStatus
-	(*GetChunksRequest)(nil),   // 4: pb.GetChunksRequest
-	(*GetChunksResponse)(nil),  // 5: pb.GetChunksResponse
-	(*GetMetadataRequest)(nil), // 6: pb.GetMetadataRequest
-	(*UploadMetadata)(nil),     // 7: pb.UploadMetadata
-	(*InitRequest)(nil),        // 8: pb.InitRequest
-	(*InitResponse)(nil),       // 9: pb.InitResponse
-	(*DeleteRequest)(nil),      // 10: pb.DeleteRequest
-	(*DeleteResponse)(nil),     // 11: pb.DeleteResponse
+	(*FileChunk)(nil),                     // 0: pb.FileChunk
+	(*DownloadRequest)(nil),               // 1: pb.DownloadRequest
+	(*DownloadResponse)(nil),              // 2: pb.DownloadResponse
+	(*UploadStatus)(nil),                  // 3: pb.UploadStatus
+	(*GetChunksRequest)(nil),              // 4: pb.GetChunksRequest
+	(*GetChunksResponse)(nil),             // 5: pb.GetChunksResponse
+	(*GetMetadataRequest)(nil),            // 6: pb.GetMetadataRequest
+	(*UploadMetadata)(nil),                // 7: pb.UploadMetadata
+	(*URLImport)(nil),                     // 8: pb.URLImport
+	(*ArchiveExtraction)(nil),             // 9: pb.ArchiveExtraction
+	(*InitRequest)(nil),                   // 10: pb.InitRequest
+	(*InitResponse)(nil),                  // 11: pb.InitResponse
+	(*DeleteRequest)(nil),                 // 12: pb.DeleteRequest
+	(*DeleteResponse)(nil),                // 13: pb.DeleteResponse
+	(*CreateSignedURLRequest)(nil),        // 14: pb.CreateSignedURLRequest
+	(*CreateSignedURLResponse)(nil),       // 15: pb.CreateSignedURLResponse
+	(*SignedDownloadRequest)(nil),         // 16: pb.SignedDownloadRequest
+	(*ShareFileRequest)(nil),              // 17: pb.ShareFileRequest
+	(*ShareGrant)(nil),                    // 18: pb.ShareGrant
+	(*UnshareFileRequest)(nil),            // 19: pb.UnshareFileRequest
+	(*UnshareFileResponse)(nil),           // 20: pb.UnshareFileResponse
+	(*ListGrantsRequest)(nil),             // 21: pb.ListGrantsRequest
+	(*ListGrantsResponse)(nil),            // 22: pb.ListGrantsResponse
+	(*ListAuditEventsRequest)(nil),        // 23: pb.ListAuditEventsRequest
+	(*AuditEvent)(nil),                    // 24: pb.AuditEvent
+	(*ListAuditEventsResponse)(nil),       // 25: pb.ListAuditEventsResponse
+	(*FindMissingChunksRequest)(nil),      // 26: pb.FindMissingChunksRequest
+	(*FindMissingChunksResponse)(nil),     // 27: pb.FindMissingChunksResponse
+	(*ContentChunk)(nil),                  // 28: pb.ContentChunk
+	(*PutChunksResponse)(nil),             // 29: pb.PutChunksResponse
+	(*ManifestEntry)(nil),                 // 30: pb.ManifestEntry
+	(*CommitManifestRequest)(nil),         // 31: pb.CommitManifestRequest
+	(*FileVersion)(nil),                   // 32: pb.FileVersion
+	(*ListVersionsRequest)(nil),           // 33: pb.ListVersionsRequest
+	(*ListVersionsResponse)(nil),          // 34: pb.ListVersionsResponse
+	(*RestoreVersionRequest)(nil),         // 35: pb.RestoreVersionRequest
+	(*TrashItem)(nil),                     // 36: pb.TrashItem
+	(*ListTrashRequest)(nil),              // 37: pb.ListTrashRequest
+	(*ListTrashResponse)(nil),             // 38: pb.ListTrashResponse
+	(*RestoreFileRequest)(nil),            // 39: pb.RestoreFileRequest
+	(*PurgeFileRequest)(nil),              // 40: pb.PurgeFileRequest
+	(*RetentionPolicy)(nil),               // 41: pb.RetentionPolicy
+	(*CreateRetentionPolicyRequest)(nil),  // 42: pb.CreateRetentionPolicyRequest
+	(*ListRetentionPoliciesRequest)(nil),  // 43: pb.ListRetentionPoliciesRequest
+	(*ListRetentionPoliciesResponse)(nil), // 44: pb.ListRetentionPoliciesResponse
+	(*AssignRetentionPolicyRequest)(nil),  // 45: pb.AssignRetentionPolicyRequest
+	(*AssignRetentionPolicyResponse)(nil), // 46: pb.AssignRetentionPolicyResponse
+	(*SetLegalHoldRequest)(nil),           // 47: pb.SetLegalHoldRequest
+	(*LegalHold)(nil),                     // 48: pb.LegalHold
+	(*Folder)(nil),                        // 49: pb.Folder
+	(*FileEntry)(nil),                     // 50: pb.FileEntry
+	(*CreateFolderRequest)(nil),           // 51: pb.CreateFolderRequest
+	(*RenameFolderRequest)(nil),           // 52: pb.RenameFolderRequest
+	(*MoveFolderRequest)(nil),             // 53: pb.MoveFolderRequest
+	(*DeleteFolderRequest)(nil),           // 54: pb.DeleteFolderRequest
+	(*ListFolderRequest)(nil),             // 55: pb.ListFolderRequest
+	(*ListFolderResponse)(nil),            // 56: pb.ListFolderResponse
+	(*LookupPathRequest)(nil),             // 57: pb.LookupPathRequest
+	(*LookupPathResponse)(nil),            // 58: pb.LookupPathResponse
+	(*RenameFileRequest)(nil),             // 59: pb.RenameFileRequest
+	(*MoveFileRequest)(nil),               // 60: pb.MoveFileRequest
+	(*CopyFileRequest)(nil),               // 61: pb.CopyFileRequest
+	(*UpdateFileMetadataRequest)(nil),     // 62: pb.UpdateFileMetadataRequest
+	(*SearchFilesRequest)(nil),            // 63: pb.SearchFilesRequest
+	(*SearchResult)(nil),                  // 64: pb.SearchResult
+	(*SearchFilesResponse)(nil),           // 65: pb.SearchFilesResponse
+	(*DownloadArchiveRequest)(nil),        // 66: pb.DownloadArchiveRequest
+	(*ArchiveChunk)(nil),                  // 67: pb.ArchiveChunk
+	(*Tenant)(nil),                        // 68: pb.Tenant
+	(*CreateTenantRequest)(nil),           // 69: pb.CreateTenantRequest
+	(*ListTenantsRequest)(nil),            // 70: pb.ListTenantsRequest
+	(*ListTenantsResponse)(nil),           // 71: pb.ListTenantsResponse
+	(*GetTenantRequest)(nil),              // 72: pb.GetTenantRequest
+	(*UpdateTenantRequest)(nil),           // 73: pb.UpdateTenantRequest
+	(*ImportFromURLRequest)(nil),          // 74: pb.ImportFromURLRequest
+	(*ImportFromURLResponse)(nil),         // 75: pb.ImportFromURLResponse
+	(*WatchUploadRequest)(nil),            // 76: pb.WatchUploadRequest
+	(*UploadEvent)(nil),                   // 77: pb.UploadEvent
+	(*Webhook)(nil),                       // 78: pb.Webhook
+	(*CreateWebhookRequest)(nil),          // 79: pb.CreateWebhookRequest
+	(*ListWebhooksRequest)(nil),           // 80: pb.ListWebhooksRequest
+	(*ListWebhooksResponse)(nil),          // 81: pb.ListWebhooksResponse
+	(*DeleteWebhookRequest)(nil),          // 82: pb.DeleteWebhookRequest
+	(*WebhookDelivery)(nil),               // 83: pb.WebhookDelivery
+	(*ListWebhookDeliveriesRequest)(nil),  // 84: pb.ListWebhookDeliveriesRequest
+	(*ListWebhookDeliveriesResponse)(nil), // 85: pb.ListWebhookDeliveriesResponse
+	(*RedeliverWebhookRequest)(nil),       // 86: pb.RedeliverWebhookRequest
+	nil,                                   // 87: pb.UploadMetadata.MetadataEntry
+	nil,                                   // 88: pb.InitRequest.MetadataEntry
+	nil,                                   // 89: pb.FileEntry.MetadataEntry
+	nil,                                   // 90: pb.ListFolderRequest.MetadataFilterEntry
+	nil,                                   // 91: pb.UpdateFileMetadataRequest.MetadataEntry
+	nil,                                   // 92: pb.SearchFilesRequest.MetadataFilterEntry
+	nil,                                   // 93: pb.ImportFromURLRequest.MetadataEntry
 }
 var file_fileupload_proto_depIdxs = []int32{
-	8,  // 0: pb.FileUploadService.InitUpload:input_type -> pb.InitRequest
-	0,  // 1: pb.FileUploadService.UploadFile:input_type -> pb.FileChunk
-	4,  // 2: pb.FileUploadService.GetUploadedChunks:input_type -> pb.GetChunksRequest
-	1,  // 3: pb.FileUploadService.DownloadFile:input_type -> pb.DownloadRequest
-	6,  // 4: pb.FileUploadService.GetUploadMetadata:input_type -> pb.GetMetadataRequest
-	10, // 5: pb.FileUploadService.DeleteFile:input_type -> pb.DeleteRequest
-	9,  // 6: pb.FileUploadService.InitUpload:output_type -> pb.InitResponse
-	3,  // 7: pb.FileUploadService.UploadFile:output_type -> pb.UploadStatus
-	5,  // 8: pb.FileUploadService.GetUploadedChunks:output_type -> pb.GetChunksResponse
-	2,  // 9: pb.FileUploadService.DownloadFile:output_type -> pb.DownloadResponse
-	7,  // 10: pb.FileUploadService.GetUploadMetadata:output_type -> pb.UploadMetadata
-	11, // 11: pb.FileUploadService.DeleteFile:output_type -> pb.DeleteResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
-	0,  // [0:0] is the sub-list for extension type_name
-	0,  // [0:0] is the sub-list for extension extendee
-	0,  // [0:0] is the sub-list for field type_name
+	87, // 0: pb.UploadMetadata.metadata:type_name -> pb.UploadMetadata.MetadataEntry
+	9,  // 1: pb.UploadMetadata.extraction:type_name -> pb.ArchiveExtraction
+	8,  // 2: pb.UploadMetadata.url_import:type_name -> pb.URLImport
+	88, // 3: pb.InitRequest.metadata:type_name -> pb.InitRequest.MetadataEntry
+	18, // 4: pb.ListGrantsResponse.grants:type_name -> pb.ShareGrant
+	24, // 5: pb.ListAuditEventsResponse.events:type_name -> pb.AuditEvent
+	30, // 6: pb.CommitManifestRequest.chunks:type_name -> pb.ManifestEntry
+	32, // 7: pb.ListVersionsResponse.versions:type_name -> pb.FileVersion
+	36, // 8: pb.ListTrashResponse.items:type_name -> pb.TrashItem
+	41, // 9: pb.ListRetentionPoliciesResponse.policies:type_name -> pb.RetentionPolicy
+	89, // 10: pb.FileEntry.metadata:type_name -> pb.FileEntry.MetadataEntry
+	90, // 11: pb.ListFolderRequest.metadata_filter:type_name -> pb.ListFolderRequest.MetadataFilterEntry
+	49, // 12: pb.ListFolderResponse.folder:type_name -> pb.Folder
+	49, // 13: pb.ListFolderResponse.folders:type_name -> pb.Folder
+	50, // 14: pb.ListFolderResponse.files:type_name -> pb.FileEntry
+	49, // 15: pb.LookupPathResponse.folder:type_name -> pb.Folder
+	50, // 16: pb.LookupPathResponse.file:type_name -> pb.FileEntry
+	91, // 17: pb.UpdateFileMetadataRequest.metadata:type_name -> pb.UpdateFileMetadataRequest.MetadataEntry
+	92, // 18: pb.SearchFilesRequest.metadata_filter:type_name -> pb.SearchFilesRequest.MetadataFilterEntry
+	50, // 19: pb.SearchResult.file:type_name -> pb.FileEntry
+	64, // 20: pb.SearchFilesResponse.results:type_name -> pb.SearchResult
+	68, // 21: pb.ListTenantsResponse.tenants:type_name -> pb.Tenant
+	93, // 22: pb.ImportFromURLRequest.metadata:type_name -> pb.ImportFromURLRequest.MetadataEntry
+	78, // 23: pb.ListWebhooksResponse.webhooks:type_name -> pb.Webhook
+	83, // 24: pb.ListWebhookDeliveriesResponse.deliveries:type_name -> pb.WebhookDelivery
+	10, // 25: pb.FileUploadService.InitUpload:input_type -> pb.InitRequest
+	0,  // 26: pb.FileUploadService.UploadFile:input_type -> pb.FileChunk
+	4,  // 27: pb.FileUploadService.GetUploadedChunks:input_type -> pb.GetChunksRequest
+	1,  // 28: pb.FileUploadService.DownloadFile:input_type -> pb.DownloadRequest
+	6,  // 29: pb.FileUploadService.GetUploadMetadata:input_type -> pb.GetMetadataRequest
+	12, // 30: pb.FileUploadService.DeleteFile:input_type -> pb.DeleteRequest
+	14, // 31: pb.FileUploadService.CreateSignedURL:input_type -> pb.CreateSignedURLRequest
+	16, // 32: pb.FileUploadService.DownloadSigned:input_type -> pb.SignedDownloadRequest
+	17, // 33: pb.FileUploadService.ShareFile:input_type -> pb.ShareFileRequest
+	19, // 34: pb.FileUploadService.UnshareFile:input_type -> pb.UnshareFileRequest
+	21, // 35: pb.FileUploadService.ListGrants:input_type -> pb.ListGrantsRequest
+	23, // 36: pb.FileUploadService.ListAuditEvents:input_type -> pb.ListAuditEventsRequest
+	26, // 37: pb.FileUploadService.FindMissingChunks:input_type -> pb.FindMissingChunksRequest
+	28, // 38: pb.FileUploadService.PutChunks:input_type -> pb.ContentChunk
+	33, // 39: pb.FileUploadService.ListVersions:input_type -> pb.ListVersionsRequest
+	35, // 40: pb.FileUploadService.RestoreVersion:input_type -> pb.RestoreVersionRequest
+	31, // 41: pb.FileUploadService.CommitManifest:input_type -> pb.CommitManifestRequest
+	37, // 42: pb.FileUploadService.ListTrash:input_type -> pb.ListTrashRequest
+	39, // 43: pb.FileUploadService.RestoreFile:input_type -> pb.RestoreFileRequest
+	40, // 44: pb.FileUploadService.PurgeFile:input_type -> pb.PurgeFileRequest
+	42, // 45: pb.FileUploadService.CreateRetentionPolicy:input_type -> pb.CreateRetentionPolicyRequest
+	43, // 46: pb.FileUploadService.ListRetentionPolicies:input_type -> pb.ListRetentionPoliciesRequest
+	45, // 47: pb.FileUploadService.AssignRetentionPolicy:input_type -> pb.AssignRetentionPolicyRequest
+	47, // 48: pb.FileUploadService.SetLegalHold:input_type -> pb.SetLegalHoldRequest
+	51, // 49: pb.FileUploadService.CreateFolder:input_type -> pb.CreateFolderRequest
+	52, // 50: pb.FileUploadService.RenameFolder:input_type -> pb.RenameFolderRequest
+	53, // 51: pb.FileUploadService.MoveFolder:input_type -> pb.MoveFolderRequest
+	54, // 52: pb.FileUploadService.DeleteFolder:input_type -> pb.DeleteFolderRequest
+	55, // 53: pb.FileUploadService.ListFolder:input_type -> pb.ListFolderRequest
+	57, // 54: pb.FileUploadService.LookupPath:input_type -> pb.LookupPathRequest
+	59, // 55: pb.FileUploadService.RenameFile:input_type -> pb.RenameFileRequest
+	60, // 56: pb.FileUploadService.MoveFile:input_type -> pb.MoveFileRequest
+	61, // 57: pb.FileUploadService.CopyFile:input_type -> pb.CopyFileRequest
+	62, // 58: pb.FileUploadService.UpdateFileMetadata:input_type -> pb.UpdateFileMetadataRequest
+	69, // 59: pb.FileUploadService.CreateTenant:input_type -> pb.CreateTenantRequest
+	70, // 60: pb.FileUploadService.ListTenants:input_type -> pb.ListTenantsRequest
+	72, // 61: pb.FileUploadService.GetTenant:input_type -> pb.GetTenantRequest
+	73, // 62: pb.FileUploadService.UpdateTenant:input_type -> pb.UpdateTenantRequest
+	63, // 63: pb.FileUploadService.SearchFiles:input_type -> pb.SearchFilesRequest
+	66, // 64: pb.FileUploadService.DownloadArchive:input_type -> pb.DownloadArchiveRequest
+	74, // 65: pb.FileUploadService.ImportFromURL:input_type -> pb.ImportFromURLRequest
+	76, // 66: pb.FileUploadService.WatchUpload:input_type -> pb.WatchUploadRequest
+	79, // 67: pb.FileUploadService.CreateWebhook:input_type -> pb.CreateWebhookRequest
+	80, // 68: pb.FileUploadService.ListWebhooks:input_type -> pb.ListWebhooksRequest
+	82, // 69: pb.FileUploadService.DeleteWebhook:input_type -> pb.DeleteWebhookRequest
+	84, // 70: pb.FileUploadService.ListWebhookDeliveries:input_type -> pb.ListWebhookDeliveriesRequest
+	86, // 71: pb.FileUploadService.RedeliverWebhook:input_type -> pb.RedeliverWebhookRequest
+	11, // 72: pb.FileUploadService.InitUpload:output_type -> pb.InitResponse
+	3,  // 73: pb.FileUploadService.UploadFile:output_type -> pb.UploadStatus
+	5,  // 74: pb.FileUploadService.GetUploadedChunks:output_type -> pb.GetChunksResponse
+	2,  // 75: pb.FileUploadService.DownloadFile:output_type -> pb.DownloadResponse
+	7,  // 76: pb.FileUploadService.GetUploadMetadata:output_type -> pb.UploadMetadata
+	13, // 77: pb.FileUploadService.DeleteFile:output_type -> pb.DeleteResponse
+	15, // 78: pb.FileUploadService.CreateSignedURL:output_type -> pb.CreateSignedURLResponse
+	2,  // 79: pb.FileUploadService.DownloadSigned:output_type -> pb.DownloadResponse
+	18, // 80: pb.FileUploadService.ShareFile:output_type -> pb.ShareGrant
+	20, // 81: pb.FileUploadService.UnshareFile:output_type -> pb.UnshareFileResponse
+	22, // 82: pb.FileUploadService.ListGrants:output_type -> pb.ListGrantsResponse
+	25, // 83: pb.FileUploadService.ListAuditEvents:output_type -> pb.ListAuditEventsResponse
+	27, // 84: pb.FileUploadService.FindMissingChunks:output_type -> pb.FindMissingChunksResponse
+	29, // 85: pb.FileUploadService.PutChunks:output_type -> pb.PutChunksResponse
+	34, // 86: pb.FileUploadService.ListVersions:output_type -> pb.ListVersionsResponse
+	32, // 87: pb.FileUploadService.RestoreVersion:output_type -> pb.FileVersion
+	3,  // 88: pb.FileUploadService.CommitManifest:output_type -> pb.UploadStatus
+	38, // 89: pb.FileUploadService.ListTrash:output_type -> pb.ListTrashResponse
+	36, // 90: pb.FileUploadService.RestoreFile:output_type -> pb.TrashItem
+	13, // 91: pb.FileUploadService.PurgeFile:output_type -> pb.DeleteResponse
+	41, // 92: pb.FileUploadService.CreateRetentionPolicy:output_type -> pb.RetentionPolicy
+	44, // 93: pb.FileUploadService.ListRetentionPolicies:output_type -> pb.ListRetentionPoliciesResponse
+	46, // 94: pb.FileUploadService.AssignRetentionPolicy:output_type -> pb.AssignRetentionPolicyResponse
+	48, // 95: pb.FileUploadService.SetLegalHold:output_type -> pb.LegalHold
+	49, // 96: pb.FileUploadService.CreateFolder:output_type -> pb.Folder
+	49, // 97: pb.FileUploadService.RenameFolder:output_type -> pb.Folder
+	49, // 98: pb.FileUploadService.MoveFolder:output_type -> pb.Folder
+	13, // 99: pb.FileUploadService.DeleteFolder:output_type -> pb.DeleteResponse
+	56, // 100: pb.FileUploadService.ListFolder:output_type -> pb.ListFolderResponse
+	58, // 101: pb.FileUploadService.LookupPath:output_type -> pb.LookupPathResponse
+	50, // 102: pb.FileUploadService.RenameFile:output_type -> pb.FileEntry
+	50, // 103: pb.FileUploadService.MoveFile:output_type -> pb.FileEntry
+	50, // 104: pb.FileUploadService.CopyFile:output_type -> pb.FileEntry
+	50, // 105: pb.FileUploadService.UpdateFileMetadata:output_type -> pb.FileEntry
+	68, // 106: pb.FileUploadService.CreateTenant:output_type -> pb.Tenant
+	71, // 107: pb.FileUploadService.ListTenants:output_type -> pb.ListTenantsResponse
+	68, // 108: pb.FileUploadService.GetTenant:output_type -> pb.Tenant
+	68, // 109: pb.FileUploadService.UpdateTenant:output_type -> pb.Tenant
+	65, // 110: pb.FileUploadService.SearchFiles:output_type -> pb.SearchFilesResponse
+	67, // 111: pb.FileUploadService.DownloadArchive:output_type -> pb.ArchiveChunk
+	75, // 112: pb.FileUploadService.ImportFromURL:output_type -> pb.ImportFromURLResponse
+	77, // 113: pb.FileUploadService.WatchUpload:output_type -> pb.UploadEvent
+	78, // 114: pb.FileUploadService.CreateWebhook:output_type -> pb.Webhook
+	81, // 115: pb.FileUploadService.ListWebhooks:output_type -> pb.ListWebhooksResponse
+	13, // 116: pb.FileUploadService.DeleteWebhook:output_type -> pb.DeleteResponse
+	85, // 117: pb.FileUploadService.ListWebhookDeliveries:output_type -> pb.ListWebhookDeliveriesResponse
+	83, // 118: pb.FileUploadService.RedeliverWebhook:output_type -> pb.WebhookDelivery
+	72, // [72:119] is the sub-list for method output_type
+	25, // [25:72] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_fileupload_proto_init() }
@@ -779,13 +6726,21 @@ func file_fileupload_proto_init() {
 	if File_fileupload_proto != nil {
 		return
 	}
+	file_fileupload_proto_msgTypes[45].OneofWrappers = []any{
+		(*AssignRetentionPolicyRequest_FileId)(nil),
+		(*AssignRetentionPolicyRequest_UserId)(nil),
+	}
+	file_fileupload_proto_msgTypes[58].OneofWrappers = []any{
+		(*LookupPathResponse_Folder)(nil),
+		(*LookupPathResponse_File)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_fileupload_proto_rawDesc), len(file_fileupload_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   94,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,12 +19,53 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	FileUploadService_InitUpload_FullMethodName        = "/pb.FileUploadService/InitUpload"
-	FileUploadService_UploadFile_FullMethodName        = "/pb.FileUploadService/UploadFile"
-	FileUploadService_GetUploadedChunks_FullMethodName = "/pb.FileUploadService/GetUploadedChunks"
-	FileUploadService_DownloadFile_FullMethodName      = "/pb.FileUploadService/DownloadFile"
-	FileUploadService_GetUploadMetadata_FullMethodName = "/pb.FileUploadService/GetUploadMetadata"
-	FileUploadService_DeleteFile_FullMethodName        = "/pb.FileUploadService/DeleteFile"
+	FileUploadService_InitUpload_FullMethodName            = "/pb.FileUploadService/InitUpload"
+	FileUploadService_UploadFile_FullMethodName            = "/pb.FileUploadService/UploadFile"
+	FileUploadService_GetUploadedChunks_FullMethodName     = "/pb.FileUploadService/GetUploadedChunks"
+	FileUploadService_DownloadFile_FullMethodName          = "/pb.FileUploadService/DownloadFile"
+	FileUploadService_GetUploadMetadata_FullMethodName     = "/pb.FileUploadService/GetUploadMetadata"
+	FileUploadService_DeleteFile_FullMethodName            = "/pb.FileUploadService/DeleteFile"
+	FileUploadService_CreateSignedURL_FullMethodName       = "/pb.FileUploadService/CreateSignedURL"
+	FileUploadService_DownloadSigned_FullMethodName        = "/pb.FileUploadService/DownloadSigned"
+	FileUploadService_ShareFile_FullMethodName             = "/pb.FileUploadService/ShareFile"
+	FileUploadService_UnshareFile_FullMethodName           = "/pb.FileUploadService/UnshareFile"
+	FileUploadService_ListGrants_FullMethodName            = "/pb.FileUploadService/ListGrants"
+	FileUploadService_ListAuditEvents_FullMethodName       = "/pb.FileUploadService/ListAuditEvents"
+	FileUploadService_FindMissingChunks_FullMethodName     = "/pb.FileUploadService/FindMissingChunks"
+	FileUploadService_PutChunks_FullMethodName             = "/pb.FileUploadService/PutChunks"
+	FileUploadService_ListVersions_FullMethodName          = "/pb.FileUploadService/ListVersions"
+	FileUploadService_RestoreVersion_FullMethodName        = "/pb.FileUploadService/RestoreVersion"
+	FileUploadService_CommitManifest_FullMethodName        = "/pb.FileUploadService/CommitManifest"
+	FileUploadService_ListTrash_FullMethodName             = "/pb.FileUploadService/ListTrash"
+	FileUploadService_RestoreFile_FullMethodName           = "/pb.FileUploadService/RestoreFile"
+	FileUploadService_PurgeFile_FullMethodName             = "/pb.FileUploadService/PurgeFile"
+	FileUploadService_CreateRetentionPolicy_FullMethodName = "/pb.FileUploadService/CreateRetentionPolicy"
+	FileUploadService_ListRetentionPolicies_FullMethodName = "/pb.FileUploadService/ListRetentionPolicies"
+	FileUploadService_AssignRetentionPolicy_FullMethodName = "/pb.FileUploadService/AssignRetentionPolicy"
+	FileUploadService_SetLegalHold_FullMethodName          = "/pb.FileUploadService/SetLegalHold"
+	FileUploadService_CreateFolder_FullMethodName          = "/pb.FileUploadService/CreateFolder"
+	FileUploadService_RenameFolder_FullMethodName          = "/pb.FileUploadService/RenameFolder"
+	FileUploadService_MoveFolder_FullMethodName            = "/pb.FileUploadService/MoveFolder"
+	FileUploadService_DeleteFolder_FullMethodName          = "/pb.FileUploadService/DeleteFolder"
+	FileUploadService_ListFolder_FullMethodName            = "/pb.FileUploadService/ListFolder"
+	FileUploadService_LookupPath_FullMethodName            = "/pb.FileUploadService/LookupPath"
+	FileUploadService_RenameFile_FullMethodName            = "/pb.FileUploadService/RenameFile"
+	FileUploadService_MoveFile_FullMethodName              = "/pb.FileUploadService/MoveFile"
+	FileUploadService_CopyFile_FullMethodName              = "/pb.FileUploadService/CopyFile"
+	FileUploadService_UpdateFileMetadata_FullMethodName    = "/pb.FileUploadService/UpdateFileMetadata"
+	FileUploadService_CreateTenant_FullMethodName          = "/pb.FileUploadService/CreateTenant"
+	FileUploadService_ListTenants_FullMethodName           = "/pb.FileUploadService/ListTenants"
+	FileUploadService_GetTenant_FullMethodName             = "/pb.FileUploadService/GetTenant"
+	FileUploadService_UpdateTenant_FullMethodName          = "/pb.FileUploadService/UpdateTenant"
+	FileUploadService_SearchFiles_FullMethodName           = "/pb.FileUploadService/SearchFiles"
+	FileUploadService_DownloadArchive_FullMethodName       = "/pb.FileUploadService/DownloadArchive"
+	FileUploadService_ImportFromURL_FullMethodName         = "/pb.FileUploadService/ImportFromURL"
+	FileUploadService_WatchUpload_FullMethodName           = "/pb.FileUploadService/WatchUpload"
+	FileUploadService_CreateWebhook_FullMethodName         = "/pb.FileUploadService/CreateWebhook"
+	FileUploadService_ListWebhooks_FullMethodName          = "/pb.FileUploadService/ListWebhooks"
+	FileUploadService_DeleteWebhook_FullMethodName         = "/pb.FileUploadService/DeleteWebhook"
+	FileUploadService_ListWebhookDeliveries_FullMethodName = "/pb.FileUploadService/ListWebhookDeliveries"
+	FileUploadService_RedeliverWebhook_FullMethodName      = "/pb.FileUploadService/RedeliverWebhook"
 )
 
 // FileUploadServiceClient is the client API for FileUploadService service.
@@ -37,6 +78,69 @@ type FileUploadServiceClient interface {
 	DownloadFile(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (*DownloadResponse, error)
 	GetUploadMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*UploadMetadata, error)
 	DeleteFile(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	CreateSignedURL(ctx context.Context, in *CreateSignedURLRequest, opts ...grpc.CallOption) (*CreateSignedURLResponse, error)
+	DownloadSigned(ctx context.Context, in *SignedDownloadRequest, opts ...grpc.CallOption) (*DownloadResponse, error)
+	ShareFile(ctx context.Context, in *ShareFileRequest, opts ...grpc.CallOption) (*ShareGrant, error)
+	UnshareFile(ctx context.Context, in *UnshareFileRequest, opts ...grpc.CallOption) (*UnshareFileResponse, error)
+	ListGrants(ctx context.Context, in *ListGrantsRequest, opts ...grpc.CallOption) (*ListGrantsResponse, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// Chunk-level deduplication: the client splits files with content-defined
+	// chunking, uploads only the chunks the server reports missing and then
+	// commits the file as an ordered list of chunk hashes
+	FindMissingChunks(ctx context.Context, in *FindMissingChunksRequest, opts ...grpc.CallOption) (*FindMissingChunksResponse, error)
+	PutChunks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ContentChunk, PutChunksResponse], error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*FileVersion, error)
+	CommitManifest(ctx context.Context, in *CommitManifestRequest, opts ...grpc.CallOption) (*UploadStatus, error)
+	// Trash: DeleteFile moves files here until they are restored or purged
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	RestoreFile(ctx context.Context, in *RestoreFileRequest, opts ...grpc.CallOption) (*TrashItem, error)
+	PurgeFile(ctx context.Context, in *PurgeFileRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Retention policies and legal holds, managed by admins
+	CreateRetentionPolicy(ctx context.Context, in *CreateRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error)
+	ListRetentionPolicies(ctx context.Context, in *ListRetentionPoliciesRequest, opts ...grpc.CallOption) (*ListRetentionPoliciesResponse, error)
+	AssignRetentionPolicy(ctx context.Context, in *AssignRetentionPolicyRequest, opts ...grpc.CallOption) (*AssignRetentionPolicyResponse, error)
+	SetLegalHold(ctx context.Context, in *SetLegalHoldRequest, opts ...grpc.CallOption) (*LegalHold, error)
+	// Folders: a per-user virtual directory tree over uploaded files
+	CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*Folder, error)
+	RenameFolder(ctx context.Context, in *RenameFolderRequest, opts ...grpc.CallOption) (*Folder, error)
+	MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*Folder, error)
+	DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	ListFolder(ctx context.Context, in *ListFolderRequest, opts ...grpc.CallOption) (*ListFolderResponse, error)
+	LookupPath(ctx context.Context, in *LookupPathRequest, opts ...grpc.CallOption) (*LookupPathResponse, error)
+	// Metadata-only changes and server-side copies of uploaded files
+	RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*FileEntry, error)
+	MoveFile(ctx context.Context, in *MoveFileRequest, opts ...grpc.CallOption) (*FileEntry, error)
+	CopyFile(ctx context.Context, in *CopyFileRequest, opts ...grpc.CallOption) (*FileEntry, error)
+	UpdateFileMetadata(ctx context.Context, in *UpdateFileMetadataRequest, opts ...grpc.CallOption) (*FileEntry, error)
+	// Tenants, managed by admins of the default tenant
+	CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*Tenant, error)
+	ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error)
+	GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*Tenant, error)
+	UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*Tenant, error)
+	// Full-text search over the files the caller owns or can read
+	SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (*SearchFilesResponse, error)
+	// Streams a ZIP or tar.gz of several files or a folder, built while it
+	// is sent. The gateway serves it as raw bytes at /v1/archives.
+	DownloadArchive(ctx context.Context, in *DownloadArchiveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArchiveChunk], error)
+	// Creates an upload whose content the server fetches from a URL in the
+	// background; GetUploadMetadata reports the progress
+	ImportFromURL(ctx context.Context, in *ImportFromURLRequest, opts ...grpc.CallOption) (*ImportFromURLResponse, error)
+	// Streams the progress and outcome of an upload as it happens on any
+	// server. The first event describes the current state; the stream ends
+	// after a completed or failed event.
+	WatchUpload(ctx context.Context, in *WatchUploadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UploadEvent], error)
+	// Subscribes an HTTP endpoint to upload.completed, upload.failed and
+	// file.deleted events of the caller's files, or of the whole tenant for
+	// administrators. The signing secret is only returned here.
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Returns the deliveries of a webhook, newest first; status "dead"
+	// lists the dead letters that exhausted their retries
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	// Queues a delivery, typically a dead letter, to be sent again
+	RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*WebhookDelivery, error)
 }
 
 type fileUploadServiceClient struct {
@@ -110,6 +214,437 @@ func (c *fileUploadServiceClient) DeleteFile(ctx context.Context, in *DeleteRequ
 	return out, nil
 }
 
+func (c *fileUploadServiceClient) CreateSignedURL(ctx context.Context, in *CreateSignedURLRequest, opts ...grpc.CallOption) (*CreateSignedURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSignedURLResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_CreateSignedURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) DownloadSigned(ctx context.Context, in *SignedDownloadRequest, opts ...grpc.CallOption) (*DownloadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DownloadResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_DownloadSigned_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ShareFile(ctx context.Context, in *ShareFileRequest, opts ...grpc.CallOption) (*ShareGrant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShareGrant)
+	err := c.cc.Invoke(ctx, FileUploadService_ShareFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) UnshareFile(ctx context.Context, in *UnshareFileRequest, opts ...grpc.CallOption) (*UnshareFileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnshareFileResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_UnshareFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListGrants(ctx context.Context, in *ListGrantsRequest, opts ...grpc.CallOption) (*ListGrantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGrantsResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListGrants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) FindMissingChunks(ctx context.Context, in *FindMissingChunksRequest, opts ...grpc.CallOption) (*FindMissingChunksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindMissingChunksResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_FindMissingChunks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) PutChunks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ContentChunk, PutChunksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileUploadService_ServiceDesc.Streams[1], FileUploadService_PutChunks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ContentChunk, PutChunksResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUploadService_PutChunksClient = grpc.ClientStreamingClient[ContentChunk, PutChunksResponse]
+
+func (c *fileUploadServiceClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) RestoreVersion(ctx context.Context, in *RestoreVersionRequest, opts ...grpc.CallOption) (*FileVersion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileVersion)
+	err := c.cc.Invoke(ctx, FileUploadService_RestoreVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) CommitManifest(ctx context.Context, in *CommitManifestRequest, opts ...grpc.CallOption) (*UploadStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadStatus)
+	err := c.cc.Invoke(ctx, FileUploadService_CommitManifest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) RestoreFile(ctx context.Context, in *RestoreFileRequest, opts ...grpc.CallOption) (*TrashItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TrashItem)
+	err := c.cc.Invoke(ctx, FileUploadService_RestoreFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) PurgeFile(ctx context.Context, in *PurgeFileRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_PurgeFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) CreateRetentionPolicy(ctx context.Context, in *CreateRetentionPolicyRequest, opts ...grpc.CallOption) (*RetentionPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetentionPolicy)
+	err := c.cc.Invoke(ctx, FileUploadService_CreateRetentionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListRetentionPolicies(ctx context.Context, in *ListRetentionPoliciesRequest, opts ...grpc.CallOption) (*ListRetentionPoliciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRetentionPoliciesResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListRetentionPolicies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) AssignRetentionPolicy(ctx context.Context, in *AssignRetentionPolicyRequest, opts ...grpc.CallOption) (*AssignRetentionPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignRetentionPolicyResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_AssignRetentionPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) SetLegalHold(ctx context.Context, in *SetLegalHoldRequest, opts ...grpc.CallOption) (*LegalHold, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LegalHold)
+	err := c.cc.Invoke(ctx, FileUploadService_SetLegalHold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) CreateFolder(ctx context.Context, in *CreateFolderRequest, opts ...grpc.CallOption) (*Folder, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Folder)
+	err := c.cc.Invoke(ctx, FileUploadService_CreateFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) RenameFolder(ctx context.Context, in *RenameFolderRequest, opts ...grpc.CallOption) (*Folder, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Folder)
+	err := c.cc.Invoke(ctx, FileUploadService_RenameFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) MoveFolder(ctx context.Context, in *MoveFolderRequest, opts ...grpc.CallOption) (*Folder, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Folder)
+	err := c.cc.Invoke(ctx, FileUploadService_MoveFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) DeleteFolder(ctx context.Context, in *DeleteFolderRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_DeleteFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListFolder(ctx context.Context, in *ListFolderRequest, opts ...grpc.CallOption) (*ListFolderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFolderResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListFolder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) LookupPath(ctx context.Context, in *LookupPathRequest, opts ...grpc.CallOption) (*LookupPathResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupPathResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_LookupPath_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) RenameFile(ctx context.Context, in *RenameFileRequest, opts ...grpc.CallOption) (*FileEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileEntry)
+	err := c.cc.Invoke(ctx, FileUploadService_RenameFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) MoveFile(ctx context.Context, in *MoveFileRequest, opts ...grpc.CallOption) (*FileEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileEntry)
+	err := c.cc.Invoke(ctx, FileUploadService_MoveFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) CopyFile(ctx context.Context, in *CopyFileRequest, opts ...grpc.CallOption) (*FileEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileEntry)
+	err := c.cc.Invoke(ctx, FileUploadService_CopyFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) UpdateFileMetadata(ctx context.Context, in *UpdateFileMetadataRequest, opts ...grpc.CallOption) (*FileEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FileEntry)
+	err := c.cc.Invoke(ctx, FileUploadService_UpdateFileMetadata_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) CreateTenant(ctx context.Context, in *CreateTenantRequest, opts ...grpc.CallOption) (*Tenant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tenant)
+	err := c.cc.Invoke(ctx, FileUploadService_CreateTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTenantsResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListTenants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*Tenant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tenant)
+	err := c.cc.Invoke(ctx, FileUploadService_GetTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) UpdateTenant(ctx context.Context, in *UpdateTenantRequest, opts ...grpc.CallOption) (*Tenant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tenant)
+	err := c.cc.Invoke(ctx, FileUploadService_UpdateTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) SearchFiles(ctx context.Context, in *SearchFilesRequest, opts ...grpc.CallOption) (*SearchFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchFilesResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_SearchFiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) DownloadArchive(ctx context.Context, in *DownloadArchiveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArchiveChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileUploadService_ServiceDesc.Streams[2], FileUploadService_DownloadArchive_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadArchiveRequest, ArchiveChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUploadService_DownloadArchiveClient = grpc.ServerStreamingClient[ArchiveChunk]
+
+func (c *fileUploadServiceClient) ImportFromURL(ctx context.Context, in *ImportFromURLRequest, opts ...grpc.CallOption) (*ImportFromURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportFromURLResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ImportFromURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) WatchUpload(ctx context.Context, in *WatchUploadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UploadEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FileUploadService_ServiceDesc.Streams[3], FileUploadService_WatchUpload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchUploadRequest, UploadEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUploadService_WatchUploadClient = grpc.ServerStreamingClient[UploadEvent]
+
+func (c *fileUploadServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, FileUploadService_CreateWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_DeleteWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, FileUploadService_ListWebhookDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileUploadServiceClient) RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*WebhookDelivery, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WebhookDelivery)
+	err := c.cc.Invoke(ctx, FileUploadService_RedeliverWebhook_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileUploadServiceServer is the server API for FileUploadService service.
 // All implementations must embed UnimplementedFileUploadServiceServer
 // for forward compatibility.
@@ -120,148 +655,1047 @@ type FileUploadServiceServer interface {
 	DownloadFile(context.Context, *DownloadRequest) (*DownloadResponse, error)
 	GetUploadMetadata(context.Context, *GetMetadataRequest) (*UploadMetadata, error)
 	DeleteFile(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	CreateSignedURL(context.Context, *CreateSignedURLRequest) (*CreateSignedURLResponse, error)
+	DownloadSigned(context.Context, *SignedDownloadRequest) (*DownloadResponse, error)
+	ShareFile(context.Context, *ShareFileRequest) (*ShareGrant, error)
+	UnshareFile(context.Context, *UnshareFileRequest) (*UnshareFileResponse, error)
+	ListGrants(context.Context, *ListGrantsRequest) (*ListGrantsResponse, error)
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	// Chunk-level deduplication: the client splits files with content-defined
+	// chunking, uploads only the chunks the server reports missing and then
+	// commits the file as an ordered list of chunk hashes
+	FindMissingChunks(context.Context, *FindMissingChunksRequest) (*FindMissingChunksResponse, error)
+	PutChunks(grpc.ClientStreamingServer[ContentChunk, PutChunksResponse]) error
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	RestoreVersion(context.Context, *RestoreVersionRequest) (*FileVersion, error)
+	CommitManifest(context.Context, *CommitManifestRequest) (*UploadStatus, error)
+	// Trash: DeleteFile moves files here until they are restored or purged
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	RestoreFile(context.Context, *RestoreFileRequest) (*TrashItem, error)
+	PurgeFile(context.Context, *PurgeFileRequest) (*DeleteResponse, error)
+	// Retention policies and legal holds, managed by admins
+	CreateRetentionPolicy(context.Context, *CreateRetentionPolicyRequest) (*RetentionPolicy, error)
+	ListRetentionPolicies(context.Context, *ListRetentionPoliciesRequest) (*ListRetentionPoliciesResponse, error)
+	AssignRetentionPolicy(context.Context, *AssignRetentionPolicyRequest) (*AssignRetentionPolicyResponse, error)
+	SetLegalHold(context.Context, *SetLegalHoldRequest) (*LegalHold, error)
+	// Folders: a per-user virtual directory tree over uploaded files
+	CreateFolder(context.Context, *CreateFolderRequest) (*Folder, error)
+	RenameFolder(context.Context, *RenameFolderRequest) (*Folder, error)
+	MoveFolder(context.Context, *MoveFolderRequest) (*Folder, error)
+	DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteResponse, error)
+	ListFolder(context.Context, *ListFolderRequest) (*ListFolderResponse, error)
+	LookupPath(context.Context, *LookupPathRequest) (*LookupPathResponse, error)
+	// Metadata-only changes and server-side copies of uploaded files
+	RenameFile(context.Context, *RenameFileRequest) (*FileEntry, error)
+	MoveFile(context.Context, *MoveFileRequest) (*FileEntry, error)
+	CopyFile(context.Context, *CopyFileRequest) (*FileEntry, error)
+	UpdateFileMetadata(context.Context, *UpdateFileMetadataRequest) (*FileEntry, error)
+	// Tenants, managed by admins of the default tenant
+	CreateTenant(context.Context, *CreateTenantRequest) (*Tenant, error)
+	ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error)
+	GetTenant(context.Context, *GetTenantRequest) (*Tenant, error)
+	UpdateTenant(context.Context, *UpdateTenantRequest) (*Tenant, error)
+	// Full-text search over the files the caller owns or can read
+	SearchFiles(context.Context, *SearchFilesRequest) (*SearchFilesResponse, error)
+	// Streams a ZIP or tar.gz of several files or a folder, built while it
+	// is sent. The gateway serves it as raw bytes at /v1/archives.
+	DownloadArchive(*DownloadArchiveRequest, grpc.ServerStreamingServer[ArchiveChunk]) error
+	// Creates an upload whose content the server fetches from a URL in the
+	// background; GetUploadMetadata reports the progress
+	ImportFromURL(context.Context, *ImportFromURLRequest) (*ImportFromURLResponse, error)
+	// Streams the progress and outcome of an upload as it happens on any
+	// server. The first event describes the current state; the stream ends
+	// after a completed or failed event.
+	WatchUpload(*WatchUploadRequest, grpc.ServerStreamingServer[UploadEvent]) error
+	// Subscribes an HTTP endpoint to upload.completed, upload.failed and
+	// file.deleted events of the caller's files, or of the whole tenant for
+	// administrators. The signing secret is only returned here.
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteResponse, error)
+	// Returns the deliveries of a webhook, newest first; status "dead"
+	// lists the dead letters that exhausted their retries
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	// Queues a delivery, typically a dead letter, to be sent again
+	RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*WebhookDelivery, error)
+	mustEmbedUnimplementedFileUploadServiceServer()
+}
+
+// UnimplementedFileUploadServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFileUploadServiceServer struct{}
+
+func (UnimplementedFileUploadServiceServer) InitUpload(context.Context, *InitRequest) (*InitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InitUpload not implemented")
+}
+func (UnimplementedFileUploadServiceServer) UploadFile(grpc.ClientStreamingServer[FileChunk, UploadStatus]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) GetUploadedChunks(context.Context, *GetChunksRequest) (*GetChunksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadedChunks not implemented")
+}
+func (UnimplementedFileUploadServiceServer) DownloadFile(context.Context, *DownloadRequest) (*DownloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) GetUploadMetadata(context.Context, *GetMetadataRequest) (*UploadMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUploadMetadata not implemented")
+}
+func (UnimplementedFileUploadServiceServer) DeleteFile(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) CreateSignedURL(context.Context, *CreateSignedURLRequest) (*CreateSignedURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSignedURL not implemented")
+}
+func (UnimplementedFileUploadServiceServer) DownloadSigned(context.Context, *SignedDownloadRequest) (*DownloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadSigned not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ShareFile(context.Context, *ShareFileRequest) (*ShareGrant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShareFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) UnshareFile(context.Context, *UnshareFileRequest) (*UnshareFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnshareFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListGrants(context.Context, *ListGrantsRequest) (*ListGrantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGrants not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedFileUploadServiceServer) FindMissingChunks(context.Context, *FindMissingChunksRequest) (*FindMissingChunksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindMissingChunks not implemented")
+}
+func (UnimplementedFileUploadServiceServer) PutChunks(grpc.ClientStreamingServer[ContentChunk, PutChunksResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PutChunks not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedFileUploadServiceServer) RestoreVersion(context.Context, *RestoreVersionRequest) (*FileVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreVersion not implemented")
+}
+func (UnimplementedFileUploadServiceServer) CommitManifest(context.Context, *CommitManifestRequest) (*UploadStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitManifest not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedFileUploadServiceServer) RestoreFile(context.Context, *RestoreFileRequest) (*TrashItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) PurgeFile(context.Context, *PurgeFileRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) CreateRetentionPolicy(context.Context, *CreateRetentionPolicyRequest) (*RetentionPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRetentionPolicy not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListRetentionPolicies(context.Context, *ListRetentionPoliciesRequest) (*ListRetentionPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRetentionPolicies not implemented")
+}
+func (UnimplementedFileUploadServiceServer) AssignRetentionPolicy(context.Context, *AssignRetentionPolicyRequest) (*AssignRetentionPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRetentionPolicy not implemented")
+}
+func (UnimplementedFileUploadServiceServer) SetLegalHold(context.Context, *SetLegalHoldRequest) (*LegalHold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLegalHold not implemented")
+}
+func (UnimplementedFileUploadServiceServer) CreateFolder(context.Context, *CreateFolderRequest) (*Folder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFolder not implemented")
+}
+func (UnimplementedFileUploadServiceServer) RenameFolder(context.Context, *RenameFolderRequest) (*Folder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFolder not implemented")
+}
+func (UnimplementedFileUploadServiceServer) MoveFolder(context.Context, *MoveFolderRequest) (*Folder, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveFolder not implemented")
+}
+func (UnimplementedFileUploadServiceServer) DeleteFolder(context.Context, *DeleteFolderRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFolder not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListFolder(context.Context, *ListFolderRequest) (*ListFolderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFolder not implemented")
+}
+func (UnimplementedFileUploadServiceServer) LookupPath(context.Context, *LookupPathRequest) (*LookupPathResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupPath not implemented")
+}
+func (UnimplementedFileUploadServiceServer) RenameFile(context.Context, *RenameFileRequest) (*FileEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) MoveFile(context.Context, *MoveFileRequest) (*FileEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) CopyFile(context.Context, *CopyFileRequest) (*FileEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyFile not implemented")
+}
+func (UnimplementedFileUploadServiceServer) UpdateFileMetadata(context.Context, *UpdateFileMetadataRequest) (*FileEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFileMetadata not implemented")
+}
+func (UnimplementedFileUploadServiceServer) CreateTenant(context.Context, *CreateTenantRequest) (*Tenant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTenant not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenants not implemented")
+}
+func (UnimplementedFileUploadServiceServer) GetTenant(context.Context, *GetTenantRequest) (*Tenant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenant not implemented")
+}
+func (UnimplementedFileUploadServiceServer) UpdateTenant(context.Context, *UpdateTenantRequest) (*Tenant, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTenant not implemented")
+}
+func (UnimplementedFileUploadServiceServer) SearchFiles(context.Context, *SearchFilesRequest) (*SearchFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchFiles not implemented")
+}
+func (UnimplementedFileUploadServiceServer) DownloadArchive(*DownloadArchiveRequest, grpc.ServerStreamingServer[ArchiveChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadArchive not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ImportFromURL(context.Context, *ImportFromURLRequest) (*ImportFromURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportFromURL not implemented")
+}
+func (UnimplementedFileUploadServiceServer) WatchUpload(*WatchUploadRequest, grpc.ServerStreamingServer[UploadEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchUpload not implemented")
+}
+func (UnimplementedFileUploadServiceServer) CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (UnimplementedFileUploadServiceServer) DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+func (UnimplementedFileUploadServiceServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedFileUploadServiceServer) RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*WebhookDelivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeliverWebhook not implemented")
+}
+func (UnimplementedFileUploadServiceServer) mustEmbedUnimplementedFileUploadServiceServer() {}
+func (UnimplementedFileUploadServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeFileUploadServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FileUploadServiceServer will
+// result in compilation errors.
+type UnsafeFileUploadServiceServer interface {
 	mustEmbedUnimplementedFileUploadServiceServer()
 }
 
-// UnimplementedFileUploadServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedFileUploadServiceServer struct{}
+func RegisterFileUploadServiceServer(s grpc.ServiceRegistrar, srv FileUploadServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFileUploadServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FileUploadService_ServiceDesc, srv)
+}
+
+func _FileUploadService_InitUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).InitUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_InitUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).InitUpload(ctx, req.(*InitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileUploadServiceServer).UploadFile(&grpc.GenericServerStream[FileChunk, UploadStatus]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUploadService_UploadFileServer = grpc.ClientStreamingServer[FileChunk, UploadStatus]
+
+func _FileUploadService_GetUploadedChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChunksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).GetUploadedChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_GetUploadedChunks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).GetUploadedChunks(ctx, req.(*GetChunksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_DownloadFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).DownloadFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_DownloadFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).DownloadFile(ctx, req.(*DownloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_GetUploadMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).GetUploadMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_GetUploadMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).GetUploadMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_DeleteFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).DeleteFile(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_CreateSignedURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSignedURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).CreateSignedURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_CreateSignedURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).CreateSignedURL(ctx, req.(*CreateSignedURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_DownloadSigned_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedDownloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).DownloadSigned(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_DownloadSigned_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).DownloadSigned(ctx, req.(*SignedDownloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_ShareFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShareFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ShareFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ShareFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ShareFile(ctx, req.(*ShareFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_UnshareFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnshareFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).UnshareFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_UnshareFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).UnshareFile(ctx, req.(*UnshareFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_ListGrants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGrantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ListGrants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ListGrants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ListGrants(ctx, req.(*ListGrantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_FindMissingChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindMissingChunksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).FindMissingChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_FindMissingChunks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).FindMissingChunks(ctx, req.(*FindMissingChunksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_PutChunks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileUploadServiceServer).PutChunks(&grpc.GenericServerStream[ContentChunk, PutChunksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUploadService_PutChunksServer = grpc.ClientStreamingServer[ContentChunk, PutChunksResponse]
+
+func _FileUploadService_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ListVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_RestoreVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).RestoreVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_RestoreVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).RestoreVersion(ctx, req.(*RestoreVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_CommitManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitManifestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).CommitManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_CommitManifest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).CommitManifest(ctx, req.(*CommitManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_RestoreFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).RestoreFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_RestoreFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).RestoreFile(ctx, req.(*RestoreFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_PurgeFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).PurgeFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_PurgeFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).PurgeFile(ctx, req.(*PurgeFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_CreateRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRetentionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).CreateRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_CreateRetentionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).CreateRetentionPolicy(ctx, req.(*CreateRetentionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_ListRetentionPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRetentionPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ListRetentionPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ListRetentionPolicies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ListRetentionPolicies(ctx, req.(*ListRetentionPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_AssignRetentionPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRetentionPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).AssignRetentionPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_AssignRetentionPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).AssignRetentionPolicy(ctx, req.(*AssignRetentionPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_SetLegalHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLegalHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).SetLegalHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_SetLegalHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).SetLegalHold(ctx, req.(*SetLegalHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_CreateFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).CreateFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_CreateFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).CreateFolder(ctx, req.(*CreateFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_RenameFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).RenameFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_RenameFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).RenameFolder(ctx, req.(*RenameFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_MoveFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).MoveFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_MoveFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).MoveFolder(ctx, req.(*MoveFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_DeleteFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).DeleteFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_DeleteFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).DeleteFolder(ctx, req.(*DeleteFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_ListFolder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFolderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ListFolder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ListFolder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ListFolder(ctx, req.(*ListFolderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_LookupPath_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupPathRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).LookupPath(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_LookupPath_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).LookupPath(ctx, req.(*LookupPathRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_RenameFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).RenameFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_RenameFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).RenameFile(ctx, req.(*RenameFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_MoveFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).MoveFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_MoveFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).MoveFile(ctx, req.(*MoveFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func (UnimplementedFileUploadServiceServer) InitUpload(context.Context, *InitRequest) (*InitResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method InitUpload not implemented")
+func _FileUploadService_CopyFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).CopyFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_CopyFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).CopyFile(ctx, req.(*CopyFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedFileUploadServiceServer) UploadFile(grpc.ClientStreamingServer[FileChunk, UploadStatus]) error {
-	return status.Errorf(codes.Unimplemented, "method UploadFile not implemented")
+
+func _FileUploadService_UpdateFileMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFileMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).UpdateFileMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_UpdateFileMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).UpdateFileMetadata(ctx, req.(*UpdateFileMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedFileUploadServiceServer) GetUploadedChunks(context.Context, *GetChunksRequest) (*GetChunksResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUploadedChunks not implemented")
+
+func _FileUploadService_CreateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).CreateTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_CreateTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).CreateTenant(ctx, req.(*CreateTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedFileUploadServiceServer) DownloadFile(context.Context, *DownloadRequest) (*DownloadResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DownloadFile not implemented")
+
+func _FileUploadService_ListTenants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).ListTenants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_ListTenants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).ListTenants(ctx, req.(*ListTenantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedFileUploadServiceServer) GetUploadMetadata(context.Context, *GetMetadataRequest) (*UploadMetadata, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetUploadMetadata not implemented")
+
+func _FileUploadService_GetTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).GetTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_GetTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).GetTenant(ctx, req.(*GetTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedFileUploadServiceServer) DeleteFile(context.Context, *DeleteRequest) (*DeleteResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+
+func _FileUploadService_UpdateTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).UpdateTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_UpdateTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).UpdateTenant(ctx, req.(*UpdateTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedFileUploadServiceServer) mustEmbedUnimplementedFileUploadServiceServer() {}
-func (UnimplementedFileUploadServiceServer) testEmbeddedByValue()                           {}
 
-// UnsafeFileUploadServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to FileUploadServiceServer will
-// result in compilation errors.
-type UnsafeFileUploadServiceServer interface {
-	mustEmbedUnimplementedFileUploadServiceServer()
+func _FileUploadService_SearchFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).SearchFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_SearchFiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).SearchFiles(ctx, req.(*SearchFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterFileUploadServiceServer(s grpc.ServiceRegistrar, srv FileUploadServiceServer) {
-	// If the following call pancis, it indicates UnimplementedFileUploadServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func _FileUploadService_DownloadArchive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadArchiveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	s.RegisterService(&FileUploadService_ServiceDesc, srv)
+	return srv.(FileUploadServiceServer).DownloadArchive(m, &grpc.GenericServerStream[DownloadArchiveRequest, ArchiveChunk]{ServerStream: stream})
 }
 
-func _FileUploadService_InitUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InitRequest)
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FileUploadService_DownloadArchiveServer = grpc.ServerStreamingServer[ArchiveChunk]
+
+func _FileUploadService_ImportFromURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportFromURLRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FileUploadServiceServer).InitUpload(ctx, in)
+		return srv.(FileUploadServiceServer).ImportFromURL(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: FileUploadService_InitUpload_FullMethodName,
+		FullMethod: FileUploadService_ImportFromURL_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileUploadServiceServer).InitUpload(ctx, req.(*InitRequest))
+		return srv.(FileUploadServiceServer).ImportFromURL(ctx, req.(*ImportFromURLRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FileUploadService_UploadFile_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(FileUploadServiceServer).UploadFile(&grpc.GenericServerStream[FileChunk, UploadStatus]{ServerStream: stream})
+func _FileUploadService_WatchUpload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchUploadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileUploadServiceServer).WatchUpload(m, &grpc.GenericServerStream[WatchUploadRequest, UploadEvent]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type FileUploadService_UploadFileServer = grpc.ClientStreamingServer[FileChunk, UploadStatus]
+type FileUploadService_WatchUploadServer = grpc.ServerStreamingServer[UploadEvent]
 
-func _FileUploadService_GetUploadedChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetChunksRequest)
+func _FileUploadService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FileUploadServiceServer).GetUploadedChunks(ctx, in)
+		return srv.(FileUploadServiceServer).CreateWebhook(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: FileUploadService_GetUploadedChunks_FullMethodName,
+		FullMethod: FileUploadService_CreateWebhook_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileUploadServiceServer).GetUploadedChunks(ctx, req.(*GetChunksRequest))
+		return srv.(FileUploadServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FileUploadService_DownloadFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DownloadRequest)
+func _FileUploadService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FileUploadServiceServer).DownloadFile(ctx, in)
+		return srv.(FileUploadServiceServer).ListWebhooks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: FileUploadService_DownloadFile_FullMethodName,
+		FullMethod: FileUploadService_ListWebhooks_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileUploadServiceServer).DownloadFile(ctx, req.(*DownloadRequest))
+		return srv.(FileUploadServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FileUploadService_GetUploadMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMetadataRequest)
+func _FileUploadService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FileUploadServiceServer).GetUploadMetadata(ctx, in)
+		return srv.(FileUploadServiceServer).DeleteWebhook(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: FileUploadService_GetUploadMetadata_FullMethodName,
+		FullMethod: FileUploadService_DeleteWebhook_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileUploadServiceServer).GetUploadMetadata(ctx, req.(*GetMetadataRequest))
+		return srv.(FileUploadServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FileUploadService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteRequest)
+func _FileUploadService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FileUploadServiceServer).DeleteFile(ctx, in)
+		return srv.(FileUploadServiceServer).ListWebhookDeliveries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: FileUploadService_DeleteFile_FullMethodName,
+		FullMethod: FileUploadService_ListWebhookDeliveries_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FileUploadServiceServer).DeleteFile(ctx, req.(*DeleteRequest))
+		return srv.(FileUploadServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileUploadService_RedeliverWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileUploadServiceServer).RedeliverWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FileUploadService_RedeliverWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileUploadServiceServer).RedeliverWebhook(ctx, req.(*RedeliverWebhookRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -293,6 +1727,158 @@ var FileUploadService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteFile",
 			Handler:    _FileUploadService_DeleteFile_Handler,
 		},
+		{
+			MethodName: "CreateSignedURL",
+			Handler:    _FileUploadService_CreateSignedURL_Handler,
+		},
+		{
+			MethodName: "DownloadSigned",
+			Handler:    _FileUploadService_DownloadSigned_Handler,
+		},
+		{
+			MethodName: "ShareFile",
+			Handler:    _FileUploadService_ShareFile_Handler,
+		},
+		{
+			MethodName: "UnshareFile",
+			Handler:    _FileUploadService_UnshareFile_Handler,
+		},
+		{
+			MethodName: "ListGrants",
+			Handler:    _FileUploadService_ListGrants_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _FileUploadService_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "FindMissingChunks",
+			Handler:    _FileUploadService_FindMissingChunks_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _FileUploadService_ListVersions_Handler,
+		},
+		{
+			MethodName: "RestoreVersion",
+			Handler:    _FileUploadService_RestoreVersion_Handler,
+		},
+		{
+			MethodName: "CommitManifest",
+			Handler:    _FileUploadService_CommitManifest_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _FileUploadService_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreFile",
+			Handler:    _FileUploadService_RestoreFile_Handler,
+		},
+		{
+			MethodName: "PurgeFile",
+			Handler:    _FileUploadService_PurgeFile_Handler,
+		},
+		{
+			MethodName: "CreateRetentionPolicy",
+			Handler:    _FileUploadService_CreateRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "ListRetentionPolicies",
+			Handler:    _FileUploadService_ListRetentionPolicies_Handler,
+		},
+		{
+			MethodName: "AssignRetentionPolicy",
+			Handler:    _FileUploadService_AssignRetentionPolicy_Handler,
+		},
+		{
+			MethodName: "SetLegalHold",
+			Handler:    _FileUploadService_SetLegalHold_Handler,
+		},
+		{
+			MethodName: "CreateFolder",
+			Handler:    _FileUploadService_CreateFolder_Handler,
+		},
+		{
+			MethodName: "RenameFolder",
+			Handler:    _FileUploadService_RenameFolder_Handler,
+		},
+		{
+			MethodName: "MoveFolder",
+			Handler:    _FileUploadService_MoveFolder_Handler,
+		},
+		{
+			MethodName: "DeleteFolder",
+			Handler:    _FileUploadService_DeleteFolder_Handler,
+		},
+		{
+			MethodName: "ListFolder",
+			Handler:    _FileUploadService_ListFolder_Handler,
+		},
+		{
+			MethodName: "LookupPath",
+			Handler:    _FileUploadService_LookupPath_Handler,
+		},
+		{
+			MethodName: "RenameFile",
+			Handler:    _FileUploadService_RenameFile_Handler,
+		},
+		{
+			MethodName: "MoveFile",
+			Handler:    _FileUploadService_MoveFile_Handler,
+		},
+		{
+			MethodName: "CopyFile",
+			Handler:    _FileUploadService_CopyFile_Handler,
+		},
+		{
+			MethodName: "UpdateFileMetadata",
+			Handler:    _FileUploadService_UpdateFileMetadata_Handler,
+		},
+		{
+			MethodName: "CreateTenant",
+			Handler:    _FileUploadService_CreateTenant_Handler,
+		},
+		{
+			MethodName: "ListTenants",
+			Handler:    _FileUploadService_ListTenants_Handler,
+		},
+		{
+			MethodName: "GetTenant",
+			Handler:    _FileUploadService_GetTenant_Handler,
+		},
+		{
+			MethodName: "UpdateTenant",
+			Handler:    _FileUploadService_UpdateTenant_Handler,
+		},
+		{
+			MethodName: "SearchFiles",
+			Handler:    _FileUploadService_SearchFiles_Handler,
+		},
+		{
+			MethodName: "ImportFromURL",
+			Handler:    _FileUploadService_ImportFromURL_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _FileUploadService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _FileUploadService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _FileUploadService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _FileUploadService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhook",
+			Handler:    _FileUploadService_RedeliverWebhook_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -300,6 +1886,21 @@ var FileUploadService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _FileUploadService_UploadFile_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "PutChunks",
+			Handler:       _FileUploadService_PutChunks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadArchive",
+			Handler:       _FileUploadService_DownloadArchive_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchUpload",
+			Handler:       _FileUploadService_WatchUpload_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "fileupload.proto",
 }